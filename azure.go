@@ -0,0 +1,54 @@
+package openai
+
+import (
+	"net/url"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// An AzureOpt is a functional option for configuring Azure OpenAI mode.
+type AzureOpt func(*service.Azure)
+
+// WithDeployment maps a model name to an Azure deployment name. Models without
+// a mapping are used as deployment names.
+func WithDeployment(model, deployment string) AzureOpt {
+	return func(a *service.Azure) {
+		if a.Deployments == nil {
+			a.Deployments = make(map[string]string)
+		}
+
+		a.Deployments[model] = deployment
+	}
+}
+
+// WithTokenProvider authenticates with bearer tokens (such as Microsoft Entra
+// ID tokens) from a CredentialProvider, instead of the "api-key" header. Use a
+// RefreshingTokenProvider to refresh tokens before they expire.
+func WithTokenProvider(provider CredentialProvider) AzureOpt {
+	return func(a *service.Azure) {
+		a.TokenProvider = provider
+	}
+}
+
+// WithAzure configures the Client to call Azure OpenAI.
+//
+// The endpoint is the Azure OpenAI resource endpoint, such as
+// "https://my-resource.openai.azure.com". The model argument of
+// deployment-scoped requests (such as chat completions and embeddings) is
+// mapped to a deployment, and requests are routed to
+// "/openai/deployments/{deployment}/...?api-version={apiVersion}".
+//
+// The Client's key (or credential) is sent in the "api-key" header, unless a
+// token provider is configured (see WithTokenProvider).
+func WithAzure(endpoint *url.URL, apiVersion string, opts ...AzureOpt) ClientOpt {
+	return func(c *Client) {
+		azure := &service.Azure{APIVersion: apiVersion}
+
+		for _, opt := range opts {
+			opt(azure)
+		}
+
+		c.baseURL = endpoint.JoinPath("openai")
+		c.azure = azure
+	}
+}
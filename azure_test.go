@@ -0,0 +1,115 @@
+package openai_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var azureEndpoint = &url.URL{Scheme: "https", Host: "my-resource.openai.azure.com"}
+
+func recordingDoer(reqs *[]*http.Request, body string) httptesting.DoerFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		*reqs = append(*reqs, req)
+
+		r := &http.Response{}
+		r.StatusCode = http.StatusOK
+		r.Body = httptesting.NewTestBody(strings.NewReader(body))
+
+		return r, nil
+	}
+}
+
+func TestClient_Azure(t *testing.T) {
+	t.Parallel()
+
+	var reqs []*http.Request
+
+	c := openai.NewClient(
+		openai.WithKey("azure-key"),
+		openai.WithDoer(recordingDoer(&reqs, `{}`)),
+		openai.WithAzure(azureEndpoint, "2024-10-21",
+			openai.WithDeployment("text-embedding-3-small", "embeddings-prod")),
+	)
+
+	_, err := c.Chat.CreateCompletion(context.Background(), "gpt-4o",
+		[]chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hi"))})
+	require.NoError(t, err)
+
+	_, err = c.Embeddings.Create(context.Background(), "text-embedding-3-small", []string{"Hi"})
+	require.NoError(t, err)
+
+	_, err = c.Chat.CreateCompletion(context.Background(), "gpt-4o",
+		[]chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hi"))},
+		chat.WithAPIKey("per-call-key"))
+	require.NoError(t, err)
+
+	_, err = c.Files.Retrieve(context.Background(), "file-1")
+	require.NoError(t, err)
+
+	require.Len(t, reqs, 4)
+
+	assert.Equal(t,
+		"https://my-resource.openai.azure.com/openai/deployments/gpt-4o/chat/completions?api-version=2024-10-21",
+		reqs[0].URL.String())
+	assert.Equal(t, "azure-key", reqs[0].Header.Get("api-key"))
+	assert.Empty(t, reqs[0].Header.Get("Authorization"))
+
+	assert.Equal(t,
+		"https://my-resource.openai.azure.com/openai/deployments/embeddings-prod/embeddings?api-version=2024-10-21",
+		reqs[1].URL.String())
+	assert.Equal(t, "azure-key", reqs[1].Header.Get("api-key"))
+
+	assert.Equal(t, "per-call-key", reqs[2].Header.Get("api-key"))
+	assert.Empty(t, reqs[2].Header.Get("Authorization"))
+
+	assert.Equal(t,
+		"https://my-resource.openai.azure.com/openai/files/file-1?api-version=2024-10-21",
+		reqs[3].URL.String())
+}
+
+func TestClient_AzureTokenProvider(t *testing.T) {
+	t.Parallel()
+
+	var (
+		reqs    []*http.Request
+		fetches int
+	)
+
+	provider := openai.NewRefreshingTokenProvider(func(context.Context) (string, time.Time, error) {
+		fetches++
+
+		// The first token is already within the refresh leeway.
+		if fetches == 1 {
+			return "token-1", time.Now().Add(time.Minute), nil
+		}
+
+		return "token-2", time.Now().Add(time.Hour), nil
+	})
+
+	c := openai.NewClient(
+		openai.WithDoer(recordingDoer(&reqs, `{}`)),
+		openai.WithAzure(azureEndpoint, "2024-10-21", openai.WithTokenProvider(provider)),
+	)
+
+	for range 3 {
+		_, err := c.Chat.CreateCompletion(context.Background(), "gpt-4o",
+			[]chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hi"))})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, fetches)
+	assert.Equal(t, "Bearer token-1", reqs[0].Header.Get("Authorization"))
+	assert.Equal(t, "Bearer token-2", reqs[1].Header.Get("Authorization"))
+	assert.Equal(t, "Bearer token-2", reqs[2].Header.Get("Authorization"))
+	assert.Empty(t, reqs[2].Header.Get("api-key"))
+}
@@ -0,0 +1,189 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// A CredentialProvider provides the credential (an API key or bearer token)
+// used to authenticate a request. It is consulted on every request, with the
+// request's context, so it may select a credential per tenant from values in
+// the context.
+type CredentialProvider = service.CredentialProvider
+
+// A CredentialProviderFunc is a function that implements CredentialProvider.
+type CredentialProviderFunc func(ctx context.Context) (string, error)
+
+// Credential implements CredentialProvider.
+func (f CredentialProviderFunc) Credential(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// ErrNoCredential is returned by a credential provider that has no credential
+// to provide.
+var ErrNoCredential = errors.New("no credential")
+
+// DefaultTokenRefreshLeeway is how long before a credential expires a
+// RefreshingTokenProvider refreshes it.
+const DefaultTokenRefreshLeeway = 5 * time.Minute
+
+// A RefreshingTokenProvider caches a credential until shortly before it
+// expires, and then fetches a new one. It is safe for concurrent use, and
+// fetches at most one credential at a time.
+type RefreshingTokenProvider struct {
+	fetch  func(ctx context.Context) (credential string, expiresAt time.Time, err error)
+	leeway time.Duration
+
+	mu         sync.Mutex
+	credential string
+	expiresAt  time.Time
+}
+
+// A RefreshingTokenOpt is a functional option for configuring a
+// RefreshingTokenProvider.
+type RefreshingTokenOpt func(*RefreshingTokenProvider)
+
+// WithRefreshLeeway sets how long before a credential expires it is
+// refreshed. The default is DefaultTokenRefreshLeeway.
+func WithRefreshLeeway(leeway time.Duration) RefreshingTokenOpt {
+	return func(p *RefreshingTokenProvider) {
+		p.leeway = leeway
+	}
+}
+
+// NewRefreshingTokenProvider creates a RefreshingTokenProvider that
+// fetches credentials with fetch.
+//
+// A credential with a zero expiresAt never expires, but can be discarded with
+// Invalidate.
+func NewRefreshingTokenProvider(
+	fetch func(ctx context.Context) (credential string, expiresAt time.Time, err error),
+	opts ...RefreshingTokenOpt,
+) *RefreshingTokenProvider {
+	p := &RefreshingTokenProvider{fetch: fetch, leeway: DefaultTokenRefreshLeeway}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Credential implements CredentialProvider.
+func (p *RefreshingTokenProvider) Credential(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.credential != "" && (p.expiresAt.IsZero() || time.Now().Add(p.leeway).Before(p.expiresAt)) {
+		return p.credential, nil
+	}
+
+	credential, expiresAt, err := p.fetch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error fetching credential: %w", err)
+	}
+
+	p.credential, p.expiresAt = credential, expiresAt
+
+	return credential, nil
+}
+
+// Invalidate discards the cached credential, so that the next request fetches
+// a new one (for example, after a request fails with 401 Unauthorized).
+func (p *RefreshingTokenProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.credential = ""
+}
+
+// NewEnvCredentialProvider creates a CredentialProvider that reads the
+// environment variable name on every request. It returns ErrNoCredential if
+// the variable is unset or empty.
+func NewEnvCredentialProvider(name string) CredentialProvider {
+	return CredentialProviderFunc(func(context.Context) (string, error) {
+		if v := os.Getenv(name); v != "" {
+			return v, nil
+		}
+
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrNoCredential, name)
+	})
+}
+
+// DefaultFileCheckInterval is how often a FileCredentialProvider checks its
+// file for changes.
+const DefaultFileCheckInterval = 10 * time.Second
+
+// A FileCredentialProvider provides a credential read from a file, such as a
+// mounted secret, and re-reads the file when it changes. Surrounding
+// whitespace is trimmed from the file's contents.
+//
+// The file is not watched. Instead, on each request made at least the check
+// interval after the previous check, the file is checked with os.Stat, and
+// re-read if its modification time or size changed. It is safe for concurrent
+// use.
+type FileCredentialProvider struct {
+	path     string
+	interval time.Duration
+
+	mu         sync.Mutex
+	credential string
+	checkedAt  time.Time
+	modTime    time.Time
+	size       int64
+}
+
+// NewFileCredentialProvider creates a FileCredentialProvider that reads the
+// file at path, checking it for changes every interval. If interval is zero,
+// DefaultFileCheckInterval is used; if it is negative, the file is checked on
+// every request.
+func NewFileCredentialProvider(path string, interval time.Duration) *FileCredentialProvider {
+	if interval == 0 {
+		interval = DefaultFileCheckInterval
+	}
+
+	return &FileCredentialProvider{path: path, interval: interval}
+}
+
+// Credential implements CredentialProvider.
+func (p *FileCredentialProvider) Credential(context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.credential != "" && now.Sub(p.checkedAt) < p.interval {
+		return p.credential, nil
+	}
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return "", fmt.Errorf("error checking credential file: %w", err)
+	}
+
+	p.checkedAt = now
+
+	if p.credential != "" && info.ModTime().Equal(p.modTime) && info.Size() == p.size {
+		return p.credential, nil
+	}
+
+	b, err := os.ReadFile(p.path)
+	if err != nil {
+		return "", fmt.Errorf("error reading credential file: %w", err)
+	}
+
+	credential := string(bytes.TrimSpace(b))
+	if credential == "" {
+		return "", fmt.Errorf("%w: credential file %s is empty", ErrNoCredential, p.path)
+	}
+
+	p.credential, p.modTime, p.size = credential, info.ModTime(), info.Size()
+
+	return credential, nil
+}
@@ -0,0 +1,158 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshingTokenProvider(t *testing.T) {
+	t.Parallel()
+
+	fetches := 0
+	provider := openai.NewRefreshingTokenProvider(func(context.Context) (string, time.Time, error) {
+		fetches++
+
+		return "key", time.Now().Add(time.Hour), nil
+	}, openai.WithRefreshLeeway(30*time.Minute))
+
+	for range 3 {
+		key, err := provider.Credential(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "key", key)
+	}
+
+	assert.Equal(t, 1, fetches)
+
+	provider.Invalidate()
+
+	_, err := provider.Credential(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, fetches)
+}
+
+func TestRefreshingTokenProvider_Error(t *testing.T) {
+	t.Parallel()
+
+	errFetch := errors.New("fetch failed")
+	provider := openai.NewRefreshingTokenProvider(func(context.Context) (string, time.Time, error) {
+		return "", time.Time{}, errFetch
+	})
+
+	c := openai.NewClient(
+		openai.WithCredentialProvider(provider),
+		openai.WithDoer(recordingDoer(&[]*http.Request{}, `{}`)),
+	)
+
+	_, err := c.Models.List(context.Background())
+	require.ErrorIs(t, err, errFetch)
+}
+
+func TestEnvCredentialProvider(t *testing.T) {
+	t.Setenv("OPENAI_GO_TEST_KEY", "env-key")
+
+	provider := openai.NewEnvCredentialProvider("OPENAI_GO_TEST_KEY")
+
+	key, err := provider.Credential(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "env-key", key)
+
+	t.Setenv("OPENAI_GO_TEST_KEY", "")
+
+	_, err = provider.Credential(context.Background())
+	require.ErrorIs(t, err, openai.ErrNoCredential)
+}
+
+func TestFileCredentialProvider(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(path, []byte("key-1\n"), 0o600))
+
+	// A negative interval checks the file on every request.
+	provider := openai.NewFileCredentialProvider(path, -1)
+
+	key, err := provider.Credential(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", key)
+
+	require.NoError(t, os.WriteFile(path, []byte("key-22\n"), 0o600))
+
+	key, err = provider.Credential(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "key-22", key)
+
+	require.NoError(t, os.Remove(path))
+
+	_, err = provider.Credential(context.Background())
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+type tenantKey struct{}
+
+func TestClient_CredentialProviderPerTenant(t *testing.T) {
+	t.Parallel()
+
+	var reqs []*http.Request
+
+	keys := map[string]string{"acme": "acme-key", "globex": "globex-key"}
+	provider := openai.CredentialProviderFunc(func(ctx context.Context) (string, error) {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+
+		return keys[tenant], nil
+	})
+
+	c := openai.NewClient(
+		openai.WithCredentialProvider(provider),
+		openai.WithDoer(recordingDoer(&reqs, `{"object":"list","data":[]}`)),
+	)
+
+	for _, tenant := range []string{"acme", "globex"} {
+		_, err := c.Models.List(context.WithValue(context.Background(), tenantKey{}, tenant))
+		require.NoError(t, err)
+	}
+
+	_, err := c.Chat.CreateCompletion(context.Background(), "gpt-4",
+		[]chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hi"))},
+		chat.WithAPIKey("override-key"))
+	require.NoError(t, err)
+
+	require.Len(t, reqs, 3)
+	assert.Equal(t, "Bearer acme-key", reqs[0].Header.Get("Authorization"))
+	assert.Equal(t, "Bearer globex-key", reqs[1].Header.Get("Authorization"))
+	assert.Equal(t, "Bearer override-key", reqs[2].Header.Get("Authorization"))
+}
+
+func TestClient_CredentialProviderSkippedForPerRequestKey(t *testing.T) {
+	t.Parallel()
+
+	var reqs []*http.Request
+
+	errFetch := errors.New("fetch failed")
+	provider := openai.CredentialProviderFunc(func(context.Context) (string, error) {
+		return "", errFetch
+	})
+
+	c := openai.NewClient(
+		openai.WithCredentialProvider(provider),
+		openai.WithDoer(recordingDoer(&reqs, `{}`)),
+	)
+
+	// The failing provider isn't consulted when a per-request key is given.
+	_, err := c.Chat.CreateCompletion(context.Background(), "gpt-4",
+		[]chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hi"))},
+		chat.WithAPIKey("override-key"))
+	require.NoError(t, err)
+
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "Bearer override-key", reqs[0].Header.Get("Authorization"))
+}
@@ -1,8 +1,9 @@
 module github.com/jclem/openai-go
 
-go 1.21.0
+go 1.23.0
 
 require (
+	github.com/coder/websocket v1.8.15
 	github.com/jclem/sseparser v0.4.0
 	github.com/stretchr/testify v1.8.4
 )
@@ -0,0 +1,26 @@
+package openai
+
+import "github.com/jclem/openai-go/internal/service"
+
+// An Instrumenter observes model operations (chat completions, streaming chat
+// completions and embeddings), for tracing and metrics. See the otel
+// subpackage for an OpenTelemetry implementation.
+type Instrumenter = service.Instrumenter
+
+// An OperationObserver observes a single operation started by an
+// Instrumenter.
+type OperationObserver = service.OperationObserver
+
+// An Operation describes a model operation.
+type Operation = service.Operation
+
+// An OperationResult describes the outcome of a model operation.
+type OperationResult = service.OperationResult
+
+// WithInstrumenter sets an Instrumenter that observes the Client's model
+// operations.
+func WithInstrumenter(instrumenter Instrumenter) ClientOpt {
+	return func(c *Client) {
+		c.instrumenter = instrumenter
+	}
+}
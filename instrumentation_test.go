@@ -0,0 +1,147 @@
+package openai_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeInstrumenter struct {
+	ops []*fakeObserver
+}
+
+type fakeObserver struct {
+	op     openai.Operation
+	events []string
+	result openai.OperationResult
+	err    error
+}
+
+type opKey struct{}
+
+func (f *fakeInstrumenter) StartOperation(
+	ctx context.Context,
+	op openai.Operation,
+) (context.Context, openai.OperationObserver) {
+	obs := &fakeObserver{op: op}
+	f.ops = append(f.ops, obs)
+
+	return context.WithValue(ctx, opKey{}, obs), obs
+}
+
+func (o *fakeObserver) FirstToken() { o.events = append(o.events, "first_token") }
+
+func (o *fakeObserver) End(result openai.OperationResult, err error) {
+	o.events = append(o.events, "end")
+	o.result, o.err = result, err
+}
+
+func TestWithInstrumenter(t *testing.T) {
+	t.Parallel()
+
+	inst := &fakeInstrumenter{}
+
+	var reqs []*http.Request
+
+	c := openai.NewClient(
+		openai.WithInstrumenter(inst),
+		openai.WithDoer(recordingDoer(&reqs, `{"id":"cmpl-1","model":"gpt-4-0613",`+
+			`"choices":[{"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":2}}`)),
+	)
+
+	_, err := c.Chat.CreateCompletion(context.Background(), "gpt-4", hi(), chat.WithTemperature(0.5))
+	require.NoError(t, err)
+
+	require.Len(t, inst.ops, 1)
+	obs := inst.ops[0]
+	assert.Equal(t, "chat", obs.op.Name)
+	assert.Equal(t, "gpt-4", obs.op.RequestModel)
+	assert.Equal(t, "api.openai.com", obs.op.ServerAddress)
+	assert.False(t, obs.op.Stream)
+	assert.InDelta(t, 0.5, *obs.op.Temperature, 0)
+	assert.Equal(t, []string{"end"}, obs.events)
+	require.NoError(t, obs.err)
+	assert.Equal(t, openai.OperationResult{
+		ResponseID:    "cmpl-1",
+		ResponseModel: "gpt-4-0613",
+		InputTokens:   5,
+		OutputTokens:  2,
+		FinishReasons: []string{"stop"},
+	}, obs.result)
+
+	// The operation's context is used for its request.
+	assert.Same(t, obs, reqs[0].Context().Value(opKey{}))
+}
+
+func TestWithInstrumenter_Stream(t *testing.T) {
+	t.Parallel()
+
+	inst := &fakeInstrumenter{}
+
+	c := openai.NewClient(
+		openai.WithInstrumenter(inst),
+		openai.WithDoer(recordingDoer(&[]*http.Request{}, ""+
+			"data: {\"id\":\"cmpl-1\",\"model\":\"gpt-4-0613\",\"choices\":[{\"delta\":{\"role\":\"assistant\"}}]}\n\n"+
+			"data: {\"id\":\"cmpl-1\",\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n"+
+			"data: {\"id\":\"cmpl-1\",\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n"+
+			"data: {\"id\":\"cmpl-1\",\"choices\":[],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":1}}\n\n"+
+			"data: [DONE]\n\n")),
+	)
+
+	stream, err := c.Chat.CreateStreamingCompletion(context.Background(), "gpt-4", hi(),
+		chat.WithStreamIncludeUsage())
+	require.NoError(t, err)
+
+	for {
+		_, err := stream.Next()
+		if errors.Is(err, chat.ErrStreamDone) {
+			break
+		}
+
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, stream.Close())
+
+	require.Len(t, inst.ops, 1)
+	obs := inst.ops[0]
+	assert.True(t, obs.op.Stream)
+	assert.Equal(t, []string{"first_token", "end"}, obs.events)
+	require.NoError(t, obs.err)
+	assert.Equal(t, openai.OperationResult{
+		ResponseID:    "cmpl-1",
+		ResponseModel: "gpt-4-0613",
+		InputTokens:   5,
+		OutputTokens:  1,
+		FinishReasons: []string{"stop"},
+	}, obs.result)
+}
+
+func TestWithInstrumenter_EmbeddingsError(t *testing.T) {
+	t.Parallel()
+
+	inst := &fakeInstrumenter{}
+
+	c := openai.NewClient(
+		openai.WithInstrumenter(inst),
+		openai.WithDoer(loggingDoer(http.StatusTooManyRequests, `{}`)),
+	)
+
+	_, err := c.Embeddings.Create(context.Background(), "text-embedding-3-small", []string{"Hi"})
+	require.Error(t, err)
+
+	require.Len(t, inst.ops, 1)
+	obs := inst.ops[0]
+	assert.Equal(t, "embeddings", obs.op.Name)
+	assert.Equal(t, []string{"end"}, obs.events)
+
+	var statusErr openai.UnexpectedStatusCodeError
+	require.ErrorAs(t, obs.err, &statusErr)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.Actual)
+}
@@ -39,3 +39,11 @@ func (TestBody) Close() error {
 func NewTestBody(r io.Reader) TestBody {
 	return TestBody{r}
 }
+
+// DoerFunc is a test HTTPDoer that calls a function for each request.
+type DoerFunc func(*http.Request) (*http.Response, error)
+
+// Do implements the HTTPDoer interface.
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Azure configures a Client to call Azure OpenAI deployments.
+type Azure struct {
+	// APIVersion is the value of the "api-version" query parameter.
+	APIVersion string
+
+	// Deployments maps model names to deployment names. Models without a
+	// mapping are used as deployment names.
+	Deployments map[string]string
+
+	// TokenProvider, if set, provides bearer tokens (such as Microsoft Entra
+	// ID tokens) instead of authenticating with the client's credential in
+	// the "api-key" header.
+	TokenProvider CredentialProvider
+}
+
+// deploymentPaths are the paths Azure serves under a deployment, rather than
+// at the root of the endpoint.
+var deploymentPaths = map[string]bool{
+	"/chat/completions":     true,
+	"/completions":          true,
+	"/embeddings":           true,
+	"/audio/speech":         true,
+	"/audio/transcriptions": true,
+	"/audio/translations":   true,
+	"/images/generations":   true,
+	"/images/edits":         true,
+	"/images/variations":    true,
+}
+
+func (a *Azure) deployment(model string) string {
+	if d, ok := a.Deployments[model]; ok {
+		return d
+	}
+
+	return model
+}
+
+// url returns the URL of a request to path, for the given model.
+func (a *Azure) url(baseURL *url.URL, path, model string) *url.URL {
+	if deploymentPaths[path] && model != "" {
+		path = "/deployments/" + url.PathEscape(a.deployment(model)) + path
+	}
+
+	u := baseURL.JoinPath(path)
+
+	q := u.Query()
+	q.Set("api-version", a.APIVersion)
+	u.RawQuery = q.Encode()
+
+	return u
+}
+
+// authorize replaces the bearer authorization of a request with Azure
+// authentication.
+//
+// A per-request key (set by WithAPIKey) is sent in the "api-key" header.
+// Otherwise, a token from the token provider is used if there is one, and the
+// client's credential if not.
+func (a *Azure) authorize(ctx context.Context, req *http.Request, perRequest bool) error {
+	key := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	req.Header.Del("Authorization")
+
+	if perRequest || a.TokenProvider == nil {
+		if key != "" {
+			req.Header.Set("api-key", key)
+		}
+
+		return nil
+	}
+
+	token, err := a.TokenProvider.Credential(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting Azure token: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return nil
+}
+
+// bodyModel returns the model of a request body, which is either a *Form or a
+// JSON-encoded object.
+func bodyModel(form *Form, encoded []byte) string {
+	if form != nil {
+		return form.value("model")
+	}
+
+	var v struct {
+		Model string `json:"model"`
+	}
+
+	// Bodies that aren't objects (or have no model) have no deployment.
+	_ = json.Unmarshal(encoded, &v)
+
+	return v.Model
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// CallOptions customize the requests made for a single call. They are carried
+// by the call's context, so that every service method accepts them.
+type CallOptions struct {
+	// Header is set on the request, replacing client and service headers.
+	Header http.Header
+
+	// Query is added to the request's query.
+	Query url.Values
+
+	// ExtraBody is merged into the request body, replacing fields the body
+	// already has. It is ignored for requests without a body.
+	ExtraBody map[string]any
+}
+
+func (o CallOptions) clone() CallOptions {
+	var query url.Values
+	if o.Query != nil {
+		query = make(url.Values, len(o.Query))
+
+		for key, values := range o.Query {
+			query[key] = slices.Clone(values)
+		}
+	}
+
+	return CallOptions{
+		Header:    o.Header.Clone(),
+		Query:     query,
+		ExtraBody: maps.Clone(o.ExtraBody),
+	}
+}
+
+type callOptionsKey struct{}
+
+// ContextWithCallOptions returns a copy of ctx carrying call options, which are
+// applied in addition to any call options ctx already carries.
+func ContextWithCallOptions(ctx context.Context, apply func(*CallOptions)) context.Context {
+	opts := CallOptionsFromContext(ctx).clone()
+	apply(&opts)
+
+	return context.WithValue(ctx, callOptionsKey{}, opts)
+}
+
+// CallOptionsFromContext returns the call options carried by ctx.
+func CallOptionsFromContext(ctx context.Context) CallOptions {
+	opts, _ := ctx.Value(callOptionsKey{}).(CallOptions)
+
+	return opts
+}
+
+// withExtraFields returns a copy of form with extra added as fields, replacing
+// plain fields with the same names. Non-string values are encoded as JSON.
+func withExtraFields(form *Form, extra map[string]any) (*Form, error) {
+	if len(extra) == 0 {
+		return form, nil
+	}
+
+	merged := &Form{parts: slices.Clone(form.parts)}
+
+	for _, name := range slices.Sorted(maps.Keys(extra)) {
+		value, ok := extra[name].(string)
+		if !ok {
+			b, err := json.Marshal(extra[name])
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode extra body field %q: %w", name, err)
+			}
+
+			value = string(b)
+		}
+
+		merged.parts = slices.DeleteFunc(merged.parts, func(p formPart) bool {
+			return p.name == name && p.reader == nil
+		})
+
+		merged.AddField(name, value)
+	}
+
+	return merged, nil
+}
+
+// withExtraJSON returns a JSON-encoded body with extra merged into it. The
+// body must encode as an object.
+func withExtraJSON(body json.RawMessage, extra map[string]any) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, fmt.Errorf("failed to merge extra body: body is not an object: %w", err)
+	}
+
+	if fields == nil {
+		fields = make(map[string]json.RawMessage, len(extra))
+	}
+
+	for name, value := range extra {
+		b, err := marshalJSON(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode extra body field %q: %w", name, err)
+		}
+
+		fields[name] = b
+	}
+
+	return fields, nil
+}
+
+// marshalJSON encodes v as JSON the way request bodies are encoded, without
+// escaping HTML or a trailing newline.
+func marshalJSON(v any) (json.RawMessage, error) {
+	b := &bytes.Buffer{}
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode body: %w", err)
+	}
+
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
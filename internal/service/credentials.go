@@ -0,0 +1,18 @@
+package service
+
+import "context"
+
+// A CredentialProvider provides the credential (an API key or bearer token)
+// used to authenticate a request. It is consulted on every request, with the
+// request's context.
+type CredentialProvider interface {
+	Credential(ctx context.Context) (string, error)
+}
+
+// staticCredential is a CredentialProvider that always provides the same key.
+type staticCredential string
+
+// Credential implements CredentialProvider.
+func (s staticCredential) Credential(context.Context) (string, error) {
+	return string(s), nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import "context"
+
+// An Instrumenter observes model operations (such as chat completions), for
+// tracing and metrics.
+type Instrumenter interface {
+	// StartOperation is called when an operation starts. The returned context
+	// is used for the operation's requests.
+	StartOperation(ctx context.Context, op Operation) (context.Context, OperationObserver)
+}
+
+// An OperationObserver observes a single operation.
+type OperationObserver interface {
+	// FirstToken is called when a streaming operation receives its first
+	// content.
+	FirstToken()
+
+	// End is called once, when the operation ends. For streaming operations,
+	// this is when the stream ends or is closed.
+	End(result OperationResult, err error)
+}
+
+// An Operation describes a model operation.
+type Operation struct {
+	// Name is the operation name, such as "chat" or "embeddings".
+	Name string
+
+	// RequestModel is the model named in the request.
+	RequestModel string
+
+	// ServerAddress is the host of the API.
+	ServerAddress string
+
+	// Stream is whether the operation streams its response.
+	Stream bool
+
+	// Temperature, TopP and MaxTokens are the request's sampling parameters,
+	// if set.
+	Temperature *float64
+	TopP        *float64
+	MaxTokens   *int
+}
+
+// An OperationResult describes the outcome of a model operation. Fields the
+// response does not include are zero.
+type OperationResult struct {
+	ResponseID    string
+	ResponseModel string
+	InputTokens   int
+	OutputTokens  int
+	FinishReasons []string
+}
+
+// WithInstrumenter sets the client's instrumenter.
+func WithInstrumenter(instrumenter Instrumenter) Option {
+	return func(c *Client) {
+		c.instrumenter = instrumenter
+	}
+}
+
+// StartOperation starts observing an operation with the client's
+// instrumenter, if it has one.
+func (c *Client) StartOperation(ctx context.Context, op Operation) (context.Context, OperationObserver) {
+	if c.instrumenter == nil {
+		return ctx, noopObserver{}
+	}
+
+	op.ServerAddress = c.baseURL.Host
+
+	return c.instrumenter.StartOperation(ctx, op)
+}
+
+type noopObserver struct{}
+
+func (noopObserver) FirstToken()                {}
+func (noopObserver) End(OperationResult, error) {}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// A Request is an API request passing through a client's middleware.
+type Request struct {
+	// Endpoint is the API path of the request (such as "/chat/completions"),
+	// relative to the client's base URL.
+	Endpoint string
+
+	// Model is the model named in the request body, if any.
+	Model string
+
+	// Body is the request body given by the service (usually a request
+	// struct, or a *Form), before it was encoded. Changing it does not change
+	// the request; use SetBody.
+	Body any
+
+	// HTTPRequest is the HTTP request that will be sent.
+	HTTPRequest *http.Request
+
+	// Attempt is the number of times the request has been sent. It is
+	// incremented each time the request reaches the end of the middleware
+	// chain, so middleware that retries a request by calling the next handler
+	// again can read it after the call.
+	Attempt int
+}
+
+// Context returns the request's context.
+func (r *Request) Context() context.Context {
+	return r.HTTPRequest.Context()
+}
+
+// DecodeBody decodes the request body into v, by encoding it as JSON. This
+// allows middleware to inspect request structs whose fields are unexported.
+func (r *Request) DecodeBody(v any) error {
+	b, err := json.Marshal(r.Body)
+	if err != nil {
+		return fmt.Errorf("failed to encode body: %w", err)
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("failed to decode body: %w", err)
+	}
+
+	return nil
+}
+
+// SetBody replaces the request body (and Model, if the new body names one).
+// The call's extra body fields (see CallOptions.ExtraBody) are merged into the
+// new body when it is encoded. The request URL is not changed.
+func (r *Request) SetBody(body any) error {
+	enc, err := encodeBody(body, CallOptionsFromContext(r.Context()).ExtraBody)
+	if err != nil {
+		return err
+	}
+
+	r.Body = body
+	r.Model = bodyModel(enc.form, enc.json)
+
+	r.HTTPRequest.Body, r.HTTPRequest.GetBody, r.HTTPRequest.ContentLength = nil, nil, 0
+	r.HTTPRequest.Header.Del("Content-Type")
+
+	if enc.reader == nil {
+		return nil
+	}
+
+	r.HTTPRequest.Header.Set("Content-Type", enc.contentType)
+	r.HTTPRequest.Body = io.NopCloser(enc.reader)
+
+	if enc.json != nil {
+		r.HTTPRequest.ContentLength = int64(len(enc.json))
+		r.HTTPRequest.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(enc.json)), nil
+		}
+	}
+
+	return nil
+}
+
+// ErrBodyNotRewindable is returned when middleware retries a request whose
+// body cannot be rewound, such as a streamed multipart/form-data body.
+var ErrBodyNotRewindable = errors.New("request body cannot be rewound for a retry")
+
+// ErrResponseWritten is returned when middleware retries a request whose
+// response has already been partly copied to an io.Writer.
+var ErrResponseWritten = errors.New("response body already written for a retry")
+
+// prepareAttempt counts an attempt to send the request, and rewinds its body
+// if it has already been sent.
+func (r *Request) prepareAttempt() error {
+	r.Attempt++
+
+	if r.Attempt == 1 || r.HTTPRequest.Body == nil || r.HTTPRequest.Body == http.NoBody {
+		return nil
+	}
+
+	if r.HTTPRequest.GetBody == nil {
+		return fmt.Errorf("%w: %s", ErrBodyNotRewindable, r.Endpoint)
+	}
+
+	body, err := r.HTTPRequest.GetBody()
+	if err != nil {
+		return fmt.Errorf("failed to rewind request body: %w", err)
+	}
+
+	r.HTTPRequest.Body = body
+
+	return nil
+}
+
+// A Response is an API response passing through a client's middleware.
+type Response struct {
+	// HTTPResponse is the HTTP response.
+	HTTPResponse *http.Response
+
+	// Body is the value the response body was decoded into (usually a
+	// pointer to a response struct). It is nil if the body has not been
+	// read, such as for streaming responses, whose body is read by the
+	// service.
+	//
+	// Middleware that responds without calling the next handler may leave
+	// Body nil, and the JSON body of HTTPResponse is decoded for the service.
+	Body any
+}
+
+// A Handler handles a Request.
+type Handler func(*Request) (*Response, error)
+
+// A Middleware wraps a Handler, to inspect or change requests and responses.
+type Middleware func(next Handler) Handler
+
+type requestInfoKey struct{}
+
+// requestInfo is what NewRequestWithContext records about a request, for
+// middleware.
+type requestInfo struct {
+	endpoint string
+	model    string
+	body     any
+}
+
+func newRequest(req *http.Request) *Request {
+	info, ok := req.Context().Value(requestInfoKey{}).(requestInfo)
+	if !ok {
+		info.endpoint = req.URL.Path
+	}
+
+	return &Request{
+		Endpoint:    info.endpoint,
+		Model:       info.model,
+		Body:        info.body,
+		HTTPRequest: req,
+	}
+}
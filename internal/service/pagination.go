@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// A Page is a single page of a cursor-paginated list.
+//
+// Not every list endpoint returns FirstID and LastID, and some endpoints are
+// not paginated at all, in which case HasMore is always false.
+type Page[T any] struct {
+	Object  string `json:"object"`
+	Data    []T    `json:"data"`
+	FirstID string `json:"first_id,omitempty"`
+	LastID  string `json:"last_id,omitempty"`
+	HasMore bool   `json:"has_more"`
+}
+
+// A ListOpt is a functional option for configuring a list request's query.
+type ListOpt func(url.Values)
+
+// WithLimit sets the maximum number of items in a page.
+func WithLimit(limit int) ListOpt {
+	return func(q url.Values) {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+}
+
+// WithOrder sets the sort order by creation time ("asc" or "desc").
+func WithOrder(order string) ListOpt {
+	return func(q url.Values) {
+		q.Set("order", order)
+	}
+}
+
+// WithAfter sets the cursor to list items after.
+func WithAfter(after string) ListOpt {
+	return func(q url.Values) {
+		q.Set("after", after)
+	}
+}
+
+// WithBefore sets the cursor to list items before.
+func WithBefore(before string) ListOpt {
+	return func(q url.Values) {
+		q.Set("before", before)
+	}
+}
+
+// WithListParam sets an arbitrary query parameter on a list request.
+func WithListParam(key, value string) ListOpt {
+	return func(q url.Values) {
+		q.Set(key, value)
+	}
+}
+
+// ListPage fetches a single page from a list endpoint.
+func ListPage[T any](ctx context.Context, c *Client, path string, opts ...ListOpt) (*Page[T], error) {
+	query := url.Values{}
+
+	for _, opt := range opts {
+		opt(query)
+	}
+
+	return listPage[T](ctx, c, path, query)
+}
+
+func listPage[T any](ctx context.Context, c *Client, path string, query url.Values) (*Page[T], error) {
+	req, err := c.NewRequestWithContext(ctx, http.MethodGet, path, nil, WithQuery(query))
+	if err != nil {
+		return nil, fmt.Errorf("error creating list request: %w", err)
+	}
+
+	var page Page[T]
+	if _, err := c.Do(req, &page); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing list request: %w", err)
+	}
+
+	return &page, nil
+}
+
+// A Pager follows the cursors of a list endpoint, fetching one page at a
+// time.
+//
+// If the "before" cursor is set, the pager pages backward using each page's
+// first ID. Otherwise it pages forward using each page's last ID.
+type Pager[T any] struct {
+	client *Client
+	path   string
+	query  url.Values
+	idOf   func(T) string
+	done   bool
+}
+
+// NewPager creates a new Pager.
+//
+// The idOf function returns the ID of an item. It is used as the cursor for
+// endpoints that do not return FirstID and LastID, and may be nil otherwise.
+func NewPager[T any](c *Client, path string, idOf func(T) string, opts ...ListOpt) *Pager[T] {
+	query := url.Values{}
+
+	for _, opt := range opts {
+		opt(query)
+	}
+
+	return &Pager[T]{client: c, path: path, query: query, idOf: idOf}
+}
+
+// Done reports whether the last page has been fetched.
+func (p *Pager[T]) Done() bool {
+	return p.done
+}
+
+// NextPage fetches the next page. It returns nil and no error once the last
+// page has been fetched.
+func (p *Pager[T]) NextPage(ctx context.Context) (*Page[T], error) {
+	if p.done {
+		return nil, nil //nolint: nilnil // No more pages is not an error.
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error fetching next page: %w", err)
+	}
+
+	page, err := listPage[T](ctx, p.client, p.path, p.query)
+	if err != nil {
+		return nil, err
+	}
+
+	backward := p.query.Has("before") && !p.query.Has("after")
+
+	cursor := page.LastID
+	if backward {
+		cursor = page.FirstID
+	}
+
+	if cursor == "" && p.idOf != nil && len(page.Data) > 0 {
+		if backward {
+			cursor = p.idOf(page.Data[0])
+		} else {
+			cursor = p.idOf(page.Data[len(page.Data)-1])
+		}
+	}
+
+	if !page.HasMore || cursor == "" {
+		p.done = true
+
+		return page, nil
+	}
+
+	if backward {
+		p.query.Set("before", cursor)
+	} else {
+		p.query.Set("after", cursor)
+	}
+
+	return page, nil
+}
+
+// All returns an iterator over every item on every remaining page.
+//
+// Iteration stops at the first error, which is yielded with the zero value of
+// T.
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for !p.done {
+			page, err := p.NextPage(ctx)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+
+				return
+			}
+
+			if page == nil {
+				return
+			}
+
+			for _, item := range page.Data {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
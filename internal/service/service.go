@@ -8,8 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
 )
 
 // A Doer is an interface for performing HTTP requests.
@@ -34,12 +40,34 @@ func (e UnexpectedStatusCodeError) Error() string {
 
 // A Client is a struct used by services to make HTTP requests.
 type Client struct {
-	baseURL *url.URL
-	key     string
-	doer    Doer
+	baseURL      *url.URL
+	credentials  CredentialProvider
+	doer         Doer
+	header       http.Header
+	azure        *Azure
+	middleware   []Middleware
+	instrumenter Instrumenter
+}
+
+// WithHeader returns a copy of the client that sets a header on every request
+// it creates.
+func (c *Client) WithHeader(key, value string) *Client {
+	clone := *c
+	clone.header = c.header.Clone()
+
+	if clone.header == nil {
+		clone.header = make(http.Header)
+	}
+
+	clone.header.Set(key, value)
+
+	return &clone
 }
 
 // NewRequestWithContext creates a new HTTP request.
+//
+// If body is a *Form, it is streamed as a multipart/form-data body. Any other
+// non-nil body is encoded as JSON.
 func (c *Client) NewRequestWithContext(
 	ctx context.Context,
 	method,
@@ -47,74 +75,358 @@ func (c *Client) NewRequestWithContext(
 	body any,
 	opts ...RequestOpt,
 ) (*http.Request, error) {
-	u := c.baseURL.JoinPath(path)
+	call := CallOptionsFromContext(ctx)
 
-	var buf io.ReadWriter
-	if body != nil {
-		buf = &bytes.Buffer{}
-		enc := json.NewEncoder(buf)
-		enc.SetEscapeHTML(false)
+	enc, err := encodeBody(body, call.ExtraBody)
+	if err != nil {
+		return nil, err
+	}
 
-		if err := enc.Encode(body); err != nil {
-			return nil, fmt.Errorf("failed to encode body: %w", err)
-		}
+	model := bodyModel(enc.form, enc.json)
+
+	u := c.baseURL.JoinPath(path)
+	if c.azure != nil {
+		u = c.azure.url(c.baseURL, path, model)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), buf)
+	// Record what the request is for, for middleware (see Client.Do).
+	ctx = context.WithValue(ctx, requestInfoKey{}, requestInfo{endpoint: path, model: model, body: body})
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), enc.reader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	contentType := enc.contentType
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.key))
+	for key, values := range c.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
 
 	for _, opt := range opts {
 		opt(req)
 	}
 
+	for key, values := range call.Header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+
+	if len(call.Query) > 0 {
+		WithQuery(call.Query)(req)
+	}
+
+	// A per-request key (see WithAPIKey) takes precedence, so the credential
+	// provider is only consulted when there isn't one.
+	perRequest := req.Header.Get("Authorization") != ""
+	if !perRequest {
+		key, err := c.credentials.Credential(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credential: %w", err)
+		}
+
+		if key != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", key))
+		}
+	}
+
+	if c.azure != nil {
+		if err := c.azure.authorize(ctx, req, perRequest); err != nil {
+			return nil, err
+		}
+	}
+
 	return req, nil
 }
 
-// Do performs an HTTP request.
+// An encodedBody is a request body, encoded for sending.
+type encodedBody struct {
+	reader      io.Reader
+	contentType string
+	form        *Form
+	json        []byte
+}
+
+// encodeBody encodes a request body. A *Form is streamed as a
+// multipart/form-data body, and any other non-nil body is encoded as JSON.
+// Extra fields (see CallOptions.ExtraBody) are merged into the encoded body.
+func encodeBody(body any, extra map[string]any) (encodedBody, error) {
+	switch body := body.(type) {
+	case nil:
+		return encodedBody{}, nil
+	case *Form:
+		form, err := withExtraFields(body, extra)
+		if err != nil {
+			return encodedBody{}, err
+		}
+
+		fr := form.newReader()
+
+		return encodedBody{reader: fr, contentType: fr.mw.FormDataContentType(), form: form}, nil
+	default:
+		var v any = body
+
+		if len(extra) > 0 {
+			b, err := marshalJSON(body)
+			if err != nil {
+				return encodedBody{}, err
+			}
+
+			if v, err = withExtraJSON(b, extra); err != nil {
+				return encodedBody{}, err
+			}
+		}
+
+		b := &bytes.Buffer{}
+		enc := json.NewEncoder(b)
+		enc.SetEscapeHTML(false)
+
+		if err := enc.Encode(v); err != nil {
+			return encodedBody{}, fmt.Errorf("failed to encode body: %w", err)
+		}
+
+		return encodedBody{reader: b, contentType: "application/json", json: b.Bytes()}, nil
+	}
+}
+
+// Do performs an HTTP request, through the client's middleware.
 //
 // If v is nil, the response body is not closed, and the caller must close it.
+// If v is an io.Writer, the response body is copied to it as it is received;
+// middleware can't retry the request once part of it has been written.
 func (c *Client) Do(req *http.Request, v any) (*http.Response, error) {
-	resp, err := c.doer.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to perform request: %w", err)
+	target := v
+	if w, ok := v.(io.Writer); ok && len(c.middleware) > 0 {
+		target = &countingWriter{w: w}
+	}
+
+	h := c.send(target)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
 	}
 
-	if v != nil {
-		defer resp.Body.Close() //nolint: errcheck // No handling would be done here.
+	resp, err := h(newRequest(req))
+	if resp == nil {
+		return nil, err
 	}
 
-	if !(200 <= resp.StatusCode && resp.StatusCode <= 299) { //revive:disable-line:add-constant
-		return resp, UnexpectedStatusCodeError{
-			Expected: http.StatusOK,
-			Actual:   resp.StatusCode,
-			Response: resp,
+	// A middleware that responded without calling the next handler (such as
+	// a cache) may leave the response for Do to decode.
+	if err == nil && v != nil && resp.Body == nil && resp.HTTPResponse != nil {
+		defer resp.HTTPResponse.Body.Close() //nolint: errcheck // No handling would be done here.
+
+		err = decodeResponse(resp.HTTPResponse, target)
+	}
+
+	return resp.HTTPResponse, err
+}
+
+// A countingWriter counts the bytes written to w, so that a request isn't
+// retried once part of its response has been written.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer.
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+
+	return n, err //nolint: wrapcheck // Errors are the writer's.
+}
+
+// send returns the handler at the end of the middleware chain, which performs
+// the request and decodes its response into v.
+func (c *Client) send(v any) Handler {
+	return func(r *Request) (*Response, error) {
+		if cw, ok := v.(*countingWriter); ok && cw.n > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrResponseWritten, r.Endpoint)
+		}
+
+		if err := r.prepareAttempt(); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.doer.Do(r.HTTPRequest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to perform request: %w", err)
+		}
+
+		if v != nil {
+			defer resp.Body.Close() //nolint: errcheck // No handling would be done here.
 		}
+
+		if !(200 <= resp.StatusCode && resp.StatusCode <= 299) { //revive:disable-line:add-constant
+			bufferBody(resp)
+
+			return &Response{HTTPResponse: resp}, UnexpectedStatusCodeError{
+				Expected: http.StatusOK,
+				Actual:   resp.StatusCode,
+				Response: resp,
+			}
+		}
+
+		return &Response{HTTPResponse: resp, Body: v}, decodeResponse(resp, v)
 	}
+}
+
+// maxErrorBody is the most of an error response body that is buffered.
+const maxErrorBody = 1 << 20
 
+// bufferBody replaces the body of an error response with a buffered copy, so
+// that it can be read by middleware and callers after the original is closed.
+func bufferBody(resp *http.Response) {
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+}
+
+// decodeResponse decodes a response body into v, which may be nil (the body
+// is left unread), an io.Writer (the body is copied to it) or a JSON value.
+func decodeResponse(resp *http.Response, v any) error {
 	switch v := v.(type) {
 	case nil:
+		return nil
 	case io.Writer:
-		_, err = io.Copy(v, resp.Body)
+		_, err := io.Copy(v, resp.Body)
+
+		return err //nolint: wrapcheck // Decoding errors are returned unwrapped.
 	default:
-		decErr := json.NewDecoder(resp.Body).Decode(v)
-		if errors.Is(decErr, io.EOF) {
-			decErr = nil
+		err := json.NewDecoder(resp.Body).Decode(v)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		return err //nolint: wrapcheck // Decoding errors are returned unwrapped.
+	}
+}
+
+// A Form is a multipart/form-data request body.
+//
+// Files are streamed into the request body as it is read, so readers added to
+// a form are never buffered in memory.
+type Form struct {
+	parts []formPart
+}
+
+type formPart struct {
+	name     string
+	value    string
+	filename string
+	reader   io.Reader
+}
+
+// AddField adds a plain field to the form.
+func (f *Form) AddField(name, value string) {
+	f.parts = append(f.parts, formPart{name: name, value: value})
+}
+
+// AddFile adds a file field to the form.
+//
+// The part's content type is inferred from the filename's extension, and
+// defaults to "application/octet-stream".
+func (f *Form) AddFile(name, filename string, r io.Reader) {
+	f.parts = append(f.parts, formPart{name: name, filename: filename, reader: r})
+}
+
+// value returns the value of the first plain field with the given name.
+func (f *Form) value(name string) string {
+	for _, p := range f.parts {
+		if p.name == name && p.reader == nil {
+			return p.value
+		}
+	}
+
+	return ""
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func (f *Form) write(mw *multipart.Writer) error {
+	for _, part := range f.parts {
+		if part.reader == nil {
+			if err := mw.WriteField(part.name, part.value); err != nil {
+				return fmt.Errorf("failed to write form field %q: %w", part.name, err)
+			}
+
+			continue
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(part.filename))
+		if contentType == "" {
+			contentType = "application/octet-stream"
 		}
 
-		if decErr != nil {
-			err = decErr
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(part.name), quoteEscaper.Replace(part.filename)))
+		h.Set("Content-Type", contentType)
+
+		w, err := mw.CreatePart(h)
+		if err != nil {
+			return fmt.Errorf("failed to create form file %q: %w", part.name, err)
+		}
+
+		if _, err := io.Copy(w, part.reader); err != nil {
+			return fmt.Errorf("failed to write form file %q: %w", part.name, err)
 		}
 	}
 
-	return resp, err
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("failed to close form: %w", err)
+	}
+
+	return nil
+}
+
+func (f *Form) newReader() *formReader {
+	pr, pw := io.Pipe()
+
+	return &formReader{form: f, pr: pr, pw: pw, mw: multipart.NewWriter(pw)}
+}
+
+// A formReader streams a Form through a pipe. The writing goroutine is only
+// started on the first read, so a request that is never sent leaks nothing.
+type formReader struct {
+	form *Form
+	once sync.Once
+	pr   *io.PipeReader
+	pw   *io.PipeWriter
+	mw   *multipart.Writer
+}
+
+// Read implements the io.Reader interface.
+func (r *formReader) Read(p []byte) (int, error) {
+	r.once.Do(func() {
+		go func() {
+			r.pw.CloseWithError(r.form.write(r.mw)) //nolint: errcheck // Always returns nil.
+		}()
+	})
+
+	return r.pr.Read(p) //nolint: wrapcheck // Errors are passed through from the writer.
+}
+
+// Close implements the io.Closer interface.
+func (r *formReader) Close() error {
+	return r.pr.Close() //nolint: wrapcheck // Always returns nil.
+}
+
+// HTTPClient returns an *http.Client that performs requests with the client's
+// Doer, for libraries that require one (such as WebSocket dialers).
+func (c *Client) HTTPClient() *http.Client {
+	return &http.Client{Transport: doerTransport{c.doer}}
+}
+
+type doerTransport struct {
+	doer Doer
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t doerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.doer.Do(req) //nolint: wrapcheck // Errors are the Doer's.
 }
 
 // A RequestOpt is a functional option for configuring a Request.
@@ -132,18 +444,73 @@ func WithAPIKey(key string) RequestOpt {
 	}
 }
 
+// WithQuery adds query parameters to the request.
+func WithQuery(query url.Values) RequestOpt {
+	return func(r *http.Request) {
+		q := r.URL.Query()
+
+		for key, values := range query {
+			for _, value := range values {
+				q.Add(key, value)
+			}
+		}
+
+		r.URL.RawQuery = q.Encode()
+	}
+}
+
 // A Service is a common type for services, which wrap OpenAI API requests.
 type Service struct {
 	Client Client
 }
 
+// An Option is a functional option for configuring a Service's Client.
+type Option func(*Client)
+
+// WithCredentials sets the credential provider of the client, replacing its
+// static key.
+func WithCredentials(provider CredentialProvider) Option {
+	return func(c *Client) {
+		c.credentials = provider
+	}
+}
+
+// WithHeaders sets headers that the client sets on every request.
+func WithHeaders(header http.Header) Option {
+	return func(c *Client) {
+		c.header = header.Clone()
+	}
+}
+
+// WithMiddleware adds middleware around the client's requests. The first
+// middleware is the outermost.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// WithAzure configures the client to call Azure OpenAI deployments. The base
+// URL must be the "/openai" path of the Azure endpoint.
+func WithAzure(azure *Azure) Option {
+	return func(c *Client) {
+		c.azure = azure
+	}
+}
+
 // New creates a new Service.
-func New(baseURL *url.URL, key string, doer Doer) *Service {
-	return &Service{
+func New(baseURL *url.URL, key string, doer Doer, opts ...Option) *Service {
+	s := &Service{
 		Client: Client{
-			baseURL: baseURL,
-			key:     key,
-			doer:    doer,
+			baseURL:     baseURL,
+			credentials: staticCredential(key),
+			doer:        doer,
 		},
 	}
+
+	for _, opt := range opts {
+		opt(&s.Client)
+	}
+
+	return s
 }
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jclem/sseparser"
+)
+
+const streamDoneString = "[DONE]"
+
+// ErrStreamDone is returned when a stream is done (marked by "[DONE]").
+var ErrStreamDone = errors.New("completion stream is done")
+
+// A Stream reads server-sent events whose data fields are JSON objects of
+// type T, terminated by a "[DONE]" data field.
+type Stream[T any] struct {
+	closer  io.Closer
+	scanner *sseparser.StreamScanner
+}
+
+// NewStream creates a new Stream reading from rc.
+func NewStream[T any](rc io.ReadCloser) *Stream[T] {
+	return &Stream[T]{closer: rc, scanner: sseparser.NewStreamScanner(rc)}
+}
+
+type streamData[T any] struct {
+	value T
+}
+
+// UnmarshalSSEValue implements sseparser.UnmarshalerSSEValue.
+func (d *streamData[T]) UnmarshalSSEValue(v string) error {
+	if v == streamDoneString {
+		return ErrStreamDone
+	}
+
+	if err := json.Unmarshal([]byte(v), &d.value); err != nil {
+		return fmt.Errorf("error unmarshaling stream object: %w", err)
+	}
+
+	return nil
+}
+
+type streamEvent[T any] struct {
+	Data streamData[T] `sse:"data"`
+}
+
+// Next returns the next object in the stream.
+//
+// When the stream is complete, it returns ErrStreamDone.
+func (s *Stream[T]) Next() (*T, error) {
+	var evt streamEvent[T]
+
+	_, err := s.scanner.UnmarshalNext(&evt)
+	if err != nil {
+		if errors.Is(err, sseparser.ErrStreamEOF) {
+			return nil, fmt.Errorf("stream ended before [DONE]: %w", err)
+		}
+
+		if errors.Is(err, ErrStreamDone) {
+			return nil, ErrStreamDone
+		}
+
+		return nil, fmt.Errorf("error reading next object from stream: %w", err)
+	}
+
+	return &evt.Data.value, nil
+}
+
+// Close closes the stream.
+func (s *Stream[T]) Close() error {
+	if err := s.closer.Close(); err != nil {
+		return fmt.Errorf("error closing stream: %w", err)
+	}
+
+	return nil
+}
+
+// An Event is a named server-sent event whose data field is a JSON object.
+type Event struct {
+	Type string
+	Data json.RawMessage
+}
+
+// An EventStream reads named server-sent events, such as those emitted by the
+// responses and assistants APIs.
+type EventStream struct {
+	closer  io.Closer
+	scanner *sseparser.StreamScanner
+}
+
+// NewEventStream creates a new EventStream reading from rc.
+func NewEventStream(rc io.ReadCloser) *EventStream {
+	return &EventStream{closer: rc, scanner: sseparser.NewStreamScanner(rc)}
+}
+
+// Next returns the next event in the stream. Events without data (such as
+// keep-alive comments) are skipped, and multiple data fields in one event are
+// joined by newlines.
+//
+// If the stream sends a "[DONE]" data field, it returns ErrStreamDone.
+func (s *EventStream) Next() (*Event, error) {
+	for {
+		evt, _, err := s.scanner.Next()
+		if err != nil {
+			if errors.Is(err, sseparser.ErrStreamEOF) {
+				return nil, fmt.Errorf("stream ended unexpectedly: %w", err)
+			}
+
+			return nil, fmt.Errorf("error reading next event from stream: %w", err)
+		}
+
+		var (
+			typ  string
+			data []string
+		)
+
+		for _, field := range evt.Fields() {
+			switch field.Name {
+			case "event":
+				typ = field.Value
+			case "data":
+				data = append(data, field.Value)
+			}
+		}
+
+		if len(data) == 0 {
+			continue
+		}
+
+		joined := strings.Join(data, "\n")
+		if joined == streamDoneString {
+			return nil, ErrStreamDone
+		}
+
+		return &Event{Type: typ, Data: json.RawMessage(joined)}, nil
+	}
+}
+
+// Close closes the stream.
+func (s *EventStream) Close() error {
+	if err := s.closer.Close(); err != nil {
+		return fmt.Errorf("error closing stream: %w", err)
+	}
+
+	return nil
+}
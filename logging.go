@@ -0,0 +1,355 @@
+package openai
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A LogOpt is a functional option for configuring request logging.
+type LogOpt func(*logConfig)
+
+type logConfig struct {
+	level             slog.Level
+	errorLevel        slog.Level
+	requestBodyLevel  *slog.Level
+	responseBodyLevel *slog.Level
+	redactContent     bool
+}
+
+// WithLogLevel sets the level at which successful requests are logged. The
+// default is slog.LevelInfo. Failed requests are logged at slog.LevelError.
+func WithLogLevel(level slog.Level) LogOpt {
+	return func(c *logConfig) {
+		c.level = level
+	}
+}
+
+// WithRequestBodyLogging logs request headers and bodies at the given level
+// (such as slog.LevelDebug). They are not logged by default.
+//
+// Credentials are always redacted, and multipart/form-data bodies (which may
+// contain files) are never logged.
+func WithRequestBodyLogging(level slog.Level) LogOpt {
+	return func(c *logConfig) {
+		c.requestBodyLevel = &level
+	}
+}
+
+// WithResponseBodyLogging logs response bodies at the given level. They are
+// not logged by default. Streaming response bodies are never logged.
+func WithResponseBodyLogging(level slog.Level) LogOpt {
+	return func(c *logConfig) {
+		c.responseBodyLevel = &level
+	}
+}
+
+// WithContentRedaction redacts message contents (such as "content", "input"
+// and "text" fields) from logged request and response bodies.
+func WithContentRedaction() LogOpt {
+	return func(c *logConfig) {
+		c.redactContent = true
+	}
+}
+
+// WithLogger logs every request made by the Client: its method, path, model,
+// status, latency, request ID, token usage and attempt number (which is
+// greater than 1 for retries).
+//
+// Logging wraps each attempt to send a request, inside any middleware added
+// with WithMiddleware.
+func WithLogger(logger *slog.Logger, opts ...LogOpt) ClientOpt {
+	cfg := logConfig{level: slog.LevelInfo, errorLevel: slog.LevelError}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(c *Client) {
+		c.logging = loggingMiddleware(logger, cfg)
+	}
+}
+
+// redacted replaces redacted values in logs.
+const redacted = "[REDACTED]"
+
+// secretHeaders are headers whose values are always redacted.
+var secretHeaders = map[string]bool{
+	"Authorization":       true,
+	"Api-Key":             true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"X-Api-Key":           true,
+}
+
+// contentFields are body fields redacted by WithContentRedaction.
+var contentFields = map[string]bool{
+	"content":      true,
+	"input":        true,
+	"prompt":       true,
+	"text":         true,
+	"instructions": true,
+	"arguments":    true,
+	"output":       true,
+	"transcript":   true,
+	"delta":        true,
+}
+
+// apiKeyPattern matches OpenAI-style API keys that appear in logged text.
+var apiKeyPattern = regexp.MustCompile(`sk-[A-Za-z0-9_-]{8,}`)
+
+func loggingMiddleware(logger *slog.Logger, cfg logConfig) Middleware {
+	return func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			ctx := req.Context()
+			redactor := newRedactor(req.HTTPRequest.Header, cfg.redactContent)
+
+			if cfg.requestBodyLevel != nil && logger.Enabled(ctx, *cfg.requestBodyLevel) {
+				logger.LogAttrs(ctx, *cfg.requestBodyLevel, "openai request body",
+					slog.String("method", req.HTTPRequest.Method),
+					slog.String("path", req.Endpoint),
+					redactor.headers(req.HTTPRequest.Header),
+					slog.String("body", redactor.requestBody(req)))
+			}
+
+			start := time.Now()
+			resp, err := next(req)
+			latency := time.Since(start)
+
+			level := cfg.level
+			if err != nil {
+				level = cfg.errorLevel
+			}
+
+			if logger.Enabled(ctx, level) {
+				logger.LogAttrs(ctx, level, "openai request", requestAttrs(req, resp, err, latency, redactor)...)
+			}
+
+			if resp != nil && resp.Body != nil && cfg.responseBodyLevel != nil &&
+				logger.Enabled(ctx, *cfg.responseBodyLevel) {
+				if body, err := json.Marshal(resp.Body); err == nil {
+					logger.LogAttrs(ctx, *cfg.responseBodyLevel, "openai response body",
+						slog.String("method", req.HTTPRequest.Method),
+						slog.String("path", req.Endpoint),
+						slog.String("body", redactor.json(body)))
+				}
+			}
+
+			return resp, err
+		}
+	}
+}
+
+// requestAttrs returns the attributes of a request's log record.
+func requestAttrs(req *Request, resp *Response, err error, latency time.Duration, redactor redactor) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("method", req.HTTPRequest.Method),
+		slog.String("path", req.Endpoint),
+		slog.Duration("latency", latency),
+		slog.Int("attempt", req.Attempt),
+	}
+
+	if req.Model != "" {
+		attrs = append(attrs, slog.String("model", req.Model))
+	}
+
+	var httpResp *http.Response
+	if resp != nil {
+		httpResp = resp.HTTPResponse
+	}
+
+	if httpResp != nil {
+		attrs = append(attrs, slog.Int("status", httpResp.StatusCode))
+
+		if id := httpResp.Header.Get("X-Request-Id"); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+	}
+
+	if resp != nil {
+		if usage := usageAttr(resp.Body); usage.Key != "" {
+			attrs = append(attrs, usage)
+		}
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", redactor.text(err.Error())))
+
+		if httpResp != nil && httpResp.Body != nil {
+			attrs = append(attrs, slog.String("error_body", redactor.text(peekBody(httpResp))))
+		}
+	}
+
+	return attrs
+}
+
+// usageAttr returns the top-level token counts of a decoded response body's
+// Usage field as a group, or an empty attribute if it has none. Only the
+// usage is encoded, not the whole body.
+func usageAttr(body any) slog.Attr {
+	v := reflect.ValueOf(body)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return slog.Attr{}
+	}
+
+	field := v.FieldByName("Usage")
+	if !field.IsValid() || !field.CanInterface() || field.IsZero() {
+		return slog.Attr{}
+	}
+
+	b, err := json.Marshal(field.Interface())
+	if err != nil {
+		return slog.Attr{}
+	}
+
+	var usage map[string]json.RawMessage
+	if json.Unmarshal(b, &usage) != nil || len(usage) == 0 {
+		return slog.Attr{}
+	}
+
+	names := make([]string, 0, len(usage))
+	for name := range usage {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	attrs := make([]any, 0, len(names))
+
+	for _, name := range names {
+		var n int64
+		if json.Unmarshal(usage[name], &n) == nil {
+			attrs = append(attrs, slog.Int64(name, n))
+		}
+	}
+
+	return slog.Group("usage", attrs...)
+}
+
+// peekBody reads an (already buffered) error response body, and leaves it
+// readable.
+func peekBody(resp *http.Response) string {
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+
+	return string(b)
+}
+
+// A redactor removes credentials (and, optionally, message contents) from
+// logged values.
+type redactor struct {
+	secrets []string
+	content bool
+}
+
+func newRedactor(header http.Header, content bool) redactor {
+	r := redactor{content: content}
+
+	for name := range secretHeaders {
+		for _, value := range header.Values(name) {
+			// Redact both "Bearer <key>" and the bare key.
+			_, secret, ok := strings.Cut(value, " ")
+			if !ok {
+				secret = value
+			}
+
+			if secret != "" {
+				r.secrets = append(r.secrets, secret)
+			}
+		}
+	}
+
+	return r
+}
+
+func (r redactor) text(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+
+	return apiKeyPattern.ReplaceAllString(s, redacted)
+}
+
+func (r redactor) headers(header http.Header) slog.Attr {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	attrs := make([]any, 0, len(names))
+
+	for _, name := range names {
+		value := strings.Join(header.Values(name), ", ")
+		if secretHeaders[http.CanonicalHeaderKey(name)] {
+			value = redacted
+		}
+
+		attrs = append(attrs, slog.String(name, r.text(value)))
+	}
+
+	return slog.Group("headers", attrs...)
+}
+
+func (r redactor) requestBody(req *Request) string {
+	if req.Body == nil {
+		return ""
+	}
+
+	if strings.HasPrefix(req.HTTPRequest.Header.Get("Content-Type"), "multipart/") {
+		return "[multipart form]"
+	}
+
+	b, err := json.Marshal(req.Body)
+	if err != nil {
+		return ""
+	}
+
+	return r.json(b)
+}
+
+// json redacts a JSON document.
+func (r redactor) json(b []byte) string {
+	if r.content {
+		var v any
+		if json.Unmarshal(b, &v) == nil {
+			if redactedBody, err := json.Marshal(redactContent(v)); err == nil {
+				b = redactedBody
+			}
+		}
+	}
+
+	return r.text(string(b))
+}
+
+// redactContent replaces the values of content fields in a decoded JSON value.
+func redactContent(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if contentFields[key] && value != nil {
+				v[key] = redacted
+			} else {
+				v[key] = redactContent(value)
+			}
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactContent(value)
+		}
+	}
+
+	return v
+}
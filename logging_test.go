@@ -0,0 +1,264 @@
+package openai_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func loggingDoer(status int, body string) httptesting.DoerFunc {
+	return func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"X-Request-Id": {"req_123"}},
+			Body:       httptesting.NewTestBody(strings.NewReader(body)),
+		}, nil
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c := openai.NewClient(
+		openai.WithKey("sk-client-key-123456"),
+		openai.WithLogger(logger,
+			openai.WithRequestBodyLogging(slog.LevelDebug),
+			openai.WithResponseBodyLogging(slog.LevelDebug)),
+		openai.WithDoer(loggingDoer(http.StatusOK,
+			`{"id":"cmpl-1","choices":[{"message":{"role":"assistant","content":"Hello"}}],`+
+				`"usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`)),
+	)
+
+	_, err := c.Chat.CreateCompletion(context.Background(), "gpt-4", hi(),
+		chat.WithAPIKey("sk-per-request-key-789"))
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.NotContains(t, out, "sk-client-key-123456")
+	assert.NotContains(t, out, "sk-per-request-key-789")
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 3)
+
+	reqBody := records[0]
+	assert.Equal(t, "openai request body", reqBody["msg"])
+	assert.Equal(t, "[REDACTED]", reqBody["headers"].(map[string]any)["Authorization"])
+	assert.Contains(t, reqBody["body"], `"content":"Hi"`)
+
+	line := records[1]
+	assert.Equal(t, "openai request", line["msg"])
+	assert.Equal(t, "INFO", line["level"])
+	assert.Equal(t, "POST", line["method"])
+	assert.Equal(t, "/chat/completions", line["path"])
+	assert.Equal(t, "gpt-4", line["model"])
+	assert.InDelta(t, 200, line["status"], 0)
+	assert.InDelta(t, 1, line["attempt"], 0)
+	assert.Equal(t, "req_123", line["request_id"])
+	assert.Contains(t, line, "latency")
+	assert.Equal(t, map[string]any{
+		"prompt_tokens": float64(5), "completion_tokens": float64(2), "total_tokens": float64(7),
+	}, line["usage"])
+
+	assert.Equal(t, "openai response body", records[2]["msg"])
+	assert.Contains(t, records[2]["body"], `"content":"Hello"`)
+}
+
+func TestWithLogger_ContentRedaction(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	c := openai.NewClient(
+		openai.WithLogger(logger,
+			openai.WithRequestBodyLogging(slog.LevelDebug),
+			openai.WithResponseBodyLogging(slog.LevelDebug),
+			openai.WithContentRedaction()),
+		openai.WithDoer(loggingDoer(http.StatusOK,
+			`{"choices":[{"message":{"role":"assistant","content":"Secret answer"}}]}`)),
+	)
+
+	_, err := c.Chat.CreateCompletion(context.Background(), "gpt-4",
+		[]chat.Message{chat.NewMessage("user", chat.WithMessageContent("Secret question"))})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.NotContains(t, out, "Secret")
+	assert.Contains(t, out, `\"role\":\"user\"`)
+}
+
+func TestWithLogger_Error(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	c := openai.NewClient(
+		openai.WithKey("sk-client-key-123456"),
+		openai.WithLogger(logger),
+		openai.WithDoer(loggingDoer(http.StatusUnauthorized,
+			`{"error":{"message":"Incorrect API key provided: sk-client-key-123456"}}`)),
+	)
+
+	_, err := c.Chat.CreateCompletion(context.Background(), "gpt-4", hi())
+	require.Error(t, err)
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "ERROR", records[0]["level"])
+	assert.InDelta(t, 401, records[0]["status"], 0)
+	assert.Contains(t, records[0]["error_body"], "Incorrect API key provided: [REDACTED]")
+	assert.NotContains(t, buf.String(), "sk-client-key-123456")
+
+	// The error body is still readable by the caller.
+	var statusErr openai.UnexpectedStatusCodeError
+	require.ErrorAs(t, err, &statusErr)
+
+	var apiErr map[string]any
+	require.NoError(t, json.NewDecoder(statusErr.Response.Body).Decode(&apiErr))
+}
+
+func TestWithLogger_Retries(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	retryOnce := func(next openai.Handler) openai.Handler {
+		return func(req *openai.Request) (*openai.Response, error) {
+			if _, err := next(req); err == nil {
+				t.Fatal("expected first attempt to fail")
+			}
+
+			return next(req)
+		}
+	}
+
+	statuses := []int{http.StatusInternalServerError, http.StatusOK}
+	doer := func(r *http.Request) (*http.Response, error) {
+		status := statuses[0]
+		statuses = statuses[1:]
+
+		return loggingDoer(status, `{}`)(r)
+	}
+
+	c := openai.NewClient(
+		openai.WithLogger(logger),
+		openai.WithMiddleware(retryOnce),
+		openai.WithDoer(httptesting.DoerFunc(doer)),
+	)
+
+	_, err := c.Models.List(context.Background())
+	require.NoError(t, err)
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 2)
+	assert.InDelta(t, 1, records[0]["attempt"], 0)
+	assert.InDelta(t, 500, records[0]["status"], 0)
+	assert.InDelta(t, 2, records[1]["attempt"], 0)
+	assert.InDelta(t, 200, records[1]["status"], 0)
+	assert.NotContains(t, records[1], "model")
+}
+
+func TestWithLogger_Disabled(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	c := openai.NewClient(
+		openai.WithLogger(logger, openai.WithResponseBodyLogging(slog.LevelDebug)),
+		openai.WithDoer(loggingDoer(http.StatusOK,
+			`{"id":"cmpl-1","usage":{"prompt_tokens":5,"completion_tokens":2,"total_tokens":7}}`)),
+	)
+
+	comp, err := c.Chat.CreateCompletion(context.Background(), "gpt-4", hi())
+	require.NoError(t, err)
+	assert.Equal(t, 7, comp.Usage.TotalTokens)
+	assert.Empty(t, buf.String())
+}
+
+var errNotStreamed = errors.New("body was not streamed")
+
+// A signalWriter closes written on its first write.
+type signalWriter struct {
+	bytes.Buffer
+
+	once    sync.Once
+	written chan struct{}
+}
+
+func (w *signalWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.written) })
+
+	return w.Buffer.Write(p)
+}
+
+func TestWithLogger_WriteSpeechStreams(t *testing.T) {
+	t.Parallel()
+
+	pr, pw := io.Pipe()
+	w := &signalWriter{written: make(chan struct{})}
+
+	go func() {
+		_, _ = pw.Write([]byte("audio"))
+
+		// The rest of the body is only sent once the first part reaches w.
+		select {
+		case <-w.written:
+			_, _ = pw.Write([]byte(" data"))
+			_ = pw.Close()
+		case <-time.After(5 * time.Second):
+			_ = pw.CloseWithError(errNotStreamed)
+		}
+	}()
+
+	doer := func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: pr}, nil
+	}
+
+	c := openai.NewClient(
+		openai.WithLogger(slog.New(slog.NewJSONHandler(io.Discard, nil))),
+		openai.WithDoer(httptesting.DoerFunc(doer)),
+	)
+
+	require.NoError(t, c.Audio.WriteSpeech(context.Background(), w, "tts-1", "alloy", "Hello, world."))
+	assert.Equal(t, "audio data", w.String())
+}
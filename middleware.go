@@ -0,0 +1,50 @@
+package openai
+
+import "github.com/jclem/openai-go/internal/service"
+
+// A Request is an API request passing through a Client's middleware. It knows
+// the request's endpoint, model and body (the service's request struct), as
+// well as the HTTP request that will be sent.
+type Request = service.Request
+
+// A Response is an API response passing through a Client's middleware. Its
+// Body is the decoded response (such as a *chat.CompletionResponse), except
+// for streaming responses.
+type Response = service.Response
+
+// ErrBodyNotRewindable is returned (wrapped) when middleware retries a request
+// whose body cannot be rewound.
+var ErrBodyNotRewindable = service.ErrBodyNotRewindable
+
+// ErrResponseWritten is returned (wrapped) when middleware retries a request
+// whose response has already been partly written to an io.Writer.
+var ErrResponseWritten = service.ErrResponseWritten
+
+// A Handler handles a Request.
+type Handler = service.Handler
+
+// A Middleware wraps a Handler, to inspect or change requests and responses.
+//
+//	func logModel(next openai.Handler) openai.Handler {
+//		return func(req *openai.Request) (*openai.Response, error) {
+//			log.Printf("%s %s", req.Endpoint, req.Model)
+//			return next(req)
+//		}
+//	}
+type Middleware = service.Middleware
+
+// WithMiddleware adds middleware around every request made by the Client. The
+// first middleware is the outermost, so it sees requests first and responses
+// last.
+//
+// Middleware that calls the next handler more than once (to retry a request)
+// resends the request, rewinding its body. Streamed multipart/form-data bodies
+// (such as file uploads) cannot be rewound, and retrying them fails with
+// ErrBodyNotRewindable. Responses copied to an io.Writer (such as file
+// content) are streamed to it, so retrying once part of one has been written
+// fails with ErrResponseWritten.
+func WithMiddleware(middleware ...Middleware) ClientOpt {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
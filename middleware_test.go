@@ -0,0 +1,263 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/jclem/openai-go/pkg/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hi() []chat.Message {
+	return []chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hi"))}
+}
+
+func TestWithMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var (
+		calls []string
+		body  struct {
+			Model       string  `json:"model"`
+			Temperature float64 `json:"temperature"`
+		}
+		completion *chat.CompletionResponse
+	)
+
+	named := func(name string) openai.Middleware {
+		return func(next openai.Handler) openai.Handler {
+			return func(req *openai.Request) (*openai.Response, error) {
+				calls = append(calls, name+" "+req.Endpoint+" "+req.Model)
+
+				return next(req)
+			}
+		}
+	}
+
+	inspect := func(next openai.Handler) openai.Handler {
+		return func(req *openai.Request) (*openai.Response, error) {
+			require.NoError(t, req.DecodeBody(&body))
+
+			resp, err := next(req)
+			completion, _ = resp.Body.(*chat.CompletionResponse)
+
+			return resp, err
+		}
+	}
+
+	var reqs []*http.Request
+
+	c := openai.NewClient(
+		openai.WithKey("api-key"),
+		openai.WithMiddleware(named("outer"), named("inner")),
+		openai.WithMiddleware(inspect),
+		openai.WithDoer(recordingDoer(&reqs, `{"id":"cmpl-1","usage":{"total_tokens":3}}`)),
+	)
+
+	resp, err := c.Chat.CreateCompletion(context.Background(), "gpt-4", hi(), chat.WithTemperature(0.5))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"outer /chat/completions gpt-4", "inner /chat/completions gpt-4"}, calls)
+	assert.Equal(t, "gpt-4", body.Model)
+	assert.InDelta(t, 0.5, body.Temperature, 0)
+	require.NotNil(t, completion)
+	assert.Equal(t, 3, completion.Usage.TotalTokens)
+	assert.Same(t, resp, completion)
+}
+
+func TestWithMiddleware_SetBody(t *testing.T) {
+	t.Parallel()
+
+	var sent map[string]any
+
+	doer := func(r *http.Request) (*http.Response, error) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+
+		return recordingDoer(&[]*http.Request{}, `{}`)(r)
+	}
+
+	rewrite := func(next openai.Handler) openai.Handler {
+		return func(req *openai.Request) (*openai.Response, error) {
+			var body map[string]any
+			require.NoError(t, req.DecodeBody(&body))
+
+			body["model"] = "gpt-4o-mini"
+			require.NoError(t, req.SetBody(body))
+			assert.Equal(t, "gpt-4o-mini", req.Model)
+
+			return next(req)
+		}
+	}
+
+	c := openai.NewClient(openai.WithMiddleware(rewrite), openai.WithDoer(httptesting.DoerFunc(doer)))
+
+	_, err := c.Chat.CreateCompletion(context.Background(), "gpt-4", hi())
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4o-mini", sent["model"])
+}
+
+func TestWithMiddleware_Retry(t *testing.T) {
+	t.Parallel()
+
+	var bodies []string
+
+	doer := func(r *http.Request) (*http.Response, error) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		bodies = append(bodies, string(b))
+
+		status := http.StatusServiceUnavailable
+		if len(bodies) == 3 {
+			status = http.StatusOK
+		}
+
+		return &http.Response{
+			StatusCode: status,
+			Body:       httptesting.NewTestBody(strings.NewReader(`{"id":"cmpl-1"}`)),
+		}, nil
+	}
+
+	var attempts int
+
+	retry := func(next openai.Handler) openai.Handler {
+		return func(req *openai.Request) (*openai.Response, error) {
+			for {
+				resp, err := next(req)
+
+				var statusErr openai.UnexpectedStatusCodeError
+				if !errors.As(err, &statusErr) || req.Attempt == 3 {
+					attempts = req.Attempt
+
+					return resp, err
+				}
+			}
+		}
+	}
+
+	c := openai.NewClient(openai.WithMiddleware(retry), openai.WithDoer(httptesting.DoerFunc(doer)))
+
+	resp, err := c.Chat.CreateCompletion(context.Background(), "gpt-4", hi())
+	require.NoError(t, err)
+
+	assert.Equal(t, "cmpl-1", resp.ID)
+	assert.Equal(t, 3, attempts)
+	require.Len(t, bodies, 3)
+	assert.Equal(t, bodies[0], bodies[2])
+	assert.Contains(t, bodies[2], `"model":"gpt-4"`)
+}
+
+func TestWithMiddleware_ShortCircuit(t *testing.T) {
+	t.Parallel()
+
+	cache := func(openai.Handler) openai.Handler {
+		return func(*openai.Request) (*openai.Response, error) {
+			return &openai.Response{HTTPResponse: &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"id":"cached"}`)),
+			}}, nil
+		}
+	}
+
+	c := openai.NewClient(openai.WithMiddleware(cache), openai.WithDoer(httptesting.DoerFunc(
+		func(*http.Request) (*http.Response, error) {
+			t.Fatal("unexpected request")
+
+			return nil, nil //nolint: nilnil // Unreachable.
+		})))
+
+	resp, err := c.Chat.CreateCompletion(context.Background(), "gpt-4", hi())
+	require.NoError(t, err)
+	assert.Equal(t, "cached", resp.ID)
+}
+
+// retryOnce is middleware that retries a request once if it fails.
+func retryOnce(next openai.Handler) openai.Handler {
+	return func(req *openai.Request) (*openai.Response, error) {
+		resp, err := next(req)
+		if err == nil {
+			return resp, nil
+		}
+
+		return next(req)
+	}
+}
+
+func TestWithMiddleware_RetryWriter(t *testing.T) {
+	t.Parallel()
+
+	var attempts int
+
+	doer := func(*http.Request) (*http.Response, error) {
+		attempts++
+
+		if attempts == 1 {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       httptesting.NewTestBody(strings.NewReader(`{}`)),
+			}, nil
+		}
+
+		return &http.Response{StatusCode: http.StatusOK, Body: httptesting.NewTestBody(strings.NewReader("file content"))}, nil
+	}
+
+	c := openai.NewClient(openai.WithMiddleware(retryOnce), openai.WithDoer(httptesting.DoerFunc(doer)))
+
+	var b strings.Builder
+	require.NoError(t, c.Files.Content(context.Background(), "file-1", &b))
+
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, "file content", b.String())
+}
+
+func TestWithMiddleware_RetryAfterWrite(t *testing.T) {
+	t.Parallel()
+
+	var attempts int
+
+	doer := func(*http.Request) (*http.Response, error) {
+		attempts++
+
+		// The body fails partway through.
+		body := io.MultiReader(strings.NewReader("file"), iotest.ErrReader(io.ErrUnexpectedEOF))
+
+		return &http.Response{StatusCode: http.StatusOK, Body: httptesting.NewTestBody(body)}, nil
+	}
+
+	c := openai.NewClient(openai.WithMiddleware(retryOnce), openai.WithDoer(httptesting.DoerFunc(doer)))
+
+	var b strings.Builder
+	err := c.Files.Content(context.Background(), "file-1", &b)
+	require.ErrorIs(t, err, openai.ErrResponseWritten)
+
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, "file", b.String())
+}
+
+func TestWithMiddleware_RetryNotRewindable(t *testing.T) {
+	t.Parallel()
+
+	doer := func(r *http.Request) (*http.Response, error) {
+		_, _ = io.Copy(io.Discard, r.Body)
+
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       httptesting.NewTestBody(strings.NewReader(`{}`)),
+		}, nil
+	}
+
+	c := openai.NewClient(openai.WithMiddleware(retryOnce), openai.WithDoer(httptesting.DoerFunc(doer)))
+
+	_, err := c.Files.Upload(context.Background(), strings.NewReader("data"), "data.jsonl", files.PurposeBatch)
+	require.ErrorIs(t, err, openai.ErrBodyNotRewindable)
+}
@@ -6,8 +6,21 @@ import (
 	"net/url"
 
 	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/assistants"
+	"github.com/jclem/openai-go/pkg/audio"
+	"github.com/jclem/openai-go/pkg/batch"
 	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/jclem/openai-go/pkg/completions"
 	"github.com/jclem/openai-go/pkg/embeddings"
+	"github.com/jclem/openai-go/pkg/files"
+	"github.com/jclem/openai-go/pkg/finetuning"
+	"github.com/jclem/openai-go/pkg/images"
+	"github.com/jclem/openai-go/pkg/models"
+	"github.com/jclem/openai-go/pkg/moderations"
+	"github.com/jclem/openai-go/pkg/realtime"
+	"github.com/jclem/openai-go/pkg/responses"
+	"github.com/jclem/openai-go/pkg/uploads"
+	"github.com/jclem/openai-go/pkg/vectorstores"
 )
 
 // DefaultBaseURL is the default base URL for the OpenAI API.
@@ -22,15 +35,38 @@ type Doer interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
+// An UnexpectedStatusCodeError is the error returned (wrapped) when the API
+// responds with a non-2xx status code. It includes the response.
+type UnexpectedStatusCodeError = service.UnexpectedStatusCodeError
+
 // A Client is an OpenAI-compatible API client.
 type Client struct {
-	Chat       *chat.Service
-	Embeddings *embeddings.Service
+	Chat         *chat.Service
+	Embeddings   *embeddings.Service
+	Models       *models.Service
+	Moderations  *moderations.Service
+	Images       *images.Service
+	Audio        *audio.Service
+	Files        *files.Service
+	Uploads      *uploads.Service
+	Batches      *batch.Service
+	FineTuning   *finetuning.Service
+	Completions  *completions.Service
+	Responses    *responses.Service
+	Assistants   *assistants.Service
+	VectorStores *vectorstores.Service
+	Realtime     *realtime.Service
 
-	key     string
-	baseURL *url.URL
-	doer    service.Doer
-	common  *service.Service
+	key          string
+	credentials  CredentialProvider
+	baseURL      *url.URL
+	doer         service.Doer
+	azure        *service.Azure
+	header       http.Header
+	middleware   []Middleware
+	logging      Middleware
+	instrumenter Instrumenter
+	common       *service.Service
 }
 
 // NewClient creates a new Client.
@@ -44,8 +80,48 @@ func NewClient(opts ...ClientOpt) *Client {
 		opt(&c)
 	}
 
-	c.common = service.New(c.baseURL, c.key, c.doer)
+	var serviceOpts []service.Option
+	if len(c.header) > 0 {
+		serviceOpts = append(serviceOpts, service.WithHeaders(c.header))
+	}
+
+	if len(c.middleware) > 0 {
+		serviceOpts = append(serviceOpts, service.WithMiddleware(c.middleware...))
+	}
+
+	// Logging is innermost, so that it logs every attempt to send a request.
+	if c.logging != nil {
+		serviceOpts = append(serviceOpts, service.WithMiddleware(c.logging))
+	}
+
+	if c.instrumenter != nil {
+		serviceOpts = append(serviceOpts, service.WithInstrumenter(c.instrumenter))
+	}
+
+	if c.credentials != nil {
+		serviceOpts = append(serviceOpts, service.WithCredentials(c.credentials))
+	}
+
+	if c.azure != nil {
+		serviceOpts = append(serviceOpts, service.WithAzure(c.azure))
+	}
+
+	c.common = service.New(c.baseURL, c.key, c.doer, serviceOpts...)
 	c.Chat = (*chat.Service)(c.common)
+	c.Embeddings = (*embeddings.Service)(c.common)
+	c.Models = (*models.Service)(c.common)
+	c.Moderations = (*moderations.Service)(c.common)
+	c.Images = (*images.Service)(c.common)
+	c.Audio = (*audio.Service)(c.common)
+	c.Files = (*files.Service)(c.common)
+	c.Uploads = (*uploads.Service)(c.common)
+	c.Batches = (*batch.Service)(c.common)
+	c.FineTuning = (*finetuning.Service)(c.common)
+	c.Completions = (*completions.Service)(c.common)
+	c.Responses = (*responses.Service)(c.common)
+	c.Assistants = (*assistants.Service)(c.common)
+	c.VectorStores = (*vectorstores.Service)(c.common)
+	c.Realtime = (*realtime.Service)(c.common)
 
 	return &c
 }
@@ -55,10 +131,47 @@ type ClientOpt func(*Client)
 
 // WithKey sets the API key for the Client.
 //
-// If no key is provided, one must be provided for every request.
+// If no key (or credential provider) is provided, one must be provided for
+// every request.
 func WithKey(key string) ClientOpt {
 	return func(c *Client) {
 		c.key = key
+		c.credentials = nil
+	}
+}
+
+// WithCredentialProvider sets a provider that is consulted for the Client's
+// credential on every request, replacing a static key.
+//
+// Per-request keys (such as chat.WithAPIKey) still take precedence.
+func WithCredentialProvider(provider CredentialProvider) ClientOpt {
+	return func(c *Client) {
+		c.credentials = provider
+	}
+}
+
+// WithOrganization sets the organization ("OpenAI-Organization" header) that
+// requests are made on behalf of.
+func WithOrganization(organization string) ClientOpt {
+	return WithHeader("OpenAI-Organization", organization)
+}
+
+// WithProject sets the project ("OpenAI-Project" header) that requests are
+// made on behalf of.
+func WithProject(project string) ClientOpt {
+	return WithHeader("OpenAI-Project", project)
+}
+
+// WithHeader sets a header on every request made by the Client.
+//
+// To set a header on a single call, use WithRequestHeader.
+func WithHeader(key, value string) ClientOpt {
+	return func(c *Client) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+
+		c.header.Set(key, value)
 	}
 }
 
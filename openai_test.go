@@ -43,3 +43,19 @@ func TestClient_Chat_CreateCompletion(t *testing.T) {
 
 	assert.Equal(t, &compresp, comp)
 }
+
+func TestClient_Embeddings_Create(t *testing.T) {
+	t.Parallel()
+
+	resp := &http.Response{}
+	resp.StatusCode = http.StatusOK
+	resp.Body = httptesting.NewTestBody(bytes.NewReader([]byte(`{"data":[{"embedding":[0.5]}]}`)))
+	doer := httptesting.NewTestDoer(resp, nil)
+
+	c := openai.NewClient(openai.WithDoer(&doer))
+
+	embs, err := c.Embeddings.Create(context.Background(), "text-embedding-3-small", []string{"Hello, world."})
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{0.5}, embs.Data[0].Embedding)
+}
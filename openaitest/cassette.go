@@ -0,0 +1,266 @@
+// Package openaitest provides utilities for testing code that uses the
+// openai package without calling the live API.
+package openaitest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+// A Cassette is a recording of HTTP interactions with the API.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// An Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// A RecordedRequest is a recorded HTTP request.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+
+	// Body is the request body. JSON bodies are stored as JSON, and other
+	// bodies as JSON strings.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// A RecordedResponse is a recorded HTTP response.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+
+	// Chunks are the pieces of the response body, in the order and with the
+	// timing in which they were read. Non-streaming bodies are usually a
+	// single chunk.
+	Chunks []Chunk `json:"chunks"`
+}
+
+// A Chunk is a piece of a recorded response body.
+type Chunk struct {
+	// Delay is the time between the previous chunk (or the request) and this
+	// one.
+	Delay Duration `json:"delay"`
+
+	// Data is the chunk's content, base64-encoded if Encoding is
+	// EncodingBase64.
+	Data string `json:"data"`
+
+	// Encoding is EncodingBase64 for chunks that aren't valid UTF-8 text
+	// (such as audio), and empty otherwise.
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// EncodingBase64 is the Encoding of base64-encoded chunks.
+const EncodingBase64 = "base64"
+
+// newChunk creates a chunk of data, which is redacted if it is text and
+// base64-encoded if it isn't.
+func newChunk(delay time.Duration, data []byte, redact func(string) string) Chunk {
+	if !utf8.Valid(data) {
+		return Chunk{Delay: Duration(delay), Data: base64.StdEncoding.EncodeToString(data), Encoding: EncodingBase64}
+	}
+
+	return Chunk{Delay: Duration(delay), Data: redact(string(data))}
+}
+
+// Bytes returns the chunk's decoded content.
+func (c Chunk) Bytes() ([]byte, error) {
+	if c.Encoding != EncodingBase64 {
+		return []byte(c.Data), nil
+	}
+
+	b, err := base64.StdEncoding.DecodeString(c.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding chunk: %w", err)
+	}
+
+	return b, nil
+}
+
+// A Duration is a time.Duration that is encoded in JSON as a string, such as
+// "1.5s".
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String()) //nolint: wrapcheck // Marshaling a string never fails.
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("error unmarshaling duration: %w", err)
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("error parsing duration: %w", err)
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+// LoadCassette loads a cassette from a file.
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("error unmarshaling cassette %s: %w", path, err)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette to a file, creating its directory if needed.
+func (c *Cassette) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { //nolint: gosec,mnd // Cassettes are checked in.
+		return fmt.Errorf("error creating cassette directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil { //nolint: gosec,mnd // Cassettes are checked in.
+		return fmt.Errorf("error writing cassette: %w", err)
+	}
+
+	return nil
+}
+
+// encodeBody encodes a request body for a cassette: compact JSON if the body
+// is JSON, or a JSON string otherwise.
+func encodeBody(b []byte) json.RawMessage {
+	if len(b) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if json.Valid(b) && json.Compact(&buf, b) == nil {
+		return buf.Bytes()
+	}
+
+	s, _ := json.Marshal(string(b))
+
+	return s
+}
+
+// An Option is a functional option for configuring a Recorder or Replayer.
+type Option func(*config)
+
+type config struct {
+	redactHeaders  map[string]bool
+	redactPatterns []*regexp.Regexp
+	realTiming     bool
+}
+
+// DefaultRedactedHeaders are the headers whose values are redacted from
+// recordings by default.
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Api-Key",
+	"OpenAI-Organization",
+	"OpenAI-Project",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// apiKeyPattern matches OpenAI-style API keys.
+var apiKeyPattern = regexp.MustCompile(`sk-[A-Za-z0-9_-]{8,}`)
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		redactHeaders:  make(map[string]bool),
+		redactPatterns: []*regexp.Regexp{apiKeyPattern},
+	}
+
+	for _, name := range DefaultRedactedHeaders {
+		cfg.redactHeaders[http.CanonicalHeaderKey(name)] = true
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// WithRedactedHeaders redacts the values of additional headers from
+// recordings.
+func WithRedactedHeaders(names ...string) Option {
+	return func(c *config) {
+		for _, name := range names {
+			c.redactHeaders[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// WithRedactedPattern redacts text matching a pattern from recorded bodies
+// and headers. Patterns are matched within lines of response bodies.
+// OpenAI-style API keys are always redacted.
+func WithRedactedPattern(pattern *regexp.Regexp) Option {
+	return func(c *config) {
+		c.redactPatterns = append(c.redactPatterns, pattern)
+	}
+}
+
+// WithRealTiming makes a Replayer wait the recorded delay before each chunk
+// of a response body, to reproduce the timing of streams.
+func WithRealTiming() Option {
+	return func(c *config) {
+		c.realTiming = true
+	}
+}
+
+// redacted replaces redacted values in recordings.
+const redacted = "REDACTED"
+
+func (c config) redact(s string) string {
+	for _, pattern := range c.redactPatterns {
+		s = pattern.ReplaceAllString(s, redacted)
+	}
+
+	return s
+}
+
+func (c config) redactHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	out := make(http.Header, len(header))
+
+	for name, values := range header {
+		for _, value := range values {
+			if c.redactHeaders[http.CanonicalHeaderKey(name)] {
+				value = redacted
+			}
+
+			out.Add(name, c.redact(value))
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,226 @@
+package openaitest_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/openaitest"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	completionBody = `{"id":"chatcmpl-1","choices":[{"message":{"role":"assistant","content":"Hello"}}]}`
+	streamBody     = "data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n" +
+		"data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\n" +
+		"data: [DONE]\n\n"
+)
+
+// liveDoer stands in for the API, responding to streaming requests with
+// streamBody in two reads and to other requests with completionBody.
+func liveDoer() openai.Doer {
+	return httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		b, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if bytes.Contains(b, []byte(`"stream":true`)) {
+			first, rest, _ := strings.Cut(streamBody, "\n\n")
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"text/event-stream"}},
+				Body: httptesting.NewTestBody(io.MultiReader(
+					strings.NewReader(first+"\n\n"), strings.NewReader(rest))),
+			}, nil
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Set-Cookie": {"session=secret"}},
+			Body:       httptesting.NewTestBody(strings.NewReader(completionBody)),
+		}, nil
+	})
+}
+
+func messages() []chat.Message {
+	return []chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hi"))}
+}
+
+func complete(t *testing.T, client *openai.Client) {
+	t.Helper()
+
+	comp, err := client.Chat.CreateCompletion(context.Background(), "gpt-4o", messages())
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", *comp.Choices[0].Message.Content)
+
+	stream, err := client.Chat.CreateStreamingCompletion(context.Background(), "gpt-4o", messages())
+	require.NoError(t, err)
+
+	var content string
+
+	for {
+		chunk, err := stream.Next()
+		if err != nil {
+			require.ErrorIs(t, err, chat.ErrStreamDone)
+
+			break
+		}
+
+		content += *chunk.Choices[0].Delta.Content
+	}
+
+	require.NoError(t, stream.Close())
+	assert.Equal(t, "Hello", content)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	recorder := openaitest.NewRecorder(liveDoer())
+	client := openai.NewClient(openai.WithKey("key"), openai.WithDoer(recorder))
+
+	_, err := client.Chat.CreateCompletion(context.Background(), "gpt-4o", messages(),
+		chat.WithUser("sk-abcdefghijklmnop"))
+	require.NoError(t, err)
+
+	complete(t, client)
+
+	cassette := recorder.Cassette()
+	require.Len(t, cassette.Interactions, 3)
+
+	comp := cassette.Interactions[0]
+	assert.Equal(t, http.MethodPost, comp.Request.Method)
+	assert.Equal(t, "/v1/chat/completions", comp.Request.Path)
+	assert.Equal(t, "REDACTED", comp.Request.Header.Get("Authorization"))
+	assert.Equal(t, "REDACTED", comp.Response.Header.Get("Set-Cookie"))
+	assert.JSONEq(t, `{"model":"gpt-4o","messages":[{"role":"user","content":"Hi"}],"user":"REDACTED"}`,
+		string(comp.Request.Body))
+	assert.Equal(t, completionBody, comp.Response.Chunks[0].Data)
+
+	stream := cassette.Interactions[2]
+	require.Len(t, stream.Response.Chunks, 2)
+	assert.Equal(t, "text/event-stream", stream.Response.Header.Get("Content-Type"))
+	assert.Equal(t, streamBody, stream.Response.Chunks[0].Data+stream.Response.Chunks[1].Data)
+}
+
+func TestReplayer(t *testing.T) {
+	t.Parallel()
+
+	recorder := openaitest.NewRecorder(liveDoer())
+	complete(t, openai.NewClient(openai.WithDoer(recorder)))
+
+	path := filepath.Join(t.TempDir(), "testdata", "chat.json")
+	require.NoError(t, recorder.Save(path))
+
+	cassette, err := openaitest.LoadCassette(path)
+	require.NoError(t, err)
+
+	replayer := openaitest.NewReplayer(cassette, openaitest.WithRealTiming())
+	complete(t, openai.NewClient(openai.WithDoer(replayer)))
+	assert.Zero(t, replayer.Remaining())
+
+	// Interactions are used once.
+	_, err = openai.NewClient(openai.WithDoer(replayer)).
+		Chat.CreateCompletion(context.Background(), "gpt-4o", messages())
+	require.ErrorIs(t, err, openaitest.ErrNoInteraction)
+}
+
+func TestReplayer_Match(t *testing.T) {
+	t.Parallel()
+
+	cassette := &openaitest.Cassette{Interactions: []openaitest.Interaction{{
+		Request: openaitest.RecordedRequest{
+			Method: http.MethodPost,
+			Path:   "/v1/chat/completions",
+			Body:   []byte(`{"messages":[{"content":"Hi","role":"user"}],"model":"gpt-4o"}`),
+		},
+		Response: openaitest.RecordedResponse{
+			StatusCode: http.StatusOK,
+			Chunks:     []openaitest.Chunk{{Data: completionBody}},
+		},
+	}}}
+
+	client := openai.NewClient(openai.WithDoer(openaitest.NewReplayer(cassette)))
+
+	// Bodies are matched regardless of key order.
+	_, err := client.Chat.CreateCompletion(context.Background(), "gpt-4o", messages())
+	require.NoError(t, err)
+
+	_, err = client.Chat.CreateCompletion(context.Background(), "gpt-4o", messages(), chat.WithUser("alice"))
+	require.ErrorIs(t, err, openaitest.ErrNoInteraction)
+}
+
+func TestNewCassette(t *testing.T) {
+	t.Parallel()
+
+	recorder := openaitest.NewRecorder(liveDoer())
+	complete(t, openai.NewClient(openai.WithDoer(recorder)))
+
+	path := filepath.Join(t.TempDir(), "chat.json")
+	require.NoError(t, recorder.Save(path))
+
+	doer := openaitest.NewCassette(t, path, liveDoer())
+	complete(t, openai.NewClient(openai.WithDoer(doer)))
+}
+
+func TestReplayer_MultipartAndBinary(t *testing.T) {
+	t.Parallel()
+
+	audio := []byte{0xff, 0xfb, '\n', 0x90, 0x00, '\n', 0xc4}
+	upload := []byte{0x00, 0xfe, 0xff, 'a'}
+
+	live := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if _, err := io.Copy(io.Discard, req.Body); err != nil {
+			return nil, err
+		}
+
+		body := []byte(`{"id":"file-1"}`)
+		if req.URL.Path == "/v1/audio/speech" {
+			body = audio
+		}
+
+		return &http.Response{StatusCode: http.StatusOK, Body: httptesting.NewTestBody(bytes.NewReader(body))}, nil
+	})
+
+	run := func(client *openai.Client) {
+		file, err := client.Files.Upload(context.Background(), bytes.NewReader(upload), "data.bin", "assistants")
+		require.NoError(t, err)
+		assert.Equal(t, "file-1", file.ID)
+
+		rc, err := client.Audio.Speech(context.Background(), "tts-1", "alloy", "Hi")
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		assert.Equal(t, audio, b)
+	}
+
+	recorder := openaitest.NewRecorder(live)
+	run(openai.NewClient(openai.WithDoer(recorder)))
+
+	path := filepath.Join(t.TempDir(), "binary.json")
+	require.NoError(t, recorder.Save(path))
+
+	cassette, err := openaitest.LoadCassette(path)
+	require.NoError(t, err)
+
+	speech := cassette.Interactions[1].Response.Chunks
+	require.NotEmpty(t, speech)
+	assert.Equal(t, openaitest.EncodingBase64, speech[0].Encoding)
+
+	// The upload is sent with a new multipart boundary, and still matches.
+	replayer := openaitest.NewReplayer(cassette)
+	run(openai.NewClient(openai.WithDoer(replayer)))
+	assert.Zero(t, replayer.Remaining())
+}
@@ -0,0 +1,361 @@
+package openaitest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jclem/openai-go"
+)
+
+// A Fault is a failure a FaultDoer injects into a request.
+type Fault int
+
+const (
+	// NoFault passes a request through unchanged.
+	NoFault Fault = iota
+
+	// ConnectionReset fails a request with a connection reset error, which
+	// matches syscall.ECONNRESET with errors.Is.
+	ConnectionReset
+
+	// Timeout fails a request with a timeout error, which matches
+	// os.ErrDeadlineExceeded with errors.Is.
+	Timeout
+
+	// RateLimited responds to a request with a 429 and a Retry-After header.
+	RateLimited
+
+	// InternalServerError responds to a request with a 500.
+	InternalServerError
+
+	// ServiceUnavailable responds to a request with a 503.
+	ServiceUnavailable
+
+	// TruncatedBody cuts a response body off halfway through, as if the
+	// connection closed early.
+	TruncatedBody
+
+	// StreamCutoff ends a server-sent event stream before its "[DONE]" event.
+	StreamCutoff
+
+	// StreamStall stalls a server-sent event stream halfway through an event,
+	// after its first event.
+	StreamStall
+)
+
+var faultNames = map[Fault]string{
+	NoFault:             "none",
+	ConnectionReset:     "connection reset",
+	Timeout:             "timeout",
+	RateLimited:         "rate limited",
+	InternalServerError: "internal server error",
+	ServiceUnavailable:  "service unavailable",
+	TruncatedBody:       "truncated body",
+	StreamCutoff:        "stream cutoff",
+	StreamStall:         "stream stall",
+}
+
+// String implements fmt.Stringer.
+func (f Fault) String() string {
+	if name, ok := faultNames[f]; ok {
+		return name
+	}
+
+	return "Fault(" + strconv.Itoa(int(f)) + ")"
+}
+
+// Repeat returns a schedule of n of the same fault, such as a burst of server
+// errors.
+func Repeat(f Fault, n int) []Fault {
+	faults := make([]Fault, n)
+	for i := range faults {
+		faults[i] = f
+	}
+
+	return faults
+}
+
+// DefaultRetryAfter is the default Retry-After of RateLimited faults.
+const DefaultRetryAfter = time.Second
+
+// DefaultStallTimeout is the default time a StreamStall fault stalls for
+// before failing with a timeout error.
+const DefaultStallTimeout = 5 * time.Second
+
+// A FaultDoer is an openai.Doer that injects faults into requests performed
+// by another Doer, to test how code handles a flaky API.
+//
+// Faults are injected on a deterministic schedule, and then (once the schedule
+// is used up) at random with set probabilities. Requests without a fault pass
+// through unchanged. A FaultDoer is safe for concurrent use.
+type FaultDoer struct {
+	doer          openai.Doer
+	retryAfter    time.Duration
+	stallTimeout  time.Duration
+	probabilities []faultProbability
+	seed          *uint64
+
+	mu       sync.Mutex
+	schedule []Fault
+	rand     *rand.Rand
+	injected []Fault
+}
+
+type faultProbability struct {
+	fault Fault
+	p     float64
+}
+
+var _ openai.Doer = (*FaultDoer)(nil)
+
+// A FaultOpt is a functional option for configuring a FaultDoer.
+type FaultOpt func(*FaultDoer)
+
+// WithSchedule injects faults into requests in order: the first request gets
+// the first fault, and so on. Use NoFault for requests that should succeed.
+func WithSchedule(faults ...Fault) FaultOpt {
+	return func(d *FaultDoer) {
+		d.schedule = append(d.schedule, faults...)
+	}
+}
+
+// WithProbability injects a fault into a random fraction p of the requests
+// made after the schedule is used up. The probabilities of all faults should
+// add up to at most 1.
+func WithProbability(f Fault, p float64) FaultOpt {
+	return func(d *FaultDoer) {
+		d.probabilities = append(d.probabilities, faultProbability{fault: f, p: p})
+	}
+}
+
+// WithSeed seeds the random choice of faults, to make it reproducible. By
+// default, the seed is random.
+func WithSeed(seed uint64) FaultOpt {
+	return func(d *FaultDoer) {
+		d.seed = &seed
+	}
+}
+
+// WithRetryAfter sets the Retry-After of RateLimited faults. The default is
+// DefaultRetryAfter.
+func WithRetryAfter(retryAfter time.Duration) FaultOpt {
+	return func(d *FaultDoer) {
+		d.retryAfter = retryAfter
+	}
+}
+
+// WithStallTimeout sets how long StreamStall faults stall for before failing
+// with a timeout error, unless the request's context is done first. The
+// default is DefaultStallTimeout.
+func WithStallTimeout(timeout time.Duration) FaultOpt {
+	return func(d *FaultDoer) {
+		d.stallTimeout = timeout
+	}
+}
+
+// NewFaultDoer creates a FaultDoer that injects faults into requests performed
+// with doer.
+func NewFaultDoer(doer openai.Doer, opts ...FaultOpt) *FaultDoer {
+	d := &FaultDoer{
+		doer:         doer,
+		retryAfter:   DefaultRetryAfter,
+		stallTimeout: DefaultStallTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	seed := rand.Uint64() //nolint: gosec // Faults need not be unpredictable.
+	if d.seed != nil {
+		seed = *d.seed
+	}
+
+	d.rand = rand.New(rand.NewPCG(seed, seed)) //nolint: gosec // Faults need not be unpredictable.
+
+	return d
+}
+
+// Injected returns the fault injected into each request so far, in order,
+// with NoFault for requests that passed through.
+func (d *FaultDoer) Injected() []Fault {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]Fault(nil), d.injected...)
+}
+
+// next chooses the fault for the next request.
+func (d *FaultDoer) next() Fault {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f := NoFault
+
+	if len(d.schedule) > 0 {
+		f = d.schedule[0]
+		d.schedule = d.schedule[1:]
+	} else if len(d.probabilities) > 0 {
+		r := d.rand.Float64()
+
+		for _, fp := range d.probabilities {
+			if r < fp.p {
+				f = fp.fault
+
+				break
+			}
+
+			r -= fp.p
+		}
+	}
+
+	d.injected = append(d.injected, f)
+
+	return f
+}
+
+// Do implements openai.Doer.
+func (d *FaultDoer) Do(req *http.Request) (*http.Response, error) {
+	f := d.next()
+
+	switch f {
+	case ConnectionReset:
+		return nil, &url.Error{Op: urlErrorOp(req.Method), URL: req.URL.String(), Err: &net.OpError{
+			Op:  "read",
+			Net: "tcp",
+			Err: os.NewSyscallError("read", syscall.ECONNRESET),
+		}}
+	case Timeout:
+		return nil, &url.Error{Op: urlErrorOp(req.Method), URL: req.URL.String(), Err: os.ErrDeadlineExceeded}
+	case RateLimited:
+		return errorResponse(req, &APIError{
+			StatusCode: http.StatusTooManyRequests,
+			Type:       "requests",
+			Code:       "rate_limit_exceeded",
+			Message:    "Rate limit reached.",
+			RetryAfter: d.retryAfter,
+		}), nil
+	case InternalServerError:
+		return errorResponse(req, &APIError{StatusCode: http.StatusInternalServerError, Type: "server_error"}), nil
+	case ServiceUnavailable:
+		return errorResponse(req, &APIError{StatusCode: http.StatusServiceUnavailable, Type: "server_error"}), nil
+	case NoFault, TruncatedBody, StreamCutoff, StreamStall:
+	}
+
+	resp, err := d.doer.Do(req)
+	if err != nil || f == NoFault {
+		return resp, err //nolint: wrapcheck // Errors are the Doer's.
+	}
+
+	// Faulty bodies are read in full first, and then cut.
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	switch f {
+	case TruncatedBody:
+		resp.Body = io.NopCloser(bytes.NewReader(body[:len(body)/2]))
+	case StreamCutoff:
+		if i := bytes.Index(body, []byte("data: [DONE]")); i >= 0 {
+			body = body[:i]
+		}
+
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	case StreamStall:
+		resp.Body = &stallingBody{
+			req:     req,
+			r:       bytes.NewReader(stallPoint(body)),
+			timeout: d.stallTimeout,
+		}
+	case NoFault, ConnectionReset, Timeout, RateLimited, InternalServerError, ServiceUnavailable:
+	}
+
+	resp.ContentLength = -1
+	resp.Header.Del("Content-Length")
+
+	return resp, nil
+}
+
+// urlErrorOp returns the operation of a *url.Error for a request method, as
+// http.Client does. An empty method means GET.
+func urlErrorOp(method string) string {
+	if method == "" {
+		return "Get"
+	}
+
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+// stallPoint returns the part of a stream before a stall: its first event,
+// and half of its next.
+func stallPoint(body []byte) []byte {
+	first := bytes.Index(body, []byte("\n\n"))
+	if first < 0 {
+		return body[:len(body)/2]
+	}
+
+	first += 2
+	rest := body[first:]
+
+	next := bytes.Index(rest, []byte("\n\n"))
+	if next < 0 {
+		next = len(rest)
+	}
+
+	return body[:first+next/2]
+}
+
+func errorResponse(req *http.Request, e *APIError) *http.Response {
+	rec := httptest.NewRecorder()
+	writeError(rec, e)
+
+	resp := rec.Result()
+	resp.Request = req
+
+	return resp
+}
+
+// A stallingBody reads a stream up to a stall, and then blocks until its
+// request's context is done or it times out.
+type stallingBody struct {
+	req     *http.Request
+	r       *bytes.Reader
+	timeout time.Duration
+}
+
+// Read implements io.Reader.
+func (b *stallingBody) Read(p []byte) (int, error) {
+	if b.r.Len() > 0 {
+		return b.r.Read(p) //nolint: wrapcheck // Reading from a bytes.Reader.
+	}
+
+	timer := time.NewTimer(b.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return 0, fmt.Errorf("stream stalled: %w", os.ErrDeadlineExceeded)
+	case <-b.req.Context().Done():
+		return 0, b.req.Context().Err() //nolint: wrapcheck // Context errors are returned as-is.
+	}
+}
+
+// Close implements io.Closer.
+func (*stallingBody) Close() error {
+	return nil
+}
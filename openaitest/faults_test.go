@@ -0,0 +1,197 @@
+package openaitest_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/openaitest"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFaultyClient(t *testing.T, opts ...openaitest.FaultOpt) (*openai.Client, *openaitest.FaultDoer) {
+	t.Helper()
+
+	server := newServer(t)
+	doer := openaitest.NewFaultDoer(server.Client(), opts...)
+
+	return server.NewClient(openai.WithDoer(doer)), doer
+}
+
+// readStream reads a stream to its end, and returns its content and the error
+// that ended it.
+func readStream(t *testing.T, stream *chat.StreamingCompletionResponse) (string, error) {
+	t.Helper()
+
+	defer func() { require.NoError(t, stream.Close()) }()
+
+	var content string
+
+	for {
+		obj, err := stream.Next()
+		if err != nil {
+			return content, err
+		}
+
+		if c, ok := obj.GetContentAt(0); ok {
+			content += c
+		}
+	}
+}
+
+func TestFaultDoer_Schedule(t *testing.T) {
+	t.Parallel()
+
+	faults := append([]openaitest.Fault{
+		openaitest.ConnectionReset,
+		openaitest.Timeout,
+		openaitest.RateLimited,
+	}, openaitest.Repeat(openaitest.ServiceUnavailable, 2)...)
+
+	client, doer := newFaultyClient(t,
+		openaitest.WithSchedule(append(faults, openaitest.InternalServerError, openaitest.NoFault)...),
+		openaitest.WithRetryAfter(2*time.Second))
+
+	complete := func() error {
+		_, err := client.Chat.CreateCompletion(context.Background(), "gpt-4o", messages())
+
+		return err
+	}
+
+	require.ErrorIs(t, complete(), syscall.ECONNRESET)
+	require.ErrorIs(t, complete(), os.ErrDeadlineExceeded)
+
+	err := complete()
+
+	var statusErr openai.UnexpectedStatusCodeError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.Actual)
+	assert.Equal(t, "2", statusErr.Response.Header.Get("Retry-After"))
+
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode(t, complete()))
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode(t, complete()))
+	assert.Equal(t, http.StatusInternalServerError, statusCode(t, complete()))
+	require.NoError(t, complete())
+
+	// Requests after the schedule pass through.
+	require.NoError(t, complete())
+	assert.Len(t, doer.Injected(), 8)
+	assert.Equal(t, openaitest.NoFault, doer.Injected()[7])
+}
+
+func TestFaultDoer_EmptyMethod(t *testing.T) {
+	t.Parallel()
+
+	doer := openaitest.NewFaultDoer(http.DefaultClient, openaitest.WithSchedule(openaitest.ConnectionReset))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://api.openai.com/v1/models", nil)
+	require.NoError(t, err)
+
+	// NewRequestWithContext fills in GET, but a request's method may be empty.
+	req.Method = ""
+
+	_, err = doer.Do(req) //nolint: bodyclose // No response is returned.
+
+	var urlErr *url.Error
+	require.ErrorAs(t, err, &urlErr)
+	assert.Equal(t, "Get", urlErr.Op)
+}
+
+func TestFaultDoer_TruncatedBody(t *testing.T) {
+	t.Parallel()
+
+	client, _ := newFaultyClient(t, openaitest.WithSchedule(openaitest.TruncatedBody))
+
+	_, err := client.Chat.CreateCompletion(context.Background(), "gpt-4o", messages())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected EOF")
+}
+
+func TestFaultDoer_StreamCutoff(t *testing.T) {
+	t.Parallel()
+
+	client, _ := newFaultyClient(t, openaitest.WithSchedule(openaitest.StreamCutoff))
+
+	stream, err := client.Chat.CreateStreamingCompletion(context.Background(), "gpt-4o", messages())
+	require.NoError(t, err)
+
+	content, err := readStream(t, stream)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, chat.ErrStreamDone)
+	assert.Contains(t, err.Error(), "before [DONE]")
+	assert.Equal(t, "Hi", content)
+}
+
+func TestFaultDoer_StreamStall(t *testing.T) {
+	t.Parallel()
+
+	client, _ := newFaultyClient(t,
+		openaitest.WithSchedule(openaitest.StreamStall, openaitest.StreamStall),
+		openaitest.WithStallTimeout(10*time.Millisecond))
+
+	stream, err := client.Chat.CreateStreamingCompletion(context.Background(), "gpt-4o", messages())
+	require.NoError(t, err)
+
+	_, err = readStream(t, stream)
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+
+	// Stalls end early when the request's context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	client, _ = newFaultyClient(t, openaitest.WithSchedule(openaitest.StreamStall))
+
+	stream, err = client.Chat.CreateStreamingCompletion(ctx, "gpt-4o", messages())
+	require.NoError(t, err)
+
+	_, err = readStream(t, stream)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestFaultDoer_Probability(t *testing.T) {
+	t.Parallel()
+
+	ok := httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       httptesting.NewTestBody(strings.NewReader(`{}`)),
+		}, nil
+	})
+
+	run := func() []openaitest.Fault {
+		doer := openaitest.NewFaultDoer(ok,
+			openaitest.WithSeed(42),
+			openaitest.WithProbability(openaitest.InternalServerError, 0.25),
+			openaitest.WithProbability(openaitest.ConnectionReset, 0.25))
+		client := openai.NewClient(openai.WithDoer(doer))
+
+		for range 200 {
+			_, _ = client.Models.List(context.Background())
+		}
+
+		return doer.Injected()
+	}
+
+	injected := run()
+
+	counts := make(map[openaitest.Fault]int)
+	for _, f := range injected {
+		counts[f]++
+	}
+
+	assert.InDelta(t, 100, counts[openaitest.NoFault], 30)
+	assert.InDelta(t, 50, counts[openaitest.InternalServerError], 25)
+	assert.InDelta(t, 50, counts[openaitest.ConnectionReset], 25)
+
+	// The same seed injects the same faults.
+	assert.Equal(t, injected, run())
+}
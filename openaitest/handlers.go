@@ -0,0 +1,392 @@
+package openaitest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jclem/openai-go/pkg/chat"
+)
+
+// A Reply is a scripted reply to a chat completion request.
+type Reply struct {
+	// Content is the content of the reply message.
+	Content string
+
+	// FunctionCall, if set, is a function call made by the reply. Requests
+	// that define tools receive it as a tool call.
+	FunctionCall *FunctionCall
+
+	// FinishReason is the reply's finish reason. It defaults to "stop", or to
+	// "function_call" or "tool_calls" for replies with a function call.
+	FinishReason string
+}
+
+// A FunctionCall is a function call made by a reply.
+type FunctionCall struct {
+	Name string
+
+	// Arguments are the call's arguments, encoded as JSON.
+	Arguments string
+}
+
+// A ChatRequest is a chat completion request received by a Server.
+type ChatRequest struct {
+	Model         string                    `json:"model"`
+	Messages      []chat.Message            `json:"messages"`
+	Functions     []chat.FunctionDefinition `json:"functions,omitempty"`
+	Tools         []json.RawMessage         `json:"tools,omitempty"`
+	Stream        bool                      `json:"stream,omitempty"`
+	StreamOptions *struct {
+		IncludeUsage bool `json:"include_usage"`
+	} `json:"stream_options,omitempty"`
+	User string `json:"user,omitempty"`
+}
+
+// echoReply replies with the content of the last message.
+func echoReply(req *ChatRequest) Reply {
+	if len(req.Messages) == 0 || req.Messages[len(req.Messages)-1].Content == nil {
+		return Reply{}
+	}
+
+	return Reply{Content: *req.Messages[len(req.Messages)-1].Content}
+}
+
+// countTokens approximates the number of tokens in s by counting words.
+func countTokens(s string) int {
+	return len(strings.Fields(s))
+}
+
+func (s *Server) checkModel(w http.ResponseWriter, model string) bool {
+	if slices.Contains(s.models, model) {
+		return true
+	}
+
+	writeError(w, &APIError{
+		StatusCode: http.StatusNotFound,
+		Type:       "invalid_request_error",
+		Code:       "model_not_found",
+		Message:    fmt.Sprintf("The model `%s` does not exist or you do not have access to it.", model),
+	})
+
+	return false
+}
+
+func invalidRequest(w http.ResponseWriter, err error) {
+	writeError(w, &APIError{
+		StatusCode: http.StatusBadRequest,
+		Type:       "invalid_request_error",
+		Message:    err.Error(),
+	})
+}
+
+type chatMessage struct {
+	Role         string         `json:"role"`
+	Content      *string        `json:"content"`
+	FunctionCall *functionCall  `json:"function_call,omitempty"`
+	ToolCalls    []toolCallJSON `json:"tool_calls,omitempty"`
+}
+
+type functionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+type toolCallJSON struct {
+	Index    *int         `json:"index,omitempty"`
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function functionCall `json:"function"`
+}
+
+func (s *Server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	var req ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidRequest(w, err)
+
+		return
+	}
+
+	if !s.checkModel(w, req.Model) {
+		return
+	}
+
+	reply := s.nextReply(&req)
+	tools := len(req.Tools) > 0
+
+	msg := chatMessage{Role: "assistant"}
+	if reply.Content != "" || reply.FunctionCall == nil {
+		msg.Content = &reply.Content
+	}
+
+	finishReason := reply.FinishReason
+
+	if call := reply.FunctionCall; call != nil {
+		fc := functionCall{Name: call.Name, Arguments: call.Arguments}
+
+		if tools {
+			msg.ToolCalls = []toolCallJSON{{ID: s.nextID("call"), Type: "function", Function: fc}}
+		} else {
+			msg.FunctionCall = &fc
+		}
+
+		if finishReason == "" {
+			finishReason = "function_call"
+			if tools {
+				finishReason = "tool_calls"
+			}
+		}
+	}
+
+	if finishReason == "" {
+		finishReason = "stop"
+	}
+
+	var prompt int
+	for _, m := range req.Messages {
+		if m.Content != nil {
+			prompt += countTokens(*m.Content)
+		}
+	}
+
+	completion := countTokens(reply.Content)
+	if reply.FunctionCall != nil {
+		completion += countTokens(reply.FunctionCall.Arguments)
+	}
+
+	usage := chat.Usage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion}
+	id := s.nextID("chatcmpl")
+	created := time.Now().Unix()
+
+	if req.Stream {
+		s.streamChat(w, r, streamedChat{
+			id:           id,
+			created:      created,
+			model:        req.Model,
+			msg:          msg,
+			finishReason: finishReason,
+			usage:        &usage,
+			includeUsage: req.StreamOptions != nil && req.StreamOptions.IncludeUsage,
+		})
+
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":      id,
+		"object":  "chat.completion",
+		"created": created,
+		"model":   req.Model,
+		"choices": []map[string]any{{"index": 0, "message": msg, "finish_reason": finishReason}},
+		"usage":   usage,
+	})
+}
+
+type streamedChat struct {
+	id           string
+	created      int64
+	model        string
+	msg          chatMessage
+	finishReason string
+	usage        *chat.Usage
+	includeUsage bool
+}
+
+// streamChat streams a chat reply as server-sent events: a role delta, the
+// content a word at a time, the function or tool call, the finish reason,
+// the usage (if requested) and "[DONE]".
+func (s *Server) streamChat(w http.ResponseWriter, r *http.Request, c streamedChat) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+
+	send := func(data any) bool {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return false
+		}
+
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", b); err != nil {
+			return false
+		}
+
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		return r.Context().Err() == nil
+	}
+
+	chunk := func(delta any, finishReason *string) map[string]any {
+		obj := map[string]any{
+			"id":      c.id,
+			"object":  "chat.completion.chunk",
+			"created": c.created,
+			"model":   c.model,
+			"choices": []map[string]any{{"index": 0, "delta": delta, "finish_reason": finishReason}},
+		}
+
+		if c.includeUsage {
+			obj["usage"] = nil
+		}
+
+		return obj
+	}
+
+	deltas := []any{map[string]any{"role": "assistant", "content": ""}}
+
+	if c.msg.Content != nil {
+		for _, word := range strings.SplitAfter(*c.msg.Content, " ") {
+			if word != "" {
+				deltas = append(deltas, map[string]any{"content": word})
+			}
+		}
+	}
+
+	if c.msg.FunctionCall != nil {
+		deltas = append(deltas, map[string]any{"function_call": c.msg.FunctionCall})
+	}
+
+	for i, call := range c.msg.ToolCalls {
+		call.Index = &i
+		deltas = append(deltas, map[string]any{"tool_calls": []toolCallJSON{call}})
+	}
+
+	for _, delta := range deltas {
+		if !send(chunk(delta, nil)) {
+			return
+		}
+	}
+
+	if !send(chunk(map[string]any{}, &c.finishReason)) {
+		return
+	}
+
+	if c.includeUsage {
+		if !send(map[string]any{
+			"id":      c.id,
+			"object":  "chat.completion.chunk",
+			"created": c.created,
+			"model":   c.model,
+			"choices": []any{},
+			"usage":   c.usage,
+		}) {
+			return
+		}
+	}
+
+	_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+func (s *Server) handleEmbeddings(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Model      string          `json:"model"`
+		Input      json.RawMessage `json:"input"`
+		Dimensions int             `json:"dimensions"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		invalidRequest(w, err)
+
+		return
+	}
+
+	var inputs []string
+	if err := json.Unmarshal(req.Input, &inputs); err != nil {
+		var input string
+		if err := json.Unmarshal(req.Input, &input); err != nil {
+			invalidRequest(w, fmt.Errorf("input must be a string or an array of strings: %w", err))
+
+			return
+		}
+
+		inputs = []string{input}
+	}
+
+	if !s.checkModel(w, req.Model) {
+		return
+	}
+
+	dimensions := req.Dimensions
+	if dimensions <= 0 {
+		dimensions = s.dimensions
+	}
+
+	data := make([]map[string]any, len(inputs))
+	tokens := 0
+
+	for i, input := range inputs {
+		data[i] = map[string]any{"index": i, "object": "embedding", "embedding": Embedding(req.Model, input, dimensions)}
+		tokens += countTokens(input)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"object": "list",
+		"data":   data,
+		"model":  req.Model,
+		"usage":  map[string]any{"prompt_tokens": tokens, "total_tokens": tokens},
+	})
+}
+
+// Embedding returns the deterministic fake embedding a Server creates for an
+// input: a unit vector derived from a hash of the model and input.
+func Embedding(model, input string, dimensions int) []float64 {
+	seed := sha256.Sum256([]byte(model + "\x00" + input))
+	v := make([]float64, dimensions)
+
+	var norm float64
+
+	for i := range v {
+		var block [sha256.Size + 8]byte
+
+		copy(block[:], seed[:])
+		binary.BigEndian.PutUint64(block[sha256.Size:], uint64(i)) //nolint: gosec // i is not negative.
+
+		h := sha256.Sum256(block[:])
+		v[i] = float64(binary.BigEndian.Uint64(h[:8]))/math.MaxUint64*2 - 1
+		norm += v[i] * v[i]
+	}
+
+	norm = math.Sqrt(norm)
+	for i := range v {
+		v[i] /= norm
+	}
+
+	return v
+}
+
+type modelJSON struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+func newModel(id string) modelJSON {
+	return modelJSON{ID: id, Object: "model", OwnedBy: "openaitest"}
+}
+
+func (s *Server) handleListModels(w http.ResponseWriter, _ *http.Request) {
+	data := make([]modelJSON, len(s.models))
+	for i, id := range s.models {
+		data[i] = newModel(id)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": data})
+}
+
+func (s *Server) handleRetrieveModel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if s.checkModel(w, id) {
+		writeJSON(w, http.StatusOK, newModel(id))
+	}
+}
@@ -0,0 +1,170 @@
+package openaitest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jclem/openai-go"
+)
+
+// A Recorder is an openai.Doer that performs requests with another Doer, and
+// records them and their responses to a cassette.
+//
+// Response bodies are recorded as they are read, in chunks of whole lines with
+// their timing, so streams are recorded as they arrive. An interaction is
+// added to the cassette when its response body is read to the end or closed.
+type Recorder struct {
+	doer openai.Doer
+	cfg  config
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+var _ openai.Doer = (*Recorder)(nil)
+
+// NewRecorder creates a Recorder that performs requests with doer.
+func NewRecorder(doer openai.Doer, opts ...Option) *Recorder {
+	return &Recorder{doer: doer, cfg: newConfig(opts)}
+}
+
+// Do implements openai.Doer.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Header: r.cfg.redactHeader(req.Header),
+		Body:   encodeBody([]byte(r.cfg.redact(string(body)))),
+	}
+
+	start := time.Now()
+
+	resp, err := r.doer.Do(req)
+	if err != nil {
+		return nil, err //nolint: wrapcheck // Errors are the Doer's.
+	}
+
+	resp.Body = &recordingBody{
+		rc:   resp.Body,
+		last: start,
+		done: func(chunks []Chunk) {
+			r.add(Interaction{
+				Request: recorded,
+				Response: RecordedResponse{
+					StatusCode: resp.StatusCode,
+					Header:     r.cfg.redactHeader(resp.Header),
+					Chunks:     chunks,
+				},
+			})
+		},
+		redact: r.cfg.redact,
+	}
+
+	return resp, nil
+}
+
+func (r *Recorder) add(i Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+}
+
+// Cassette returns a copy of the recorded cassette.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Save writes the recorded cassette to a file.
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+// readRequestBody reads a request's body, and replaces it so that it can be
+// sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading request body: %w", err)
+	}
+
+	if err := req.Body.Close(); err != nil {
+		return nil, fmt.Errorf("error closing request body: %w", err)
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
+
+// A recordingBody records a response body in chunks as it is read. Chunks end
+// at line boundaries, so that redaction patterns are matched against whole
+// lines even if a line arrives in several reads.
+type recordingBody struct {
+	rc      io.ReadCloser
+	last    time.Time
+	pending []byte
+	chunks  []Chunk
+	done    func([]Chunk)
+	redact  func(string) string
+	once    sync.Once
+}
+
+// Read implements io.Reader.
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.rc.Read(p)
+
+	b.pending = append(b.pending, p[:n]...)
+	if i := bytes.LastIndexByte(b.pending, '\n'); i >= 0 {
+		b.flush(i + 1)
+	}
+
+	if err == io.EOF {
+		b.finish()
+	}
+
+	return n, err //nolint: wrapcheck // Errors are the body's.
+}
+
+// Close implements io.Closer.
+func (b *recordingBody) Close() error {
+	b.finish()
+
+	return b.rc.Close() //nolint: wrapcheck // Errors are the body's.
+}
+
+// flush records the first n pending bytes as a chunk.
+func (b *recordingBody) flush(n int) {
+	if n == 0 {
+		return
+	}
+
+	now := time.Now()
+	b.chunks = append(b.chunks, newChunk(now.Sub(b.last), b.pending[:n], b.redact))
+	b.last = now
+	b.pending = b.pending[n:]
+}
+
+func (b *recordingBody) finish() {
+	b.once.Do(func() {
+		b.flush(len(b.pending))
+		b.done(b.chunks)
+	})
+}
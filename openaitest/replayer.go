@@ -0,0 +1,310 @@
+package openaitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jclem/openai-go"
+)
+
+// ErrNoInteraction is returned by a Replayer for requests that match no
+// unused interaction in its cassette.
+var ErrNoInteraction = errors.New("no matching interaction")
+
+var errBodyClosed = errors.New("read on closed body")
+
+// A Replayer is an openai.Doer that responds to requests with the interactions
+// of a cassette, without calling the API.
+//
+// A request matches an interaction with the same method, path and query, and
+// an equivalent body: JSON bodies are compared as JSON, so key order and
+// whitespace don't matter, and multipart/form-data bodies are compared by
+// their parts, ignoring their random boundaries. Each interaction is used
+// once, in order, so a cassette can hold several responses to the same
+// request.
+type Replayer struct {
+	cfg config
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+var _ openai.Doer = (*Replayer)(nil)
+
+// NewReplayer creates a Replayer that responds with the interactions of
+// cassette.
+func NewReplayer(cassette *Cassette, opts ...Option) *Replayer {
+	return &Replayer{
+		cfg:          newConfig(opts),
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// Do implements openai.Doer.
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The body is encoded as it would be recorded, so that bodies that can't
+	// be stored exactly (such as binary files) still match.
+	i, ok := r.match(req, normalizeBody(decodeBody(encodeBody([]byte(r.cfg.redact(string(body)))))))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, req.URL.RequestURI())
+	}
+
+	recorded := i.Response
+
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode: recorded.StatusCode,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Body:       &replayBody{req: req, chunks: recorded.Chunks, realTiming: r.cfg.realTiming},
+		Request:    req,
+	}, nil
+}
+
+// Remaining returns the number of interactions that have not been used.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+
+	for _, used := range r.used {
+		if !used {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (r *Replayer) match(req *http.Request, body string) (Interaction, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		recorded := interaction.Request
+
+		if r.used[i] ||
+			recorded.Method != req.Method ||
+			recorded.Path != req.URL.Path ||
+			recorded.Query != req.URL.RawQuery ||
+			normalizeBody(decodeBody(recorded.Body)) != body {
+			continue
+		}
+
+		r.used[i] = true
+
+		return interaction, true
+	}
+
+	return Interaction{}, false
+}
+
+// decodeBody decodes a request body encoded by encodeBody.
+func decodeBody(b json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		return s
+	}
+
+	return string(b)
+}
+
+// normalizeBody returns a canonical form of a JSON or multipart/form-data
+// body, or the body itself if it is neither.
+func normalizeBody(s string) string {
+	if parts, ok := normalizeMultipart(s); ok {
+		return parts
+	}
+
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+
+	// Maps are marshaled with sorted keys.
+	b, err := json.Marshal(v)
+	if err != nil {
+		return s
+	}
+
+	return string(b)
+}
+
+// A formPart is a part of a multipart/form-data body, without its boundary.
+type formPart struct {
+	Name        string `json:"name"`
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Data        string `json:"data"`
+}
+
+// normalizeMultipart returns a canonical form of a multipart/form-data body,
+// which lists its parts without the boundary. The boundary is read from the
+// body's first line, so the request's header isn't needed.
+func normalizeMultipart(s string) (string, bool) {
+	line, _, ok := strings.Cut(s, "\r\n")
+	if !ok || !strings.HasPrefix(line, "--") {
+		return "", false
+	}
+
+	mr := multipart.NewReader(strings.NewReader(s), line[2:])
+
+	var parts []formPart
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return "", false
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return "", false
+		}
+
+		parts = append(parts, formPart{
+			Name:        part.FormName(),
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Data:        string(data),
+		})
+	}
+
+	b, err := json.Marshal(parts)
+	if err != nil {
+		return "", false
+	}
+
+	return "multipart:" + string(b), true
+}
+
+// A replayBody serves recorded chunks as separate reads.
+type replayBody struct {
+	req        *http.Request
+	chunks     []Chunk
+	realTiming bool
+	buf        bytes.Reader
+	closed     bool
+}
+
+// Read implements io.Reader.
+func (b *replayBody) Read(p []byte) (int, error) {
+	if b.closed {
+		return 0, errBodyClosed
+	}
+
+	for b.buf.Len() == 0 {
+		if len(b.chunks) == 0 {
+			return 0, io.EOF
+		}
+
+		chunk := b.chunks[0]
+		b.chunks = b.chunks[1:]
+
+		if err := b.wait(time.Duration(chunk.Delay)); err != nil {
+			return 0, err
+		}
+
+		data, err := chunk.Bytes()
+		if err != nil {
+			return 0, err
+		}
+
+		b.buf.Reset(data)
+	}
+
+	return b.buf.Read(p) //nolint: wrapcheck // Reading from a bytes.Reader.
+}
+
+func (b *replayBody) wait(d time.Duration) error {
+	if !b.realTiming || d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-b.req.Context().Done():
+		return b.req.Context().Err() //nolint: wrapcheck // Context errors are returned as-is.
+	}
+}
+
+// Close implements io.Closer.
+func (b *replayBody) Close() error {
+	b.closed = true
+
+	return nil
+}
+
+// RecordEnv is the environment variable that makes NewCassette record
+// interactions with the live API rather than replay them.
+const RecordEnv = "OPENAITEST_RECORD"
+
+// NewCassette returns an openai.Doer for a test that replays the cassette at
+// path. If the RecordEnv environment variable is set, it instead records
+// requests made with live, and saves them to path when the test ends.
+//
+// When replaying, the test fails if the cassette can't be loaded, or if any
+// of its interactions are unused when the test ends.
+func NewCassette(tb testing.TB, path string, live openai.Doer, opts ...Option) openai.Doer {
+	tb.Helper()
+
+	if os.Getenv(RecordEnv) != "" {
+		recorder := NewRecorder(live, opts...)
+
+		tb.Cleanup(func() {
+			if err := recorder.Save(path); err != nil {
+				tb.Errorf("openaitest: %v", err)
+			}
+		})
+
+		return recorder
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		tb.Fatalf("openaitest: %v (set %s to record it)", err, RecordEnv)
+	}
+
+	replayer := NewReplayer(cassette, opts...)
+
+	tb.Cleanup(func() {
+		if n := replayer.Remaining(); n > 0 && !tb.Failed() {
+			tb.Errorf("openaitest: %d unused interaction(s) in %s", n, path)
+		}
+	})
+
+	return replayer
+}
@@ -0,0 +1,385 @@
+package openaitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jclem/openai-go"
+)
+
+// DefaultModels are the models a Server serves by default.
+var DefaultModels = []string{"gpt-4o", "gpt-4o-mini", "text-embedding-3-small"}
+
+// DefaultEmbeddingDimensions is the default number of dimensions of the
+// embeddings a Server creates.
+const DefaultEmbeddingDimensions = 1536
+
+// A Server is a fake OpenAI API server, listening on a local loopback address.
+// It serves chat completions (streaming and not), embeddings and models, and
+// records every request it receives.
+//
+// Chat completions respond with scripted replies (see Reply), or by echoing
+// the last message. Embeddings are deterministic: the same model and input
+// always have the same embedding. Token usage counts words as tokens.
+//
+// Requests must have an API key. Requests for models the server does not
+// serve fail with a 404, as they do in the API.
+type Server struct {
+	*httptest.Server
+
+	models     []string
+	dimensions int
+	replyFunc  func(*ChatRequest) Reply
+
+	mu          sync.Mutex
+	latency     time.Duration
+	rateLimit   int
+	ratePeriod  time.Duration
+	windowStart time.Time
+	windowCount int
+	replies     []Reply
+	failures    []*APIError
+	requests    []*Request
+	ids         int
+}
+
+// A ServerOpt is a functional option for configuring a Server.
+type ServerOpt func(*Server)
+
+// WithModels sets the models the server serves. The default is
+// DefaultModels.
+func WithModels(ids ...string) ServerOpt {
+	return func(s *Server) {
+		s.models = ids
+	}
+}
+
+// WithEmbeddingDimensions sets the number of dimensions of embeddings that
+// don't request a number. The default is DefaultEmbeddingDimensions.
+func WithEmbeddingDimensions(dimensions int) ServerOpt {
+	return func(s *Server) {
+		s.dimensions = dimensions
+	}
+}
+
+// WithReplyFunc sets a function that creates chat replies when no scripted
+// reply is queued. By default, the server replies with the content of the
+// last message.
+func WithReplyFunc(fn func(*ChatRequest) Reply) ServerOpt {
+	return func(s *Server) {
+		s.replyFunc = fn
+	}
+}
+
+// WithLatency delays every response by d.
+func WithLatency(d time.Duration) ServerOpt {
+	return func(s *Server) {
+		s.latency = d
+	}
+}
+
+// WithRateLimit limits the server to n requests per period. Requests over the
+// limit fail with a 429 and a Retry-After header.
+func WithRateLimit(n int, period time.Duration) ServerOpt {
+	return func(s *Server) {
+		s.rateLimit = n
+		s.ratePeriod = period
+	}
+}
+
+// NewServer starts a fake API server. The caller should call Close when
+// finished, to shut it down.
+func NewServer(opts ...ServerOpt) *Server {
+	s := &Server{
+		models:     DefaultModels,
+		dimensions: DefaultEmbeddingDimensions,
+		replyFunc:  echoReply,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/chat/completions", s.handleChatCompletions)
+	mux.HandleFunc("POST /v1/embeddings", s.handleEmbeddings)
+	mux.HandleFunc("GET /v1/models", s.handleListModels)
+	mux.HandleFunc("GET /v1/models/{id}", s.handleRetrieveModel)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &APIError{
+			StatusCode: http.StatusNotFound,
+			Type:       "invalid_request_error",
+			Message:    fmt.Sprintf("Unknown request URL: %s %s.", r.Method, r.URL.Path),
+		})
+	})
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+
+	return s
+}
+
+// BaseURL returns the server's base URL, for use with openai.WithBaseURL.
+func (s *Server) BaseURL() *url.URL {
+	u, err := url.Parse(s.URL + "/v1")
+	if err != nil {
+		panic(err)
+	}
+
+	return u
+}
+
+// NewClient creates a client for the server, with a test API key. Options are
+// applied after the server's, so they can override them.
+func (s *Server) NewClient(opts ...openai.ClientOpt) *openai.Client {
+	return openai.NewClient(append([]openai.ClientOpt{
+		openai.WithKey("sk-test"),
+		openai.WithBaseURL(s.BaseURL()),
+		openai.WithDoer(s.Client()),
+	}, opts...)...)
+}
+
+// Reply queues scripted replies to chat completion requests. Each reply is
+// used once, in order, before the server falls back to its reply function.
+func (s *Server) Reply(replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.replies = append(s.replies, replies...)
+}
+
+// FailNext queues errors to respond to the next requests with, one error per
+// request, whatever their endpoint.
+func (s *Server) FailNext(errs ...*APIError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, errs...)
+}
+
+// SetLatency sets the delay before every response.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = d
+}
+
+// Requests returns the requests the server has received, in order.
+func (s *Server) Requests() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Request(nil), s.requests...)
+}
+
+// LastRequest returns the last request the server received, or nil if it has
+// received none.
+func (s *Server) LastRequest() *Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.requests) == 0 {
+		return nil
+	}
+
+	return s.requests[len(s.requests)-1]
+}
+
+// A Request is a request received by a Server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Decode decodes the request's JSON body into v.
+func (r *Request) Decode(v any) error {
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("error decoding %s request: %w", r.Path, err)
+	}
+
+	return nil
+}
+
+// An APIError is an error response from a Server.
+type APIError struct {
+	// StatusCode is the response status code.
+	StatusCode int
+
+	// Type, Code and Message are the fields of the response's error object.
+	// Message defaults to the status text.
+	Type    string
+	Code    string
+	Message string
+
+	// RetryAfter, if set, is sent in the Retry-After header.
+	RetryAfter time.Duration
+}
+
+func writeError(w http.ResponseWriter, e *APIError) {
+	message := e.Message
+	if message == "" {
+		message = http.StatusText(e.StatusCode)
+	}
+
+	if e.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+	}
+
+	var code *string
+	if e.Code != "" {
+		code = &e.Code
+	}
+
+	writeJSON(w, e.StatusCode, map[string]any{
+		"error": map[string]any{
+			"message": message,
+			"type":    e.Type,
+			"param":   nil,
+			"code":    code,
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// middleware records requests, and applies the server's latency, failures,
+// rate limit and authentication, in that order.
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, &APIError{StatusCode: http.StatusBadRequest, Type: "invalid_request_error"})
+
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		latency, failure, limited := s.admit(&Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+		}, w.Header())
+
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		switch {
+		case failure != nil:
+			writeError(w, failure)
+		case limited != nil:
+			writeError(w, limited)
+		case r.Header.Get("Authorization") == "" && r.Header.Get("Api-Key") == "":
+			writeError(w, &APIError{
+				StatusCode: http.StatusUnauthorized,
+				Type:       "invalid_request_error",
+				Code:       "invalid_api_key",
+				Message:    "You didn't provide an API key.",
+			})
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// admit records a request, and returns its latency, its queued failure (if
+// any), and its rate limit error (if it is over the limit).
+func (s *Server) admit(req *Request, header http.Header) (time.Duration, *APIError, *APIError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, req)
+
+	var failure *APIError
+	if len(s.failures) > 0 {
+		failure = s.failures[0]
+		s.failures = s.failures[1:]
+	}
+
+	return s.latency, failure, s.limit(header)
+}
+
+// limit counts a request against the rate limit, and returns an error if it
+// is over the limit. It must be called with s.mu held.
+func (s *Server) limit(header http.Header) *APIError {
+	if s.rateLimit <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	if now.Sub(s.windowStart) >= s.ratePeriod {
+		s.windowStart = now
+		s.windowCount = 0
+	}
+
+	s.windowCount++
+
+	reset := s.ratePeriod - now.Sub(s.windowStart)
+
+	header.Set("X-Ratelimit-Limit-Requests", strconv.Itoa(s.rateLimit))
+	header.Set("X-Ratelimit-Remaining-Requests", strconv.Itoa(max(s.rateLimit-s.windowCount, 0)))
+	header.Set("X-Ratelimit-Reset-Requests", reset.String())
+
+	if s.windowCount <= s.rateLimit {
+		return nil
+	}
+
+	return &APIError{
+		StatusCode: http.StatusTooManyRequests,
+		Type:       "requests",
+		Code:       "rate_limit_exceeded",
+		Message:    fmt.Sprintf("Rate limit reached: limit %d per %s.", s.rateLimit, s.ratePeriod),
+		RetryAfter: reset,
+	}
+}
+
+// nextID returns a new response ID with a prefix.
+func (s *Server) nextID(prefix string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ids++
+
+	return fmt.Sprintf("%s-%d", prefix, s.ids)
+}
+
+// nextReply returns the next scripted reply to req, or the reply function's.
+func (s *Server) nextReply(req *ChatRequest) Reply {
+	s.mu.Lock()
+
+	if len(s.replies) > 0 {
+		reply := s.replies[0]
+		s.replies = s.replies[1:]
+		s.mu.Unlock()
+
+		return reply
+	}
+
+	s.mu.Unlock()
+
+	return s.replyFunc(req)
+}
@@ -0,0 +1,233 @@
+package openaitest_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/openaitest"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T, opts ...openaitest.ServerOpt) *openaitest.Server {
+	t.Helper()
+
+	server := openaitest.NewServer(opts...)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func statusCode(t *testing.T, err error) int {
+	t.Helper()
+
+	var statusErr openai.UnexpectedStatusCodeError
+	require.ErrorAs(t, err, &statusErr)
+
+	return statusErr.Actual
+}
+
+func TestServer_Chat(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+	client := server.NewClient()
+
+	server.Reply(openaitest.Reply{Content: "Hello there"})
+
+	comp, err := client.Chat.CreateCompletion(context.Background(), "gpt-4o", messages(), chat.WithUser("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hello there", *comp.Choices[0].Message.Content)
+	assert.Equal(t, "stop", comp.Choices[0].FinishReason)
+	assert.Equal(t, 1, comp.Usage.PromptTokens)
+	assert.Equal(t, 2, comp.Usage.CompletionTokens)
+
+	// Without a scripted reply, the server echoes the last message.
+	comp, err = client.Chat.CreateCompletion(context.Background(), "gpt-4o", messages())
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", *comp.Choices[0].Message.Content)
+
+	requests := server.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "/v1/chat/completions", requests[0].Path)
+	assert.Equal(t, "Bearer sk-test", requests[0].Header.Get("Authorization"))
+
+	var req openaitest.ChatRequest
+	require.NoError(t, requests[0].Decode(&req))
+	assert.Equal(t, "gpt-4o", req.Model)
+	assert.Equal(t, "alice", req.User)
+	assert.Equal(t, "Hi", *req.Messages[0].Content)
+
+	_, err = client.Chat.CreateCompletion(context.Background(), "gpt-5", messages())
+	assert.Equal(t, http.StatusNotFound, statusCode(t, err))
+}
+
+func TestServer_ChatFunctionCall(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+	client := server.NewClient()
+
+	server.Reply(
+		openaitest.Reply{FunctionCall: &openaitest.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+		openaitest.Reply{Content: "It is sunny in Paris."},
+	)
+
+	weather := chat.NewFunctionDefinition("get_weather", map[string]any{"type": "object"})
+
+	comp, err := client.Chat.CreateCompletion(context.Background(), "gpt-4o", messages(), chat.WithFunctions(weather))
+	require.NoError(t, err)
+	assert.Equal(t, "function_call", comp.Choices[0].FinishReason)
+
+	call, ok := comp.GetFunctionCallAt(0)
+	require.True(t, ok)
+	assert.Equal(t, "get_weather", call.Name)
+
+	var args string
+	require.NoError(t, json.Unmarshal(call.Arguments, &args))
+	assert.JSONEq(t, `{"city":"Paris"}`, args)
+
+	comp, err = client.Chat.CreateCompletion(context.Background(), "gpt-4o", append(messages(),
+		chat.NewMessage("assistant", chat.WithMessageFunctionCall(call)),
+		chat.NewMessage("function", chat.WithMessageName("get_weather"), chat.WithMessageContent("sunny"))))
+	require.NoError(t, err)
+	assert.Equal(t, "It is sunny in Paris.", *comp.Choices[0].Message.Content)
+}
+
+func TestServer_ChatStream(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+	server.Reply(openaitest.Reply{Content: "Hello there, friend"})
+
+	stream, err := server.NewClient().Chat.CreateStreamingCompletion(context.Background(), "gpt-4o", messages(),
+		chat.WithStreamIncludeUsage())
+	require.NoError(t, err)
+
+	var (
+		content      string
+		finishReason string
+		usage        *chat.Usage
+	)
+
+	for {
+		obj, err := stream.Next()
+		if err != nil {
+			require.ErrorIs(t, err, chat.ErrStreamDone)
+
+			break
+		}
+
+		if c, ok := obj.GetContentAt(0); ok {
+			content += c
+		}
+
+		if choice, ok := obj.GetChoiceAt(0); ok && choice.FinishReason != nil {
+			finishReason = *choice.FinishReason
+		}
+
+		if obj.Usage != nil {
+			usage = obj.Usage
+		}
+	}
+
+	require.NoError(t, stream.Close())
+	assert.Equal(t, "Hello there, friend", content)
+	assert.Equal(t, "stop", finishReason)
+	require.NotNil(t, usage)
+	assert.Equal(t, 3, usage.CompletionTokens)
+}
+
+func TestServer_Embeddings(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t, openaitest.WithEmbeddingDimensions(4))
+	client := server.NewClient()
+
+	resp, err := client.Embeddings.Create(context.Background(), "text-embedding-3-small", []string{"a", "b", "a"})
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 3)
+	assert.Len(t, resp.Data[0].Embedding, 4)
+	assert.Equal(t, resp.Data[0].Embedding, resp.Data[2].Embedding)
+	assert.NotEqual(t, resp.Data[0].Embedding, resp.Data[1].Embedding)
+	assert.Equal(t, openaitest.Embedding("text-embedding-3-small", "b", 4), resp.Data[1].Embedding)
+	assert.Equal(t, 3, resp.Usage.PromptTokens)
+}
+
+func TestServer_Models(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t, openaitest.WithModels("gpt-4o", "o3"))
+	client := server.NewClient()
+
+	list, err := client.Models.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, list.Data, 2)
+	assert.Equal(t, "o3", list.Data[1].ID)
+
+	model, err := client.Models.Retrieve(context.Background(), "o3")
+	require.NoError(t, err)
+	assert.Equal(t, "o3", model.ID)
+
+	exists, err := client.Models.Exists(context.Background(), "gpt-4")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestServer_Errors(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t)
+	client := server.NewClient()
+
+	server.FailNext(&openaitest.APIError{StatusCode: http.StatusServiceUnavailable, Type: "server_error"})
+
+	_, err := client.Models.List(context.Background())
+	assert.Equal(t, http.StatusServiceUnavailable, statusCode(t, err))
+
+	_, err = client.Models.List(context.Background())
+	require.NoError(t, err)
+
+	_, err = server.NewClient(openai.WithKey("")).Models.List(context.Background())
+	assert.Equal(t, http.StatusUnauthorized, statusCode(t, err))
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t, openaitest.WithRateLimit(1, time.Minute))
+	client := server.NewClient()
+
+	_, err := client.Models.List(context.Background())
+	require.NoError(t, err)
+
+	_, err = client.Models.List(context.Background())
+
+	var statusErr openai.UnexpectedStatusCodeError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.Actual)
+	assert.Equal(t, "60", statusErr.Response.Header.Get("Retry-After"))
+	assert.Equal(t, "0", statusErr.Response.Header.Get("X-Ratelimit-Remaining-Requests"))
+}
+
+func TestServer_Latency(t *testing.T) {
+	t.Parallel()
+
+	server := newServer(t, openaitest.WithLatency(time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := server.NewClient().Models.List(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	server.SetLatency(0)
+
+	_, err = server.NewClient().Models.List(context.Background())
+	require.NoError(t, err)
+}
@@ -0,0 +1,282 @@
+// Package otel provides an OpenTelemetry openai.Instrumenter, which creates
+// spans and records metrics for model operations following the OpenTelemetry
+// semantic conventions for generative AI.
+//
+// It is a separate module, so that the client does not depend on
+// OpenTelemetry.
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jclem/openai-go"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name of the Instrumenter's tracer and
+// meter.
+const ScopeName = "github.com/jclem/openai-go/otel"
+
+// DefaultSystem is the default value of the "gen_ai.system" attribute.
+const DefaultSystem = "openai"
+
+// Semantic convention attribute keys.
+const (
+	AttrOperationName         = attribute.Key("gen_ai.operation.name")
+	AttrSystem                = attribute.Key("gen_ai.system")
+	AttrRequestModel          = attribute.Key("gen_ai.request.model")
+	AttrRequestTemperature    = attribute.Key("gen_ai.request.temperature")
+	AttrRequestTopP           = attribute.Key("gen_ai.request.top_p")
+	AttrRequestMaxTokens      = attribute.Key("gen_ai.request.max_tokens")
+	AttrResponseID            = attribute.Key("gen_ai.response.id")
+	AttrResponseModel         = attribute.Key("gen_ai.response.model")
+	AttrResponseFinishReasons = attribute.Key("gen_ai.response.finish_reasons")
+	AttrUsageInputTokens      = attribute.Key("gen_ai.usage.input_tokens")
+	AttrUsageOutputTokens     = attribute.Key("gen_ai.usage.output_tokens")
+	AttrTokenType             = attribute.Key("gen_ai.token.type")
+	AttrServerAddress         = attribute.Key("server.address")
+	AttrErrorType             = attribute.Key("error.type")
+)
+
+// Metric names.
+const (
+	MetricTokenUsage        = "gen_ai.client.token.usage"
+	MetricOperationDuration = "gen_ai.client.operation.duration"
+	MetricTimeToFirstChunk  = "gen_ai.client.operation.time_to_first_chunk"
+)
+
+// EventFirstToken is the name of the span event added when a stream receives
+// its first token.
+const EventFirstToken = "gen_ai.first_token"
+
+// durationBuckets are the histogram bucket boundaries for durations, in
+// seconds.
+var durationBuckets = []float64{
+	0.01, 0.02, 0.04, 0.08, 0.16, 0.32, 0.64, 1.28, 2.56, 5.12, 10.24, 20.48, 40.96, 81.92,
+}
+
+// tokenBuckets are the histogram bucket boundaries for token counts.
+var tokenBuckets = []float64{
+	1, 4, 16, 64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216, 67108864,
+}
+
+// An Option is a functional option for configuring an Instrumenter.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	system         string
+}
+
+// WithTracerProvider sets the tracer provider. The default is the global
+// tracer provider.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider. The default is the global meter
+// provider.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithSystem sets the "gen_ai.system" attribute, such as "az.ai.openai" for
+// Azure OpenAI. The default is DefaultSystem.
+func WithSystem(system string) Option {
+	return func(c *config) {
+		c.system = system
+	}
+}
+
+// An Instrumenter is an openai.Instrumenter that creates a span per model
+// operation, and records token usage, operation duration and (for streams)
+// time to first token histograms.
+type Instrumenter struct {
+	tracer trace.Tracer
+	system string
+
+	tokenUsage metric.Int64Histogram
+	duration   metric.Float64Histogram
+	firstToken metric.Float64Histogram
+}
+
+var _ openai.Instrumenter = (*Instrumenter)(nil)
+
+// NewInstrumenter creates a new Instrumenter.
+func NewInstrumenter(opts ...Option) (*Instrumenter, error) {
+	cfg := config{system: DefaultSystem}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.tracerProvider == nil {
+		cfg.tracerProvider = otelapi.GetTracerProvider()
+	}
+
+	if cfg.meterProvider == nil {
+		cfg.meterProvider = otelapi.GetMeterProvider()
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+
+	tokenUsage, err := meter.Int64Histogram(MetricTokenUsage,
+		metric.WithDescription("Number of input and output tokens used."),
+		metric.WithUnit("{token}"),
+		metric.WithExplicitBucketBoundaries(tokenBuckets...))
+	if err != nil {
+		return nil, fmt.Errorf("error creating token usage histogram: %w", err)
+	}
+
+	duration, err := meter.Float64Histogram(MetricOperationDuration,
+		metric.WithDescription("Duration of model operations."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...))
+	if err != nil {
+		return nil, fmt.Errorf("error creating operation duration histogram: %w", err)
+	}
+
+	firstToken, err := meter.Float64Histogram(MetricTimeToFirstChunk,
+		metric.WithDescription("Time to the first token of streaming model operations."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...))
+	if err != nil {
+		return nil, fmt.Errorf("error creating time to first token histogram: %w", err)
+	}
+
+	return &Instrumenter{
+		tracer:     cfg.tracerProvider.Tracer(ScopeName),
+		system:     cfg.system,
+		tokenUsage: tokenUsage,
+		duration:   duration,
+		firstToken: firstToken,
+	}, nil
+}
+
+// StartOperation implements openai.Instrumenter.
+func (i *Instrumenter) StartOperation(
+	ctx context.Context,
+	op openai.Operation,
+) (context.Context, openai.OperationObserver) {
+	attrs := []attribute.KeyValue{
+		AttrOperationName.String(op.Name),
+		AttrSystem.String(i.system),
+		AttrRequestModel.String(op.RequestModel),
+	}
+
+	if op.ServerAddress != "" {
+		attrs = append(attrs, AttrServerAddress.String(op.ServerAddress))
+	}
+
+	attrs = slices.Clip(attrs)
+	spanAttrs := slices.Clone(attrs)
+
+	if op.Temperature != nil {
+		spanAttrs = append(spanAttrs, AttrRequestTemperature.Float64(*op.Temperature))
+	}
+
+	if op.TopP != nil {
+		spanAttrs = append(spanAttrs, AttrRequestTopP.Float64(*op.TopP))
+	}
+
+	if op.MaxTokens != nil {
+		spanAttrs = append(spanAttrs, AttrRequestMaxTokens.Int(*op.MaxTokens))
+	}
+
+	ctx, span := i.tracer.Start(ctx, op.Name+" "+op.RequestModel,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(spanAttrs...))
+
+	return ctx, &observer{
+		instrumenter: i,
+		ctx:          ctx,
+		span:         span,
+		attrs:        attrs,
+		start:        time.Now(),
+	}
+}
+
+type observer struct {
+	instrumenter *Instrumenter
+	ctx          context.Context //nolint: containedctx // Used to record metrics when the operation ends.
+	span         trace.Span
+	attrs        []attribute.KeyValue
+	start        time.Time
+}
+
+func (o *observer) FirstToken() {
+	o.span.AddEvent(EventFirstToken)
+	o.instrumenter.firstToken.Record(o.ctx, time.Since(o.start).Seconds(),
+		metric.WithAttributes(o.attrs...))
+}
+
+func (o *observer) End(result openai.OperationResult, err error) {
+	defer o.span.End()
+
+	attrs := o.attrs // Clipped, so appending copies it.
+	if result.ResponseModel != "" {
+		attrs = append(attrs, AttrResponseModel.String(result.ResponseModel))
+	}
+
+	if err != nil {
+		errType := errorType(err)
+		attrs = append(attrs, AttrErrorType.String(errType))
+
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, errType)
+	}
+
+	o.span.SetAttributes(attrs[len(o.attrs):]...)
+
+	if result.ResponseID != "" {
+		o.span.SetAttributes(AttrResponseID.String(result.ResponseID))
+	}
+
+	if len(result.FinishReasons) > 0 {
+		o.span.SetAttributes(AttrResponseFinishReasons.StringSlice(result.FinishReasons))
+	}
+
+	o.instrumenter.duration.Record(o.ctx, time.Since(o.start).Seconds(), metric.WithAttributes(attrs...))
+
+	if err != nil {
+		return
+	}
+
+	o.span.SetAttributes(
+		AttrUsageInputTokens.Int(result.InputTokens),
+		AttrUsageOutputTokens.Int(result.OutputTokens))
+
+	o.instrumenter.tokenUsage.Record(o.ctx, int64(result.InputTokens),
+		metric.WithAttributes(append(attrs, AttrTokenType.String("input"))...))
+
+	if result.OutputTokens > 0 {
+		o.instrumenter.tokenUsage.Record(o.ctx, int64(result.OutputTokens),
+			metric.WithAttributes(append(attrs, AttrTokenType.String("output"))...))
+	}
+}
+
+// errorType returns the "error.type" of an error: the HTTP status code of
+// unexpected status errors, and "_OTHER" (the semantic conventions' fallback)
+// otherwise.
+func errorType(err error) string {
+	var statusErr openai.UnexpectedStatusCodeError
+	if errors.As(err, &statusErr) {
+		return strconv.Itoa(statusErr.Actual)
+	}
+
+	return "_OTHER"
+}
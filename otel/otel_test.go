@@ -0,0 +1,222 @@
+package otel_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/otel"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type doerFunc func(*http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+type fixture struct {
+	spans  *tracetest.SpanRecorder
+	reader *sdkmetric.ManualReader
+	client *openai.Client
+}
+
+func newFixture(t *testing.T, status int, body string) fixture {
+	t.Helper()
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	inst, err := otel.NewInstrumenter(
+		otel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		otel.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	require.NoError(t, err)
+
+	client := openai.NewClient(
+		openai.WithInstrumenter(inst),
+		openai.WithDoer(doerFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: status,
+				Body:       readCloser{strings.NewReader(body)},
+			}, nil
+		})),
+	)
+
+	return fixture{spans: spans, reader: reader, client: client}
+}
+
+type readCloser struct{ *strings.Reader }
+
+func (readCloser) Close() error { return nil }
+
+func (f fixture) metrics(t *testing.T) map[string]metricdata.Aggregation {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, f.reader.Collect(context.Background(), &rm))
+
+	metrics := make(map[string]metricdata.Aggregation)
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	return metrics
+}
+
+func attrs(set attribute.Set) map[attribute.Key]any {
+	m := make(map[attribute.Key]any)
+	for _, kv := range set.ToSlice() {
+		m[kv.Key] = kv.Value.AsInterface()
+	}
+
+	return m
+}
+
+func messages() []chat.Message {
+	return []chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hi"))}
+}
+
+func TestInstrumenter_Chat(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t, http.StatusOK, `{"id":"cmpl-1","model":"gpt-4-0613",`+
+		`"choices":[{"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":2}}`)
+
+	_, err := f.client.Chat.CreateCompletion(context.Background(), "gpt-4", messages(),
+		chat.WithTemperature(0.5))
+	require.NoError(t, err)
+
+	spans := f.spans.Ended()
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+	assert.Equal(t, "chat gpt-4", span.Name())
+	assert.Equal(t, map[attribute.Key]any{
+		otel.AttrOperationName:         "chat",
+		otel.AttrSystem:                "openai",
+		otel.AttrRequestModel:          "gpt-4",
+		otel.AttrServerAddress:         "api.openai.com",
+		otel.AttrRequestTemperature:    0.5,
+		otel.AttrResponseModel:         "gpt-4-0613",
+		otel.AttrResponseID:            "cmpl-1",
+		otel.AttrResponseFinishReasons: []string{"stop"},
+		otel.AttrUsageInputTokens:      int64(5),
+		otel.AttrUsageOutputTokens:     int64(2),
+	}, attrs(attribute.NewSet(span.Attributes()...)))
+
+	metrics := f.metrics(t)
+
+	usage, ok := metrics[otel.MetricTokenUsage].(metricdata.Histogram[int64])
+	require.True(t, ok)
+	require.Len(t, usage.DataPoints, 2)
+
+	tokens := make(map[any]int64)
+	for _, dp := range usage.DataPoints {
+		tokenType, _ := dp.Attributes.Value(otel.AttrTokenType)
+		tokens[tokenType.AsString()] = dp.Sum
+	}
+
+	assert.Equal(t, map[any]int64{"input": 5, "output": 2}, tokens)
+
+	duration, ok := metrics[otel.MetricOperationDuration].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, duration.DataPoints, 1)
+	assert.Equal(t, uint64(1), duration.DataPoints[0].Count)
+}
+
+func TestInstrumenter_Stream(t *testing.T) {
+	t.Parallel()
+
+	// The first chunk has only a role and empty content, so it isn't the first
+	// token.
+	f := newFixture(t, http.StatusOK, ""+
+		"data: {\"id\":\"cmpl-1\",\"choices\":[{\"delta\":{\"role\":\"assistant\",\"content\":\"\"}}]}\n\n"+
+		"data: {\"id\":\"cmpl-1\",\"model\":\"gpt-4-0613\",\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n"+
+		"data: {\"id\":\"cmpl-1\",\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\n"+
+		"data: [DONE]\n\n")
+
+	stream, err := f.client.Chat.CreateStreamingCompletion(context.Background(), "gpt-4", messages())
+	require.NoError(t, err)
+
+	for {
+		if _, err := stream.Next(); err != nil {
+			require.ErrorIs(t, err, chat.ErrStreamDone)
+
+			break
+		}
+	}
+
+	require.NoError(t, stream.Close())
+
+	spans := f.spans.Ended()
+	require.Len(t, spans, 1)
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, otel.EventFirstToken, spans[0].Events()[0].Name)
+
+	ttft, ok := f.metrics(t)[otel.MetricTimeToFirstChunk].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, ttft.DataPoints, 1)
+	assert.Equal(t, uint64(1), ttft.DataPoints[0].Count)
+}
+
+func TestInstrumenter_StreamClosedEarly(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t, http.StatusOK, ""+
+		"data: {\"id\":\"cmpl-1\",\"choices\":[{\"delta\":{\"role\":\"assistant\",\"content\":\"\"}}]}\n\n"+
+		"data: {\"id\":\"cmpl-1\",\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n"+
+		"data: [DONE]\n\n")
+
+	stream, err := f.client.Chat.CreateStreamingCompletion(context.Background(), "gpt-4", messages())
+	require.NoError(t, err)
+
+	_, err = stream.Next()
+	require.NoError(t, err)
+	require.NoError(t, stream.Close())
+
+	spans := f.spans.Ended()
+	require.Len(t, spans, 1)
+
+	for _, event := range spans[0].Events() {
+		assert.NotEqual(t, otel.EventFirstToken, event.Name)
+	}
+
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "_OTHER", attrs(attribute.NewSet(spans[0].Attributes()...))[otel.AttrErrorType])
+}
+
+func TestInstrumenter_Error(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t, http.StatusTooManyRequests, `{"error":{"message":"Rate limited"}}`)
+
+	_, err := f.client.Embeddings.Create(context.Background(), "text-embedding-3-small", []string{"Hi"})
+	require.Error(t, err)
+
+	spans := f.spans.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "embeddings text-embedding-3-small", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "429", attrs(attribute.NewSet(spans[0].Attributes()...))[otel.AttrErrorType])
+
+	metrics := f.metrics(t)
+	assert.NotContains(t, metrics, otel.MetricTokenUsage)
+
+	duration, ok := metrics[otel.MetricOperationDuration].(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, duration.DataPoints, 1)
+
+	errType, _ := duration.DataPoints[0].Attributes.Value(otel.AttrErrorType)
+	assert.Equal(t, "429", errType.AsString())
+}
@@ -0,0 +1,302 @@
+// Package assistants provides a client for the OpenAI assistants API,
+// including threads, messages and runs.
+//
+// The assistants API is in beta, and every request this package makes sets
+// the "OpenAI-Beta: assistants=v2" header.
+package assistants
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+const (
+	betaHeader = "OpenAI-Beta"
+	betaValue  = "assistants=v2"
+)
+
+// Tool types.
+const (
+	ToolTypeCodeInterpreter = "code_interpreter"
+	ToolTypeFileSearch      = "file_search"
+	ToolTypeFunction        = "function"
+)
+
+// A Tool is a tool enabled on an assistant or run.
+type Tool struct {
+	Type     string    `json:"type"`
+	Function *Function `json:"function,omitempty"`
+}
+
+// A Function describes a function tool.
+type Function struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+	Strict      *bool  `json:"strict,omitempty"`
+}
+
+// NewFunctionTool creates a new function tool.
+//
+// The parameters must be a JSON-serializable JSON Schema object.
+func NewFunctionTool(name string, description string, parameters any) Tool {
+	return Tool{
+		Type:     ToolTypeFunction,
+		Function: &Function{Name: name, Description: description, Parameters: parameters},
+	}
+}
+
+// NewCodeInterpreterTool creates a new code interpreter tool.
+func NewCodeInterpreterTool() Tool {
+	return Tool{Type: ToolTypeCodeInterpreter}
+}
+
+// NewFileSearchTool creates a new file search tool.
+func NewFileSearchTool() Tool {
+	return Tool{Type: ToolTypeFileSearch}
+}
+
+// ToolResources are resources made available to an assistant's tools.
+type ToolResources struct {
+	CodeInterpreter *CodeInterpreterResources `json:"code_interpreter,omitempty"`
+	FileSearch      *FileSearchResources      `json:"file_search,omitempty"`
+}
+
+// CodeInterpreterResources are the files available to the code interpreter
+// tool.
+type CodeInterpreterResources struct {
+	FileIDs []string `json:"file_ids"`
+}
+
+// FileSearchResources are the vector stores available to the file search tool.
+type FileSearchResources struct {
+	VectorStoreIDs []string `json:"vector_store_ids"`
+}
+
+// An Assistant is an assistant that can call the model and use tools.
+type Assistant struct {
+	ID            string            `json:"id"`
+	Object        string            `json:"object"`
+	CreatedAt     int64             `json:"created_at"`
+	Name          *string           `json:"name"`
+	Description   *string           `json:"description"`
+	Model         string            `json:"model"`
+	Instructions  *string           `json:"instructions"`
+	Tools         []Tool            `json:"tools"`
+	ToolResources *ToolResources    `json:"tool_resources,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Temperature   *float64          `json:"temperature,omitempty"`
+	TopP          *float64          `json:"top_p,omitempty"`
+}
+
+type assistantRequest struct {
+	apiKey string
+
+	Model         string            `json:"model,omitempty"`
+	Name          *string           `json:"name,omitempty"`
+	Description   *string           `json:"description,omitempty"`
+	Instructions  *string           `json:"instructions,omitempty"`
+	Tools         []Tool            `json:"tools,omitempty"`
+	ToolResources *ToolResources    `json:"tool_resources,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Temperature   *float64          `json:"temperature,omitempty"`
+	TopP          *float64          `json:"top_p,omitempty"`
+}
+
+// AssistantOpt is a functional option for configuring an assistant creation or
+// update request.
+type AssistantOpt func(*assistantRequest)
+
+// WithModel sets the model of the assistant. It is only needed when updating
+// an assistant.
+func WithModel(model string) AssistantOpt {
+	return func(r *assistantRequest) {
+		r.Model = model
+	}
+}
+
+// WithName sets the name of the assistant.
+func WithName(name string) AssistantOpt {
+	return func(r *assistantRequest) {
+		r.Name = &name
+	}
+}
+
+// WithDescription sets the description of the assistant.
+func WithDescription(description string) AssistantOpt {
+	return func(r *assistantRequest) {
+		r.Description = &description
+	}
+}
+
+// WithInstructions sets the system instructions of the assistant.
+func WithInstructions(instructions string) AssistantOpt {
+	return func(r *assistantRequest) {
+		r.Instructions = &instructions
+	}
+}
+
+// WithTools sets the tools of the assistant.
+func WithTools(tools ...Tool) AssistantOpt {
+	return func(r *assistantRequest) {
+		r.Tools = tools
+	}
+}
+
+// WithToolResources sets the tool resources of the assistant.
+func WithToolResources(resources ToolResources) AssistantOpt {
+	return func(r *assistantRequest) {
+		r.ToolResources = &resources
+	}
+}
+
+// WithMetadata sets the metadata of the assistant.
+func WithMetadata(metadata map[string]string) AssistantOpt {
+	return func(r *assistantRequest) {
+		r.Metadata = metadata
+	}
+}
+
+// WithTemperature sets the temperature of the assistant.
+func WithTemperature(temperature float64) AssistantOpt {
+	return func(r *assistantRequest) {
+		r.Temperature = &temperature
+	}
+}
+
+// WithTopP sets the top p of the assistant.
+func WithTopP(topP float64) AssistantOpt {
+	return func(r *assistantRequest) {
+		r.TopP = &topP
+	}
+}
+
+// WithAPIKey sets the API key for the assistant request.
+func WithAPIKey(apiKey string) AssistantOpt {
+	return func(r *assistantRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// A DeleteResponse is a response to a deletion request.
+type DeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
+// ListOpt is a functional option for configuring a list request.
+type ListOpt = service.ListOpt
+
+// WithLimit sets the maximum number of objects in a page.
+func WithLimit(limit int) ListOpt {
+	return service.WithLimit(limit)
+}
+
+// WithOrder sets the order ("asc" or "desc") of the listed objects.
+func WithOrder(order string) ListOpt {
+	return service.WithOrder(order)
+}
+
+// WithAfter sets the cursor to list objects after, which is the LastID of the
+// previous page.
+func WithAfter(after string) ListOpt {
+	return service.WithAfter(after)
+}
+
+// An AssistantListResponse is a page of assistants.
+type AssistantListResponse = service.Page[Assistant]
+
+// Service is a service wrapping an OpenAI-compatible assistants API.
+type Service service.Service
+
+func (h *Service) client() *service.Client {
+	return h.Client.WithHeader(betaHeader, betaValue)
+}
+
+// do performs a request, decoding the response into v.
+func (h *Service) do(ctx context.Context, method, path string, body any, apiKey string, v any) error {
+	httpReq, err := h.client().NewRequestWithContext(ctx, method, path, body, service.WithAPIKey(apiKey))
+	if err != nil {
+		return fmt.Errorf("error creating assistants request: %w", err)
+	}
+
+	if _, err := h.Client.Do(httpReq, v); err != nil { //nolint: bodyclose // False positive.
+		return fmt.Errorf("error performing assistants request: %w", err)
+	}
+
+	return nil
+}
+
+// CreateAssistant creates an assistant.
+func (h *Service) CreateAssistant(ctx context.Context, model string, opts ...AssistantOpt) (*Assistant, error) {
+	req := assistantRequest{Model: model}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	var resp Assistant
+	if err := h.do(ctx, http.MethodPost, "/assistants", req, req.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// RetrieveAssistant retrieves an assistant by its ID.
+func (h *Service) RetrieveAssistant(ctx context.Context, id string) (*Assistant, error) {
+	var resp Assistant
+	if err := h.do(ctx, http.MethodGet, "/assistants/"+url.PathEscape(id), nil, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// UpdateAssistant updates an assistant. Only the given options are changed.
+func (h *Service) UpdateAssistant(ctx context.Context, id string, opts ...AssistantOpt) (*Assistant, error) {
+	var req assistantRequest
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	var resp Assistant
+	if err := h.do(ctx, http.MethodPost, "/assistants/"+url.PathEscape(id), req, req.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// DeleteAssistant deletes an assistant by its ID.
+func (h *Service) DeleteAssistant(ctx context.Context, id string) (*DeleteResponse, error) {
+	var resp DeleteResponse
+	if err := h.do(ctx, http.MethodDelete, "/assistants/"+url.PathEscape(id), nil, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListAssistants lists a page of assistants.
+func (h *Service) ListAssistants(ctx context.Context, opts ...ListOpt) (*AssistantListResponse, error) {
+	resp, err := service.ListPage[Assistant](ctx, h.client(), "/assistants", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing assistants: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAllAssistants returns an iterator over every assistant, following
+// pagination cursors.
+func (h *Service) ListAllAssistants(ctx context.Context, opts ...ListOpt) iter.Seq2[Assistant, error] {
+	return service.NewPager[Assistant](h.client(), "/assistants", nil, opts...).All(ctx)
+}
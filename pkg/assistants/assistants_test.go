@@ -0,0 +1,263 @@
+package assistants_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/assistants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResponse(body string) *http.Response {
+	r := &http.Response{}
+	r.StatusCode = http.StatusOK
+	r.Body = httptesting.NewTestBody(strings.NewReader(body))
+
+	return r
+}
+
+func TestHTTPClient_CreateAssistant(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req  *http.Request
+		body map[string]any
+	)
+
+	doer := httptesting.DoerFunc(func(r *http.Request) (*http.Response, error) {
+		req = r
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		return newResponse(`{"id": "asst_1", "model": "gpt-4o", "tools": [{"type": "code_interpreter"}]}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*assistants.Service)(svc)
+
+	asst, err := c.CreateAssistant(
+		context.Background(),
+		"gpt-4o",
+		assistants.WithName("Helper"),
+		assistants.WithTools(assistants.NewCodeInterpreterTool()),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "assistants=v2", req.Header.Get("OpenAI-Beta"))
+	assert.Equal(t, "Bearer api-key", req.Header.Get("Authorization"))
+	assert.Equal(t, "/v1/assistants", req.URL.Path)
+	assert.Equal(t, map[string]any{
+		"model": "gpt-4o",
+		"name":  "Helper",
+		"tools": []any{map[string]any{"type": "code_interpreter"}},
+	}, body)
+	assert.Equal(t, "asst_1", asst.ID)
+}
+
+func TestHTTPClient_ListMessagesSetsBetaHeader(t *testing.T) {
+	t.Parallel()
+
+	var req *http.Request
+
+	doer := httptesting.DoerFunc(func(r *http.Request) (*http.Response, error) {
+		req = r
+
+		return newResponse(`{"data": [{"id": "msg_1", "role": "assistant",
+			"content": [{"type": "text", "text": {"value": "Hi", "annotations": []}}]}]}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*assistants.Service)(svc)
+
+	page, err := c.ListMessages(context.Background(), "thread_1", assistants.WithRunID("run_1"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "assistants=v2", req.Header.Get("OpenAI-Beta"))
+	assert.Equal(t, "/v1/threads/thread_1/messages", req.URL.Path)
+	assert.Equal(t, "run_1", req.URL.Query().Get("run_id"))
+	assert.Equal(t, "Hi", page.Data[0].Text())
+}
+
+func TestHTTPClient_CreateRunStreaming(t *testing.T) {
+	t.Parallel()
+
+	// The requires_action event splits its data across two data fields, which
+	// the stream joins with a newline.
+	sse := `event: thread.run.created
+data: {"id": "run_1", "status": "queued"}
+
+event: thread.message.delta
+data: {"id": "msg_1", "delta": {"content": [{"index": 0, "type": "text", "text": {"value": "Hel"}}]}}
+
+event: thread.message.delta
+data: {"id": "msg_1", "delta": {"content": [{"index": 0, "type": "text", "text": {"value": "lo"}}]}}
+
+event: thread.run.step.delta
+data: {"id": "step_1", "delta": {"step_details": {"type": "tool_calls", "tool_calls": [{"index": 0, "type": "function"}]}}}
+
+event: thread.run.requires_action
+data: {"id": "run_1", "status": "requires_action", "required_action": {"type": "submit_tool_outputs",
+data:  "submit_tool_outputs": {"tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "f", "arguments": "{}"}}]}}}
+
+event: done
+data: [DONE]
+
+`
+
+	doer := httptesting.NewTestDoer(newResponse(sse), nil)
+	svc := service.New(openai.DefaultBaseURL, "api-key", &doer)
+	c := (*assistants.Service)(svc)
+
+	stream, err := c.CreateRunStreaming(context.Background(), "thread_1", "asst_1")
+	require.NoError(t, err)
+
+	var (
+		text   string
+		action *assistants.Run
+		types  []string
+	)
+
+	for {
+		evt, err := stream.Next()
+		if errors.Is(err, assistants.ErrStreamDone) {
+			break
+		}
+
+		require.NoError(t, err)
+
+		types = append(types, evt.Type)
+
+		switch evt.Type {
+		case assistants.EventMessageDelta:
+			text += evt.MessageDelta.Text()
+		case assistants.EventRunRequiresAction:
+			action = evt.Run
+		}
+	}
+
+	assert.Equal(t, "Hello", text)
+	assert.Equal(t, []string{
+		assistants.EventRunCreated,
+		assistants.EventMessageDelta,
+		assistants.EventMessageDelta,
+		assistants.EventRunStepDelta,
+		assistants.EventRunRequiresAction,
+	}, types)
+	require.NotNil(t, action)
+	assert.Equal(t, "call_1", action.RequiredAction.SubmitToolOutputs.ToolCalls[0].ID)
+	require.NoError(t, stream.Close())
+}
+
+func TestHTTPClient_WaitForRun(t *testing.T) {
+	t.Parallel()
+
+	var (
+		retrieves int
+		submitted map[string]any
+	)
+
+	doer := httptesting.DoerFunc(func(r *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(r.URL.Path, "/submit_tool_outputs") {
+			if err := json.NewDecoder(r.Body).Decode(&submitted); err != nil {
+				return nil, err
+			}
+
+			return newResponse(`{"id": "run_1", "thread_id": "thread_1", "status": "queued"}`), nil
+		}
+
+		retrieves++
+
+		switch retrieves {
+		case 1:
+			return newResponse(`{"id": "run_1", "thread_id": "thread_1", "status": "requires_action",
+				"required_action": {"type": "submit_tool_outputs", "submit_tool_outputs": {"tool_calls": [
+					{"id": "call_1", "type": "function", "function": {"name": "add", "arguments": "{\"a\":1,\"b\":2}"}}
+				]}}}`), nil
+		case 2:
+			return newResponse(`{"id": "run_1", "thread_id": "thread_1", "status": "in_progress"}`), nil
+		default:
+			return newResponse(`{"id": "run_1", "thread_id": "thread_1", "status": "completed"}`), nil
+		}
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*assistants.Service)(svc)
+
+	run, err := c.WaitForRun(context.Background(), "thread_1", "run_1", time.Millisecond, assistants.ToolHandlers{
+		"add": func(_ context.Context, arguments string) (string, error) {
+			var args struct{ A, B int }
+			if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+				return "", err
+			}
+
+			b, err := json.Marshal(args.A + args.B)
+
+			return string(b), err
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, assistants.RunStatusCompleted, run.Status)
+	assert.Equal(t, 3, retrieves)
+	assert.Equal(t, map[string]any{
+		"tool_outputs": []any{map[string]any{"tool_call_id": "call_1", "output": "3"}},
+	}, submitted)
+}
+
+func TestHTTPClient_WaitForRunMissingHandler(t *testing.T) {
+	t.Parallel()
+
+	doer := httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+		return newResponse(`{"id": "run_1", "thread_id": "thread_1", "status": "requires_action",
+			"required_action": {"type": "submit_tool_outputs", "submit_tool_outputs": {"tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "missing", "arguments": "{}"}}
+			]}}}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*assistants.Service)(svc)
+
+	_, err := c.WaitForRun(context.Background(), "thread_1", "run_1", time.Millisecond, nil)
+	require.ErrorIs(t, err, assistants.ErrNoToolHandler)
+}
+
+func TestHTTPClient_WaitForRunZeroInterval(t *testing.T) {
+	t.Parallel()
+
+	doer := httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+		return newResponse(`{"id": "run_1", "thread_id": "thread_1", "status": "completed"}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*assistants.Service)(svc)
+
+	run, err := c.WaitForRun(context.Background(), "thread_1", "run_1", 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, assistants.RunStatusCompleted, run.Status)
+}
+
+func TestHTTPClient_WaitForRunUnsupportedAction(t *testing.T) {
+	t.Parallel()
+
+	doer := httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+		return newResponse(`{"id": "run_1", "thread_id": "thread_1", "status": "requires_action",
+			"required_action": {"type": "something_else"}}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*assistants.Service)(svc)
+
+	_, err := c.WaitForRun(context.Background(), "thread_1", "run_1", time.Millisecond, nil)
+	require.ErrorIs(t, err, assistants.ErrUnsupportedRequiredAction)
+}
@@ -0,0 +1,403 @@
+package assistants
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// Run statuses.
+const (
+	RunStatusQueued         = "queued"
+	RunStatusInProgress     = "in_progress"
+	RunStatusRequiresAction = "requires_action"
+	RunStatusCancelling     = "cancelling"
+	RunStatusCancelled      = "cancelled"
+	RunStatusFailed         = "failed"
+	RunStatusCompleted      = "completed"
+	RunStatusIncomplete     = "incomplete"
+	RunStatusExpired        = "expired"
+)
+
+// A Run is an execution of an assistant on a thread.
+type Run struct {
+	ID                  string             `json:"id"`
+	Object              string             `json:"object"`
+	CreatedAt           int64              `json:"created_at"`
+	ThreadID            string             `json:"thread_id"`
+	AssistantID         string             `json:"assistant_id"`
+	Status              string             `json:"status"`
+	RequiredAction      *RequiredAction    `json:"required_action"`
+	LastError           *RunError          `json:"last_error"`
+	IncompleteDetails   *IncompleteDetails `json:"incomplete_details"`
+	Model               string             `json:"model"`
+	Instructions        string             `json:"instructions"`
+	Tools               []Tool             `json:"tools"`
+	Metadata            map[string]string  `json:"metadata,omitempty"`
+	Usage               *Usage             `json:"usage"`
+	MaxPromptTokens     *int               `json:"max_prompt_tokens"`
+	MaxCompletionTokens *int               `json:"max_completion_tokens"`
+}
+
+// Done reports whether the run is in a terminal state.
+func (r *Run) Done() bool {
+	switch r.Status {
+	case RunStatusCancelled, RunStatusFailed, RunStatusCompleted, RunStatusIncomplete, RunStatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// A RequiredAction is an action required to continue a run.
+type RequiredAction struct {
+	Type              string             `json:"type"`
+	SubmitToolOutputs *SubmitToolOutputs `json:"submit_tool_outputs,omitempty"`
+}
+
+// SubmitToolOutputs lists the tool calls whose outputs must be submitted to
+// continue a run.
+type SubmitToolOutputs struct {
+	ToolCalls []ToolCall `json:"tool_calls"`
+}
+
+// A ToolCall is a call by the assistant to a tool.
+type ToolCall struct {
+	Index           int                  `json:"index,omitempty"`
+	ID              string               `json:"id"`
+	Type            string               `json:"type"`
+	Function        *FunctionCall        `json:"function,omitempty"`
+	CodeInterpreter *CodeInterpreterCall `json:"code_interpreter,omitempty"`
+	FileSearch      json.RawMessage      `json:"file_search,omitempty"`
+}
+
+// A FunctionCall is a call to a function tool.
+type FunctionCall struct {
+	Name      string  `json:"name"`
+	Arguments string  `json:"arguments"`
+	Output    *string `json:"output,omitempty"`
+}
+
+// A CodeInterpreterCall is a call to the code interpreter tool.
+type CodeInterpreterCall struct {
+	Input   string                  `json:"input"`
+	Outputs []CodeInterpreterOutput `json:"outputs,omitempty"`
+}
+
+// A CodeInterpreterOutput is a log or image output of the code interpreter.
+type CodeInterpreterOutput struct {
+	Type  string     `json:"type"`
+	Logs  string     `json:"logs,omitempty"`
+	Image *ImageFile `json:"image,omitempty"`
+}
+
+// A RunError is the error that caused a run to fail, or an error event in a
+// run stream.
+type RunError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// IncompleteDetails describes why a run is incomplete.
+type IncompleteDetails struct {
+	Reason string `json:"reason"`
+}
+
+// A Usage defines usage statistics.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// A ToolOutput is the output of a tool call, submitted to continue a run.
+type ToolOutput struct {
+	ToolCallID string `json:"tool_call_id"`
+	Output     string `json:"output"`
+}
+
+type runRequest struct {
+	apiKey string
+
+	AssistantID            string            `json:"assistant_id"`
+	Model                  *string           `json:"model,omitempty"`
+	Instructions           *string           `json:"instructions,omitempty"`
+	AdditionalInstructions *string           `json:"additional_instructions,omitempty"`
+	AdditionalMessages     []MessageRequest  `json:"additional_messages,omitempty"`
+	Tools                  []Tool            `json:"tools,omitempty"`
+	Metadata               map[string]string `json:"metadata,omitempty"`
+	Temperature            *float64          `json:"temperature,omitempty"`
+	MaxPromptTokens        *int              `json:"max_prompt_tokens,omitempty"`
+	MaxCompletionTokens    *int              `json:"max_completion_tokens,omitempty"`
+	ParallelToolCalls      *bool             `json:"parallel_tool_calls,omitempty"`
+	Stream                 *bool             `json:"stream,omitempty"`
+}
+
+// RunOpt is a functional option for configuring a run creation request.
+type RunOpt func(*runRequest)
+
+// WithRunModel overrides the model of the assistant for the run.
+func WithRunModel(model string) RunOpt {
+	return func(r *runRequest) {
+		r.Model = &model
+	}
+}
+
+// WithRunInstructions overrides the instructions of the assistant for the
+// run.
+func WithRunInstructions(instructions string) RunOpt {
+	return func(r *runRequest) {
+		r.Instructions = &instructions
+	}
+}
+
+// WithAdditionalInstructions appends instructions to those of the assistant
+// for the run.
+func WithAdditionalInstructions(instructions string) RunOpt {
+	return func(r *runRequest) {
+		r.AdditionalInstructions = &instructions
+	}
+}
+
+// WithAdditionalMessages adds messages to the thread before the run starts.
+func WithAdditionalMessages(messages ...MessageRequest) RunOpt {
+	return func(r *runRequest) {
+		r.AdditionalMessages = messages
+	}
+}
+
+// WithRunTools overrides the tools of the assistant for the run.
+func WithRunTools(tools ...Tool) RunOpt {
+	return func(r *runRequest) {
+		r.Tools = tools
+	}
+}
+
+// WithRunMetadata sets the metadata of the run.
+func WithRunMetadata(metadata map[string]string) RunOpt {
+	return func(r *runRequest) {
+		r.Metadata = metadata
+	}
+}
+
+// WithRunTemperature overrides the temperature of the assistant for the run.
+func WithRunTemperature(temperature float64) RunOpt {
+	return func(r *runRequest) {
+		r.Temperature = &temperature
+	}
+}
+
+// WithMaxPromptTokens sets the maximum number of prompt tokens used over the
+// course of the run.
+func WithMaxPromptTokens(maxPromptTokens int) RunOpt {
+	return func(r *runRequest) {
+		r.MaxPromptTokens = &maxPromptTokens
+	}
+}
+
+// WithMaxCompletionTokens sets the maximum number of completion tokens used
+// over the course of the run.
+func WithMaxCompletionTokens(maxCompletionTokens int) RunOpt {
+	return func(r *runRequest) {
+		r.MaxCompletionTokens = &maxCompletionTokens
+	}
+}
+
+// WithParallelToolCalls sets whether the assistant may call tools in
+// parallel.
+func WithParallelToolCalls(parallel bool) RunOpt {
+	return func(r *runRequest) {
+		r.ParallelToolCalls = &parallel
+	}
+}
+
+// WithRunAPIKey sets the API key for the run creation request.
+func WithRunAPIKey(apiKey string) RunOpt {
+	return func(r *runRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// A RunListResponse is a page of runs.
+type RunListResponse = service.Page[Run]
+
+// CreateRun creates a run of an assistant on a thread.
+func (h *Service) CreateRun(ctx context.Context, threadID, assistantID string, opts ...RunOpt) (*Run, error) {
+	req := newRunRequest(assistantID, opts)
+
+	var resp Run
+	if err := h.do(ctx, http.MethodPost, runsPath(threadID), req, req.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// CreateRunStreaming creates a run of an assistant on a thread, streaming its
+// events.
+//
+// It returns a RunStream. The caller is responsible for closing it.
+func (h *Service) CreateRunStreaming(
+	ctx context.Context,
+	threadID string,
+	assistantID string,
+	opts ...RunOpt,
+) (*RunStream, error) {
+	req := newRunRequest(assistantID, opts)
+
+	stream := true
+	req.Stream = &stream
+
+	return h.doStreaming(ctx, runsPath(threadID), req, req.apiKey)
+}
+
+func newRunRequest(assistantID string, opts []RunOpt) runRequest {
+	req := runRequest{AssistantID: assistantID}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	return req
+}
+
+// RetrieveRun retrieves a run by its ID.
+func (h *Service) RetrieveRun(ctx context.Context, threadID, id string) (*Run, error) {
+	var resp Run
+	if err := h.do(ctx, http.MethodGet, runPath(threadID, id), nil, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// CancelRun cancels an in-progress run.
+func (h *Service) CancelRun(ctx context.Context, threadID, id string) (*Run, error) {
+	var resp Run
+	if err := h.do(ctx, http.MethodPost, runPath(threadID, id)+"/cancel", nil, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListRuns lists a page of runs on a thread.
+func (h *Service) ListRuns(ctx context.Context, threadID string, opts ...ListOpt) (*RunListResponse, error) {
+	resp, err := service.ListPage[Run](ctx, h.client(), runsPath(threadID), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing runs: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAllRuns returns an iterator over every run on a thread, following
+// pagination cursors.
+func (h *Service) ListAllRuns(ctx context.Context, threadID string, opts ...ListOpt) iter.Seq2[Run, error] {
+	return service.NewPager[Run](h.client(), runsPath(threadID), nil, opts...).All(ctx)
+}
+
+type submitToolOutputsRequest struct {
+	ToolOutputs []ToolOutput `json:"tool_outputs"`
+	Stream      *bool        `json:"stream,omitempty"`
+}
+
+// SubmitToolOutputs submits the outputs of the tool calls of a run whose
+// status is "requires_action".
+func (h *Service) SubmitToolOutputs(ctx context.Context, threadID, runID string, outputs []ToolOutput) (*Run, error) {
+	req := submitToolOutputsRequest{ToolOutputs: outputs}
+
+	var resp Run
+	if err := h.do(ctx, http.MethodPost, runPath(threadID, runID)+"/submit_tool_outputs", req, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// SubmitToolOutputsStreaming submits the outputs of the tool calls of a run
+// whose status is "requires_action", streaming the events of the continued
+// run.
+//
+// It returns a RunStream. The caller is responsible for closing it.
+func (h *Service) SubmitToolOutputsStreaming(
+	ctx context.Context,
+	threadID string,
+	runID string,
+	outputs []ToolOutput,
+) (*RunStream, error) {
+	stream := true
+	req := submitToolOutputsRequest{ToolOutputs: outputs, Stream: &stream}
+
+	return h.doStreaming(ctx, runPath(threadID, runID)+"/submit_tool_outputs", req, "")
+}
+
+// A RunStep is a single step taken by a run, such as creating a message or
+// calling tools.
+type RunStep struct {
+	ID          string      `json:"id"`
+	Object      string      `json:"object"`
+	CreatedAt   int64       `json:"created_at"`
+	RunID       string      `json:"run_id"`
+	ThreadID    string      `json:"thread_id"`
+	AssistantID string      `json:"assistant_id"`
+	Type        string      `json:"type"`
+	Status      string      `json:"status"`
+	StepDetails StepDetails `json:"step_details"`
+	LastError   *RunError   `json:"last_error"`
+	Usage       *Usage      `json:"usage"`
+}
+
+// StepDetails are the details of a run step.
+type StepDetails struct {
+	Type            string           `json:"type"`
+	MessageCreation *MessageCreation `json:"message_creation,omitempty"`
+	ToolCalls       []ToolCall       `json:"tool_calls,omitempty"`
+}
+
+// A MessageCreation references the message created by a run step.
+type MessageCreation struct {
+	MessageID string `json:"message_id"`
+}
+
+// A RunStepListResponse is a page of run steps.
+type RunStepListResponse = service.Page[RunStep]
+
+// ListRunSteps lists a page of the steps of a run.
+func (h *Service) ListRunSteps(
+	ctx context.Context,
+	threadID string,
+	runID string,
+	opts ...ListOpt,
+) (*RunStepListResponse, error) {
+	resp, err := service.ListPage[RunStep](ctx, h.client(), runPath(threadID, runID)+"/steps", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing run steps: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAllRunSteps returns an iterator over every step of a run, following
+// pagination cursors.
+func (h *Service) ListAllRunSteps(
+	ctx context.Context,
+	threadID string,
+	runID string,
+	opts ...ListOpt,
+) iter.Seq2[RunStep, error] {
+	return service.NewPager[RunStep](h.client(), runPath(threadID, runID)+"/steps", nil, opts...).All(ctx)
+}
+
+func runsPath(threadID string) string {
+	return threadPath(threadID) + "/runs"
+}
+
+func runPath(threadID, runID string) string {
+	return runsPath(threadID) + "/" + url.PathEscape(runID)
+}
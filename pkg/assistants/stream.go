@@ -0,0 +1,182 @@
+package assistants
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// Run streaming event types.
+const (
+	EventThreadCreated     = "thread.created"
+	EventRunCreated        = "thread.run.created"
+	EventRunQueued         = "thread.run.queued"
+	EventRunInProgress     = "thread.run.in_progress"
+	EventRunRequiresAction = "thread.run.requires_action"
+	EventRunCompleted      = "thread.run.completed"
+	EventRunIncomplete     = "thread.run.incomplete"
+	EventRunFailed         = "thread.run.failed"
+	EventRunCancelling     = "thread.run.cancelling"
+	EventRunCancelled      = "thread.run.cancelled"
+	EventRunExpired        = "thread.run.expired"
+	EventRunStepCreated    = "thread.run.step.created"
+	EventRunStepInProgress = "thread.run.step.in_progress"
+	EventRunStepDelta      = "thread.run.step.delta"
+	EventRunStepCompleted  = "thread.run.step.completed"
+	EventRunStepFailed     = "thread.run.step.failed"
+	EventRunStepCancelled  = "thread.run.step.cancelled"
+	EventRunStepExpired    = "thread.run.step.expired"
+	EventMessageCreated    = "thread.message.created"
+	EventMessageInProgress = "thread.message.in_progress"
+	EventMessageDelta      = "thread.message.delta"
+	EventMessageCompleted  = "thread.message.completed"
+	EventMessageIncomplete = "thread.message.incomplete"
+	EventError             = "error"
+)
+
+// A RunStreamEvent is a single event in a run stream.
+//
+// Exactly one of the object fields is set, depending on Type.
+type RunStreamEvent struct {
+	Type string
+
+	Thread       *Thread
+	Run          *Run
+	Step         *RunStep
+	StepDelta    *RunStepDelta
+	Message      *Message
+	MessageDelta *MessageDelta
+	Error        *RunError
+
+	// Raw holds the event's JSON data.
+	Raw json.RawMessage
+}
+
+// A MessageDelta is an incremental change to a message during streaming.
+type MessageDelta struct {
+	ID     string `json:"id"`
+	Object string `json:"object"`
+	Delta  struct {
+		Role    string           `json:"role,omitempty"`
+		Content []MessageContent `json:"content,omitempty"`
+	} `json:"delta"`
+}
+
+// Text returns the concatenated text of the delta's text content parts.
+func (d *MessageDelta) Text() string {
+	var text string
+
+	for _, c := range d.Delta.Content {
+		if c.Text != nil {
+			text += c.Text.Value
+		}
+	}
+
+	return text
+}
+
+// A RunStepDelta is an incremental change to a run step during streaming.
+type RunStepDelta struct {
+	ID     string `json:"id"`
+	Object string `json:"object"`
+	Delta  struct {
+		StepDetails StepDetails `json:"step_details"`
+	} `json:"delta"`
+}
+
+func (e *RunStreamEvent) target() any {
+	switch {
+	case e.Type == EventThreadCreated:
+		e.Thread = &Thread{}
+		return e.Thread
+	case e.Type == EventRunStepDelta:
+		e.StepDelta = &RunStepDelta{}
+		return e.StepDelta
+	case strings.HasPrefix(e.Type, "thread.run.step."):
+		e.Step = &RunStep{}
+		return e.Step
+	case strings.HasPrefix(e.Type, "thread.run."):
+		e.Run = &Run{}
+		return e.Run
+	case e.Type == EventMessageDelta:
+		e.MessageDelta = &MessageDelta{}
+		return e.MessageDelta
+	case strings.HasPrefix(e.Type, "thread.message."):
+		e.Message = &Message{}
+		return e.Message
+	case e.Type == EventError:
+		e.Error = &RunError{}
+		return e.Error
+	}
+
+	return nil
+}
+
+// ErrStreamDone is returned when the stream is done (marked by the "done"
+// event).
+var ErrStreamDone = errors.New("run stream is done")
+
+// A RunStream is a stream of run events. It reads an io.ReadCloser and emits
+// RunStreamEvents.
+//
+// A run that requires action ends its stream after the
+// "thread.run.requires_action" event. Continue it with
+// SubmitToolOutputsStreaming.
+//
+// The caller is responsible for closing the stream (`stream.Close()`).
+type RunStream struct {
+	stream *service.EventStream
+}
+
+// Next returns the next event in the run stream.
+//
+// When the stream is complete, it returns ErrStreamDone.
+func (s *RunStream) Next() (*RunStreamEvent, error) {
+	evt, err := s.stream.Next()
+	if err != nil {
+		if errors.Is(err, service.ErrStreamDone) {
+			return nil, ErrStreamDone
+		}
+
+		return nil, err //nolint: wrapcheck // Errors are already wrapped.
+	}
+
+	e := RunStreamEvent{Type: evt.Type, Raw: evt.Data}
+
+	if v := e.target(); v != nil {
+		if err := json.Unmarshal(evt.Data, v); err != nil {
+			return nil, fmt.Errorf("error unmarshaling %s event: %w", evt.Type, err)
+		}
+	}
+
+	return &e, nil
+}
+
+// Close closes the stream.
+func (s *RunStream) Close() error {
+	return s.stream.Close() //nolint: wrapcheck // Errors are already wrapped.
+}
+
+func (h *Service) doStreaming(ctx context.Context, path string, body any, apiKey string) (*RunStream, error) {
+	httpReq, err := h.client().NewRequestWithContext(ctx, http.MethodPost, path, body, service.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating assistants request: %w", err)
+	}
+
+	httpResp, err := h.Client.Do(httpReq, nil) //nolint: bodyclose // False positive.
+	if err != nil {
+		return nil, fmt.Errorf("error performing assistants request: %w", err)
+	}
+
+	return newRunStream(httpResp.Body), nil
+}
+
+func newRunStream(rc io.ReadCloser) *RunStream {
+	return &RunStream{stream: service.NewEventStream(rc)}
+}
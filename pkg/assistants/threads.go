@@ -0,0 +1,286 @@
+package assistants
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// A Thread is a conversation between a user and an assistant.
+type Thread struct {
+	ID            string            `json:"id"`
+	Object        string            `json:"object"`
+	CreatedAt     int64             `json:"created_at"`
+	ToolResources *ToolResources    `json:"tool_resources,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+type threadRequest struct {
+	apiKey string
+
+	Messages      []MessageRequest  `json:"messages,omitempty"`
+	ToolResources *ToolResources    `json:"tool_resources,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// ThreadOpt is a functional option for configuring a thread creation request.
+type ThreadOpt func(*threadRequest)
+
+// WithThreadMessages sets the initial messages of the thread.
+func WithThreadMessages(messages ...MessageRequest) ThreadOpt {
+	return func(r *threadRequest) {
+		r.Messages = messages
+	}
+}
+
+// WithThreadToolResources sets the tool resources of the thread.
+func WithThreadToolResources(resources ToolResources) ThreadOpt {
+	return func(r *threadRequest) {
+		r.ToolResources = &resources
+	}
+}
+
+// WithThreadMetadata sets the metadata of the thread.
+func WithThreadMetadata(metadata map[string]string) ThreadOpt {
+	return func(r *threadRequest) {
+		r.Metadata = metadata
+	}
+}
+
+// WithThreadAPIKey sets the API key for the thread creation request.
+func WithThreadAPIKey(apiKey string) ThreadOpt {
+	return func(r *threadRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// CreateThread creates a thread.
+func (h *Service) CreateThread(ctx context.Context, opts ...ThreadOpt) (*Thread, error) {
+	var req threadRequest
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	var resp Thread
+	if err := h.do(ctx, http.MethodPost, "/threads", req, req.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// RetrieveThread retrieves a thread by its ID.
+func (h *Service) RetrieveThread(ctx context.Context, id string) (*Thread, error) {
+	var resp Thread
+	if err := h.do(ctx, http.MethodGet, threadPath(id), nil, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// DeleteThread deletes a thread by its ID.
+func (h *Service) DeleteThread(ctx context.Context, id string) (*DeleteResponse, error) {
+	var resp DeleteResponse
+	if err := h.do(ctx, http.MethodDelete, threadPath(id), nil, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func threadPath(id string) string {
+	return "/threads/" + url.PathEscape(id)
+}
+
+// Message roles.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// A Message is a message in a thread.
+type Message struct {
+	ID          string            `json:"id"`
+	Object      string            `json:"object"`
+	CreatedAt   int64             `json:"created_at"`
+	ThreadID    string            `json:"thread_id"`
+	Status      string            `json:"status,omitempty"`
+	Role        string            `json:"role"`
+	Content     []MessageContent  `json:"content"`
+	AssistantID *string           `json:"assistant_id"`
+	RunID       *string           `json:"run_id"`
+	Attachments []Attachment      `json:"attachments,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Text returns the concatenated text of the message's text content parts.
+func (m *Message) Text() string {
+	var text string
+
+	for _, c := range m.Content {
+		if c.Text != nil {
+			text += c.Text.Value
+		}
+	}
+
+	return text
+}
+
+// Message content types.
+const (
+	ContentTypeText      = "text"
+	ContentTypeImageFile = "image_file"
+	ContentTypeImageURL  = "image_url"
+)
+
+// A MessageContent is a single content part of a message.
+type MessageContent struct {
+	Index     int        `json:"index,omitempty"`
+	Type      string     `json:"type"`
+	Text      *Text      `json:"text,omitempty"`
+	ImageFile *ImageFile `json:"image_file,omitempty"`
+	ImageURL  *ImageURL  `json:"image_url,omitempty"`
+}
+
+// A Text is text content, with any file citations.
+type Text struct {
+	Value       string       `json:"value"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// An Annotation is a file citation or file path within text content.
+type Annotation struct {
+	Type         string        `json:"type"`
+	Text         string        `json:"text"`
+	StartIndex   int           `json:"start_index"`
+	EndIndex     int           `json:"end_index"`
+	FileCitation *FileCitation `json:"file_citation,omitempty"`
+	FilePath     *FilePath     `json:"file_path,omitempty"`
+}
+
+// A FileCitation cites a file used by the file search tool.
+type FileCitation struct {
+	FileID string `json:"file_id"`
+}
+
+// A FilePath references a file generated by the code interpreter tool.
+type FilePath struct {
+	FileID string `json:"file_id"`
+}
+
+// An ImageFile references an uploaded image file.
+type ImageFile struct {
+	FileID string `json:"file_id"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// An ImageURL references an image by URL.
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// An Attachment attaches a file to a message for use by the given tools.
+type Attachment struct {
+	FileID string `json:"file_id"`
+	Tools  []Tool `json:"tools"`
+}
+
+// A MessageRequest is a message to add to a thread.
+type MessageRequest struct {
+	apiKey string
+
+	Role        string            `json:"role"`
+	Content     string            `json:"content"`
+	Attachments []Attachment      `json:"attachments,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// MessageOpt is a functional option for configuring a message.
+type MessageOpt func(*MessageRequest)
+
+// WithAttachments sets the file attachments of the message.
+func WithAttachments(attachments ...Attachment) MessageOpt {
+	return func(r *MessageRequest) {
+		r.Attachments = attachments
+	}
+}
+
+// WithMessageMetadata sets the metadata of the message.
+func WithMessageMetadata(metadata map[string]string) MessageOpt {
+	return func(r *MessageRequest) {
+		r.Metadata = metadata
+	}
+}
+
+// WithMessageAPIKey sets the API key for the message creation request.
+func WithMessageAPIKey(apiKey string) MessageOpt {
+	return func(r *MessageRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// NewMessage creates a new message to add to a thread.
+func NewMessage(role string, content string, opts ...MessageOpt) MessageRequest {
+	msg := MessageRequest{Role: role, Content: content}
+
+	for _, opt := range opts {
+		opt(&msg)
+	}
+
+	return msg
+}
+
+// A MessageListResponse is a page of messages.
+type MessageListResponse = service.Page[Message]
+
+// CreateMessage adds a message to a thread.
+func (h *Service) CreateMessage(ctx context.Context, threadID string, msg MessageRequest) (*Message, error) {
+	var resp Message
+	if err := h.do(ctx, http.MethodPost, messagesPath(threadID), msg, msg.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// RetrieveMessage retrieves a message in a thread by its ID.
+func (h *Service) RetrieveMessage(ctx context.Context, threadID, id string) (*Message, error) {
+	var resp Message
+	if err := h.do(ctx, http.MethodGet, messagesPath(threadID)+"/"+url.PathEscape(id), nil, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// WithRunID filters listed messages to those created by a run.
+func WithRunID(runID string) ListOpt {
+	return service.WithListParam("run_id", runID)
+}
+
+// ListMessages lists a page of messages in a thread.
+func (h *Service) ListMessages(ctx context.Context, threadID string, opts ...ListOpt) (*MessageListResponse, error) {
+	resp, err := service.ListPage[Message](ctx, h.client(), messagesPath(threadID), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing messages: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAllMessages returns an iterator over every message in a thread,
+// following pagination cursors.
+func (h *Service) ListAllMessages(ctx context.Context, threadID string, opts ...ListOpt) iter.Seq2[Message, error] {
+	return service.NewPager[Message](h.client(), messagesPath(threadID), nil, opts...).All(ctx)
+}
+
+func messagesPath(threadID string) string {
+	return threadPath(threadID) + "/messages"
+}
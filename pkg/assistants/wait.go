@@ -0,0 +1,111 @@
+package assistants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// A ToolHandler handles a function tool call, given its JSON arguments, and
+// returns the output to submit to the run.
+type ToolHandler func(ctx context.Context, arguments string) (string, error)
+
+// ToolHandlers maps function names to their handlers.
+type ToolHandlers map[string]ToolHandler
+
+// ErrNoToolHandler is returned when a run calls a function that has no
+// handler.
+var ErrNoToolHandler = errors.New("no handler for tool call")
+
+// ErrUnsupportedRequiredAction is returned when a run requires an action
+// other than submitting tool outputs.
+var ErrUnsupportedRequiredAction = errors.New("unsupported required action")
+
+// DefaultPollInterval is the interval WaitForRun polls at when it is given an
+// interval that isn't positive.
+const DefaultPollInterval = time.Second
+
+// WaitForRun polls a run every interval until it is in a terminal state (see
+// Run.Done), or the context is done. If interval isn't positive,
+// DefaultPollInterval is used.
+//
+// When the run requires action, each of its function tool calls is
+// dispatched to the handler for its name, and the outputs are submitted
+// before polling continues. If a call has no handler or its handler returns
+// an error, the error is returned and the run is left waiting for outputs.
+//
+// A run that failed, expired, or was cancelled is returned without an error;
+// check its Status.
+func (h *Service) WaitForRun(
+	ctx context.Context,
+	threadID string,
+	runID string,
+	interval time.Duration,
+	handlers ToolHandlers,
+) (*Run, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		run, err := h.RetrieveRun(ctx, threadID, runID)
+		if err != nil {
+			return nil, err
+		}
+
+		if run.Done() {
+			return run, nil
+		}
+
+		if run.Status == RunStatusRequiresAction && run.RequiredAction != nil {
+			if err := h.handleRequiredAction(ctx, run, handlers); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error waiting for run: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Service) handleRequiredAction(ctx context.Context, run *Run, handlers ToolHandlers) error {
+	if run.RequiredAction.SubmitToolOutputs == nil {
+		return fmt.Errorf("%w: %q", ErrUnsupportedRequiredAction, run.RequiredAction.Type)
+	}
+
+	calls := run.RequiredAction.SubmitToolOutputs.ToolCalls
+	outputs := make([]ToolOutput, 0, len(calls))
+
+	for _, call := range calls {
+		if call.Function == nil {
+			return fmt.Errorf("%w: %s (type %q)", ErrNoToolHandler, call.ID, call.Type)
+		}
+
+		handler, ok := handlers[call.Function.Name]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrNoToolHandler, call.Function.Name)
+		}
+
+		output, err := handler(ctx, call.Function.Arguments)
+		if err != nil {
+			return fmt.Errorf("error handling tool call %s: %w", call.Function.Name, err)
+		}
+
+		outputs = append(outputs, ToolOutput{ToolCallID: call.ID, Output: output})
+	}
+
+	if _, err := h.SubmitToolOutputs(ctx, run.ThreadID, run.ID, outputs); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,419 @@
+// Package audio provides an audio client for the OpenAI API.
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/sseparser"
+)
+
+// A File is an audio file to upload.
+type File struct {
+	Name   string
+	Reader io.Reader
+}
+
+// NewFile creates a new File. The name's extension is used by the API to
+// detect the audio format, so it should match it (e.g. "call.mp3").
+func NewFile(name string, r io.Reader) File {
+	return File{Name: name, Reader: r}
+}
+
+// Transcription response formats.
+const (
+	ResponseFormatJSON        = "json"
+	ResponseFormatVerboseJSON = "verbose_json"
+	ResponseFormatText        = "text"
+	ResponseFormatSRT         = "srt"
+	ResponseFormatVTT         = "vtt"
+)
+
+// Timestamp granularities for verbose JSON transcriptions.
+const (
+	TimestampGranularityWord    = "word"
+	TimestampGranularitySegment = "segment"
+)
+
+type transcriptionRequest struct {
+	apiKey string
+
+	model                  string
+	language               *string
+	prompt                 *string
+	responseFormat         *string
+	temperature            *float64
+	timestampGranularities []string
+	include                []string
+	chunkingStrategy       *string
+	stream                 bool
+}
+
+func (r *transcriptionRequest) newForm(file File) *service.Form {
+	form := &service.Form{}
+	form.AddFile("file", file.Name, file.Reader)
+	form.AddField("model", r.model)
+
+	if r.language != nil {
+		form.AddField("language", *r.language)
+	}
+
+	if r.prompt != nil {
+		form.AddField("prompt", *r.prompt)
+	}
+
+	if r.responseFormat != nil {
+		form.AddField("response_format", *r.responseFormat)
+	}
+
+	if r.temperature != nil {
+		form.AddField("temperature", strconv.FormatFloat(*r.temperature, 'f', -1, 64))
+	}
+
+	for _, granularity := range r.timestampGranularities {
+		form.AddField("timestamp_granularities[]", granularity)
+	}
+
+	for _, include := range r.include {
+		form.AddField("include[]", include)
+	}
+
+	if r.chunkingStrategy != nil {
+		form.AddField("chunking_strategy", *r.chunkingStrategy)
+	}
+
+	if r.stream {
+		form.AddField("stream", "true")
+	}
+
+	return form
+}
+
+// returnsText reports whether the response body is plain text rather than
+// JSON.
+func (r *transcriptionRequest) returnsText() bool {
+	if r.responseFormat == nil {
+		return false
+	}
+
+	switch *r.responseFormat {
+	case ResponseFormatText, ResponseFormatSRT, ResponseFormatVTT:
+		return true
+	default:
+		return false
+	}
+}
+
+// TranscriptionOpt is a functional option for configuring a transcription or
+// translation request.
+type TranscriptionOpt func(*transcriptionRequest)
+
+// WithLanguage sets the ISO-639-1 language of the input audio.
+//
+// It is only supported for transcriptions, and is not sent with translations.
+func WithLanguage(language string) TranscriptionOpt {
+	return func(r *transcriptionRequest) {
+		r.language = &language
+	}
+}
+
+// WithPrompt sets a prompt to guide the model's style or continue a previous
+// segment.
+func WithPrompt(prompt string) TranscriptionOpt {
+	return func(r *transcriptionRequest) {
+		r.prompt = &prompt
+	}
+}
+
+// WithResponseFormat sets the response format.
+//
+// For ResponseFormatText, ResponseFormatSRT, and ResponseFormatVTT, the raw
+// response body is returned as the transcription text.
+func WithResponseFormat(format string) TranscriptionOpt {
+	return func(r *transcriptionRequest) {
+		r.responseFormat = &format
+	}
+}
+
+// WithTemperature sets the sampling temperature.
+func WithTemperature(temperature float64) TranscriptionOpt {
+	return func(r *transcriptionRequest) {
+		r.temperature = &temperature
+	}
+}
+
+// WithTimestampGranularities sets the timestamp granularities to populate.
+//
+// It requires ResponseFormatVerboseJSON.
+func WithTimestampGranularities(granularities ...string) TranscriptionOpt {
+	return func(r *transcriptionRequest) {
+		r.timestampGranularities = granularities
+	}
+}
+
+// WithInclude sets additional information to include in the response (e.g.
+// "logprobs").
+func WithInclude(include ...string) TranscriptionOpt {
+	return func(r *transcriptionRequest) {
+		r.include = include
+	}
+}
+
+// WithChunkingStrategy sets the chunking strategy (e.g. "auto").
+func WithChunkingStrategy(strategy string) TranscriptionOpt {
+	return func(r *transcriptionRequest) {
+		r.chunkingStrategy = &strategy
+	}
+}
+
+// WithAPIKey sets the API key for the transcription request.
+func WithAPIKey(apiKey string) TranscriptionOpt {
+	return func(r *transcriptionRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// A Transcription is a transcription or translation of audio.
+//
+// For plain text, SRT, and VTT response formats, only Text is set, and holds
+// the raw response body.
+type Transcription struct {
+	Task     string    `json:"task,omitempty"`
+	Language string    `json:"language,omitempty"`
+	Duration float64   `json:"duration,omitempty"`
+	Text     string    `json:"text"`
+	Words    []Word    `json:"words,omitempty"`
+	Segments []Segment `json:"segments,omitempty"`
+	Logprobs []Logprob `json:"logprobs,omitempty"`
+	Usage    *Usage    `json:"usage,omitempty"`
+}
+
+// A Word is a single transcribed word with timestamps, in seconds.
+type Word struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// A Segment is a transcribed segment with timestamps, in seconds.
+type Segment struct {
+	ID               int     `json:"id"`
+	Seek             int     `json:"seek"`
+	Start            float64 `json:"start"`
+	End              float64 `json:"end"`
+	Text             string  `json:"text"`
+	Tokens           []int   `json:"tokens"`
+	Temperature      float64 `json:"temperature"`
+	AvgLogprob       float64 `json:"avg_logprob"`
+	CompressionRatio float64 `json:"compression_ratio"`
+	NoSpeechProb     float64 `json:"no_speech_prob"`
+}
+
+// A Logprob is the log probability of a transcribed token.
+type Logprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes"`
+}
+
+// A Usage defines usage statistics for a transcription.
+//
+// Type is "tokens" for token-billed models, and "duration" for models billed
+// by the second.
+type Usage struct {
+	Type         string  `json:"type"`
+	InputTokens  int     `json:"input_tokens,omitempty"`
+	OutputTokens int     `json:"output_tokens,omitempty"`
+	TotalTokens  int     `json:"total_tokens,omitempty"`
+	Seconds      float64 `json:"seconds,omitempty"`
+}
+
+// Service is a service wrapping an OpenAI-compatible audio API.
+type Service service.Service
+
+// Transcribe transcribes audio into the input language.
+func (h *Service) Transcribe(
+	ctx context.Context,
+	model string,
+	file File,
+	opts ...TranscriptionOpt,
+) (*Transcription, error) {
+	return h.transcribe(ctx, "/audio/transcriptions", model, file, opts)
+}
+
+// Translate translates audio into English.
+func (h *Service) Translate(
+	ctx context.Context,
+	model string,
+	file File,
+	opts ...TranscriptionOpt,
+) (*Transcription, error) {
+	return h.transcribe(ctx, "/audio/translations", model, file, append(slices.Clip(opts), withoutLanguage))
+}
+
+// withoutLanguage clears the language of a translation request, which the
+// API does not accept.
+func withoutLanguage(r *transcriptionRequest) {
+	r.language = nil
+}
+
+func (h *Service) transcribe(
+	ctx context.Context,
+	path string,
+	model string,
+	file File,
+	opts []TranscriptionOpt,
+) (*Transcription, error) {
+	req := transcriptionRequest{model: model}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, path, req.newForm(file),
+		service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating audio request: %w", err)
+	}
+
+	var resp Transcription
+
+	if req.returnsText() {
+		var buf bytes.Buffer
+		if _, err := h.Client.Do(httpReq, &buf); err != nil { //nolint: bodyclose // False positive.
+			return nil, fmt.Errorf("error performing audio request: %w", err)
+		}
+
+		resp.Text = buf.String()
+
+		return &resp, nil
+	}
+
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing audio request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// TranscribeStreaming transcribes audio into the input language, streaming
+// text deltas as they are produced.
+//
+// Streaming is not supported by all models. The caller is responsible for
+// closing the stream.
+func (h *Service) TranscribeStreaming(
+	ctx context.Context,
+	model string,
+	file File,
+	opts ...TranscriptionOpt,
+) (*StreamingTranscription, error) {
+	req := transcriptionRequest{model: model, stream: true}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/audio/transcriptions",
+		req.newForm(file), service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating audio request: %w", err)
+	}
+
+	httpResp, err := h.Client.Do(httpReq, nil) //nolint: bodyclose // False positive.
+	if err != nil {
+		return nil, fmt.Errorf("error performing audio request: %w", err)
+	}
+
+	return newStreamingTranscription(httpResp.Body), nil
+}
+
+// Streaming transcription event types.
+const (
+	EventTranscriptTextDelta = "transcript.text.delta"
+	EventTranscriptTextDone  = "transcript.text.done"
+)
+
+// A TranscriptionEvent is a single event in a streaming transcription.
+//
+// Delta is set for EventTranscriptTextDelta events, and Text and Usage are set
+// for the final EventTranscriptTextDone event.
+type TranscriptionEvent struct {
+	Type     string    `json:"type"`
+	Delta    string    `json:"delta,omitempty"`
+	Text     string    `json:"text,omitempty"`
+	Logprobs []Logprob `json:"logprobs,omitempty"`
+	Usage    *Usage    `json:"usage,omitempty"`
+}
+
+// UnmarshalSSEValue implements sseparser.UnmarshalerSSEValue.
+func (e *TranscriptionEvent) UnmarshalSSEValue(v string) error {
+	if err := json.Unmarshal([]byte(v), e); err != nil {
+		return fmt.Errorf("error unmarshaling transcription event: %w", err)
+	}
+
+	return nil
+}
+
+type streamingTranscriptionEvent struct {
+	Data TranscriptionEvent `sse:"data"`
+}
+
+// ErrStreamDone is returned when the stream is done (after the
+// EventTranscriptTextDone event has been read).
+var ErrStreamDone = errors.New("transcription stream is done")
+
+// A StreamingTranscription is a streaming response to a transcription
+// request. It reads an io.ReadCloser and emits TranscriptionEvents.
+//
+// The caller is responsible for closing the stream (`stream.Close()`).
+type StreamingTranscription struct {
+	closer  io.Closer
+	scanner *sseparser.StreamScanner
+	done    bool
+}
+
+// Next returns the next event in the streaming transcription.
+//
+// After the done event has been returned, it returns ErrStreamDone.
+func (s *StreamingTranscription) Next() (*TranscriptionEvent, error) {
+	if s.done {
+		return nil, ErrStreamDone
+	}
+
+	var evt streamingTranscriptionEvent
+
+	if _, err := s.scanner.UnmarshalNext(&evt); err != nil {
+		if errors.Is(err, sseparser.ErrStreamEOF) {
+			return nil, fmt.Errorf("stream ended before %s: %w", EventTranscriptTextDone, err)
+		}
+
+		return nil, fmt.Errorf("error reading next event from stream: %w", err)
+	}
+
+	s.done = evt.Data.Type == EventTranscriptTextDone
+
+	return &evt.Data, nil
+}
+
+// Close closes the stream.
+func (s *StreamingTranscription) Close() error {
+	if err := s.closer.Close(); err != nil {
+		return fmt.Errorf("error closing stream: %w", err)
+	}
+
+	return nil
+}
+
+func newStreamingTranscription(rc io.ReadCloser) *StreamingTranscription {
+	scanner := sseparser.NewStreamScanner(rc)
+
+	return &StreamingTranscription{closer: rc, scanner: scanner}
+}
@@ -0,0 +1,175 @@
+package audio_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/audio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// formFields returns the fields of a multipart request as "name=value".
+func formFields(req *http.Request) ([]string, error) {
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+
+	mr := multipart.NewReader(req.Body, params["boundary"])
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return fields, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, part.FormName()+"="+string(b))
+	}
+}
+
+func TestHTTPClient_Transcribe(t *testing.T) {
+	t.Parallel()
+
+	var fields []string
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		var err error
+		if fields, err = formFields(req); err != nil {
+			return nil, err
+		}
+
+		r := &http.Response{}
+		r.StatusCode = http.StatusOK
+		r.Body = httptesting.NewTestBody(strings.NewReader(`{
+			"task": "transcribe",
+			"language": "english",
+			"duration": 1.5,
+			"text": "Hello there.",
+			"words": [{"word": "Hello", "start": 0, "end": 0.5}, {"word": "there", "start": 0.6, "end": 1.1}],
+			"segments": [{"id": 0, "start": 0, "end": 1.5, "text": "Hello there."}]
+		}`))
+
+		return r, nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*audio.Service)(svc)
+
+	resp, err := c.Transcribe(
+		context.Background(),
+		"whisper-1",
+		audio.NewFile("call.mp3", strings.NewReader("audio")),
+		audio.WithResponseFormat(audio.ResponseFormatVerboseJSON),
+		audio.WithTimestampGranularities(audio.TimestampGranularityWord, audio.TimestampGranularitySegment),
+		audio.WithLanguage("en"),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"file=audio",
+		"model=whisper-1",
+		"language=en",
+		"response_format=verbose_json",
+		"timestamp_granularities[]=word",
+		"timestamp_granularities[]=segment",
+	}, fields)
+	assert.Equal(t, "Hello there.", resp.Text)
+	assert.Len(t, resp.Words, 2)
+	assert.Len(t, resp.Segments, 1)
+}
+
+func TestHTTPClient_TranslateText(t *testing.T) {
+	t.Parallel()
+
+	srt := "1\n00:00:00,000 --> 00:00:01,500\nHello there.\n"
+
+	var fields []string
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		var err error
+		if fields, err = formFields(req); err != nil {
+			return nil, err
+		}
+
+		r := &http.Response{}
+		r.StatusCode = http.StatusOK
+		r.Body = httptesting.NewTestBody(strings.NewReader(srt))
+
+		return r, nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*audio.Service)(svc)
+
+	resp, err := c.Translate(
+		context.Background(),
+		"whisper-1",
+		audio.NewFile("call.mp3", strings.NewReader("audio")),
+		audio.WithResponseFormat(audio.ResponseFormatSRT),
+		audio.WithLanguage("fr"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, srt, resp.Text)
+
+	// Translations don't accept a language.
+	assert.Equal(t, []string{"file=audio", "model=whisper-1", "response_format=srt"}, fields)
+}
+
+func TestHTTPClient_TranscribeStreaming(t *testing.T) {
+	t.Parallel()
+
+	sse := `data: {"type": "transcript.text.delta", "delta": "Hello"}
+
+data: {"type": "transcript.text.done", "text": "Hello", "usage": {"type": "tokens", "total_tokens": 5}}
+
+`
+
+	r := &http.Response{}
+	r.StatusCode = http.StatusOK
+	r.Body = httptesting.NewTestBody(strings.NewReader(sse))
+	doer := httptesting.NewTestDoer(r, nil)
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", &doer)
+	c := (*audio.Service)(svc)
+
+	stream, err := c.TranscribeStreaming(
+		context.Background(),
+		"gpt-4o-transcribe",
+		audio.NewFile("call.mp3", strings.NewReader("audio")),
+	)
+	require.NoError(t, err)
+
+	evt, err := stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", evt.Delta)
+
+	evt, err = stream.Next()
+	require.NoError(t, err)
+	assert.Equal(t, audio.EventTranscriptTextDone, evt.Type)
+	assert.Equal(t, 5, evt.Usage.TotalTokens)
+
+	_, err = stream.Next()
+	require.ErrorIs(t, err, audio.ErrStreamDone)
+	require.NoError(t, stream.Close())
+}
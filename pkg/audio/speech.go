@@ -0,0 +1,136 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// Speech audio formats.
+const (
+	SpeechFormatMP3  = "mp3"
+	SpeechFormatOpus = "opus"
+	SpeechFormatAAC  = "aac"
+	SpeechFormatFLAC = "flac"
+	SpeechFormatWAV  = "wav"
+	SpeechFormatPCM  = "pcm"
+)
+
+type speechRequest struct {
+	apiKey string
+
+	Model          string   `json:"model"`
+	Input          string   `json:"input"`
+	Voice          string   `json:"voice"`
+	Instructions   *string  `json:"instructions,omitempty"`
+	ResponseFormat *string  `json:"response_format,omitempty"`
+	Speed          *float64 `json:"speed,omitempty"`
+}
+
+// SpeechOpt is a functional option for configuring a speech request.
+type SpeechOpt func(*speechRequest)
+
+// WithSpeechFormat sets the audio format of the speech (e.g. SpeechFormatOpus).
+//
+// The default format is SpeechFormatMP3.
+func WithSpeechFormat(format string) SpeechOpt {
+	return func(r *speechRequest) {
+		r.ResponseFormat = &format
+	}
+}
+
+// WithSpeed sets the speed of the speech, from 0.25 to 4.0.
+func WithSpeed(speed float64) SpeechOpt {
+	return func(r *speechRequest) {
+		r.Speed = &speed
+	}
+}
+
+// WithInstructions sets instructions to control the voice (e.g. tone or
+// accent).
+//
+// Instructions are not supported by all models.
+func WithInstructions(instructions string) SpeechOpt {
+	return func(r *speechRequest) {
+		r.Instructions = &instructions
+	}
+}
+
+// WithSpeechAPIKey sets the API key for the speech request.
+func WithSpeechAPIKey(apiKey string) SpeechOpt {
+	return func(r *speechRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// Speech generates audio from input text.
+//
+// It returns the audio stream as it is received. The caller is responsible
+// for closing it.
+func (h *Service) Speech(
+	ctx context.Context,
+	model string,
+	voice string,
+	input string,
+	opts ...SpeechOpt,
+) (io.ReadCloser, error) {
+	httpReq, err := h.newSpeechRequest(ctx, model, voice, input, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp, err := h.Client.Do(httpReq, nil) //nolint: bodyclose // Closed by the caller.
+	if err != nil {
+		return nil, fmt.Errorf("error performing speech request: %w", err)
+	}
+
+	return httpResp.Body, nil
+}
+
+// WriteSpeech generates audio from input text and copies it to w as it is
+// received, without buffering the full audio (e.g. to a file or an
+// http.ResponseWriter).
+func (h *Service) WriteSpeech(
+	ctx context.Context,
+	w io.Writer,
+	model string,
+	voice string,
+	input string,
+	opts ...SpeechOpt,
+) error {
+	httpReq, err := h.newSpeechRequest(ctx, model, voice, input, opts)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.Client.Do(httpReq, w); err != nil { //nolint: bodyclose // False positive.
+		return fmt.Errorf("error performing speech request: %w", err)
+	}
+
+	return nil
+}
+
+func (h *Service) newSpeechRequest(
+	ctx context.Context,
+	model string,
+	voice string,
+	input string,
+	opts []SpeechOpt,
+) (*http.Request, error) {
+	req := speechRequest{Model: model, Voice: voice, Input: input}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/audio/speech", req,
+		service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating speech request: %w", err)
+	}
+
+	return httpReq, nil
+}
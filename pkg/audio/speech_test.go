@@ -0,0 +1,79 @@
+package audio_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/audio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_Speech(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		r := &http.Response{}
+		r.StatusCode = http.StatusOK
+		r.Body = httptesting.NewTestBody(strings.NewReader("audio-bytes"))
+
+		return r, nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*audio.Service)(svc)
+
+	rc, err := c.Speech(
+		context.Background(),
+		"gpt-4o-mini-tts",
+		"alloy",
+		"Hello, world.",
+		audio.WithSpeechFormat(audio.SpeechFormatOpus),
+		audio.WithSpeed(1.25),
+	)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+
+	assert.Equal(t, "audio-bytes", string(b))
+	assert.Equal(t, map[string]any{
+		"model":           "gpt-4o-mini-tts",
+		"voice":           "alloy",
+		"input":           "Hello, world.",
+		"response_format": "opus",
+		"speed":           1.25,
+	}, body)
+}
+
+func TestHTTPClient_WriteSpeech(t *testing.T) {
+	t.Parallel()
+
+	r := &http.Response{}
+	r.StatusCode = http.StatusOK
+	r.Body = httptesting.NewTestBody(strings.NewReader("audio-bytes"))
+	doer := httptesting.NewTestDoer(r, nil)
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", &doer)
+	c := (*audio.Service)(svc)
+
+	var buf bytes.Buffer
+	err := c.WriteSpeech(context.Background(), &buf, "tts-1", "alloy", "Hello, world.")
+	require.NoError(t, err)
+	assert.Equal(t, "audio-bytes", buf.String())
+}
@@ -0,0 +1,261 @@
+// Package batch provides a batch client for the OpenAI API.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/files"
+)
+
+// Batch endpoints.
+const (
+	EndpointChatCompletions = "/v1/chat/completions"
+	EndpointEmbeddings      = "/v1/embeddings"
+)
+
+// Batch statuses.
+const (
+	StatusValidating = "validating"
+	StatusFailed     = "failed"
+	StatusInProgress = "in_progress"
+	StatusFinalizing = "finalizing"
+	StatusCompleted  = "completed"
+	StatusExpired    = "expired"
+	StatusCancelling = "cancelling"
+	StatusCancelled  = "cancelled"
+)
+
+// A Batch is a batch of requests processed asynchronously.
+type Batch struct {
+	ID               string            `json:"id"`
+	Object           string            `json:"object"`
+	Endpoint         string            `json:"endpoint"`
+	Errors           *Errors           `json:"errors,omitempty"`
+	InputFileID      string            `json:"input_file_id"`
+	CompletionWindow string            `json:"completion_window"`
+	Status           string            `json:"status"`
+	OutputFileID     *string           `json:"output_file_id,omitempty"`
+	ErrorFileID      *string           `json:"error_file_id,omitempty"`
+	CreatedAt        int64             `json:"created_at"`
+	InProgressAt     *int64            `json:"in_progress_at,omitempty"`
+	ExpiresAt        *int64            `json:"expires_at,omitempty"`
+	FinalizingAt     *int64            `json:"finalizing_at,omitempty"`
+	CompletedAt      *int64            `json:"completed_at,omitempty"`
+	FailedAt         *int64            `json:"failed_at,omitempty"`
+	ExpiredAt        *int64            `json:"expired_at,omitempty"`
+	CancellingAt     *int64            `json:"cancelling_at,omitempty"`
+	CancelledAt      *int64            `json:"cancelled_at,omitempty"`
+	RequestCounts    RequestCounts     `json:"request_counts"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// Done reports whether the batch is in a terminal state.
+func (b *Batch) Done() bool {
+	switch b.Status {
+	case StatusFailed, StatusCompleted, StatusExpired, StatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// Errors holds the errors that caused a batch to fail validation.
+type Errors struct {
+	Object string       `json:"object"`
+	Data   []BatchError `json:"data"`
+}
+
+// A BatchError is a single batch validation error.
+type BatchError struct {
+	Code    string  `json:"code"`
+	Message string  `json:"message"`
+	Param   *string `json:"param,omitempty"`
+	Line    *int    `json:"line,omitempty"`
+}
+
+// RequestCounts holds the number of requests in each state.
+type RequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// A ListResponse is a page of batches.
+type ListResponse = service.Page[Batch]
+
+type createRequest struct {
+	apiKey string
+
+	InputFileID      string            `json:"input_file_id"`
+	Endpoint         string            `json:"endpoint"`
+	CompletionWindow string            `json:"completion_window"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// CreateOpt is a functional option for configuring a batch creation request.
+type CreateOpt func(*createRequest)
+
+// WithMetadata sets the metadata for the batch.
+func WithMetadata(metadata map[string]string) CreateOpt {
+	return func(r *createRequest) {
+		r.Metadata = metadata
+	}
+}
+
+// WithAPIKey sets the API key for the batch creation request. Submit also
+// uses it to upload the input file.
+func WithAPIKey(apiKey string) CreateOpt {
+	return func(r *createRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// ListOpt is a functional option for configuring a batch list request.
+type ListOpt = service.ListOpt
+
+// WithLimit sets the maximum number of batches in a page.
+func WithLimit(limit int) ListOpt {
+	return service.WithLimit(limit)
+}
+
+// WithAfter sets the cursor to list batches after, which is the LastID of the
+// previous page.
+func WithAfter(after string) ListOpt {
+	return service.WithAfter(after)
+}
+
+// Service is a service wrapping an OpenAI-compatible batch API.
+type Service service.Service
+
+// Create creates a batch from an uploaded input file.
+func (h *Service) Create(
+	ctx context.Context,
+	inputFileID string,
+	endpoint string,
+	opts ...CreateOpt,
+) (*Batch, error) {
+	req := createRequest{InputFileID: inputFileID, Endpoint: endpoint, CompletionWindow: "24h"}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/batches", req,
+		service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating batch request: %w", err)
+	}
+
+	var resp Batch
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing batch request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// Submit uploads a JSONL input file (such as one written by a Writer) and
+// creates a batch from it.
+func (h *Service) Submit(
+	ctx context.Context,
+	r io.Reader,
+	endpoint string,
+	opts ...CreateOpt,
+) (*Batch, error) {
+	var req createRequest
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	file, err := (*files.Service)(h).Upload(ctx, r, "batch.jsonl", files.PurposeBatch,
+		files.WithUploadAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error uploading batch input file: %w", err)
+	}
+
+	return h.Create(ctx, file.ID, endpoint, opts...)
+}
+
+// Retrieve retrieves a batch by its ID.
+func (h *Service) Retrieve(ctx context.Context, id string) (*Batch, error) {
+	return h.get(ctx, http.MethodGet, "/batches/"+url.PathEscape(id))
+}
+
+// Cancel cancels an in-progress batch.
+func (h *Service) Cancel(ctx context.Context, id string) (*Batch, error) {
+	return h.get(ctx, http.MethodPost, "/batches/"+url.PathEscape(id)+"/cancel")
+}
+
+func (h *Service) get(ctx context.Context, method, path string) (*Batch, error) {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, method, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating batch request: %w", err)
+	}
+
+	var resp Batch
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing batch request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// List lists a page of batches.
+//
+// Use WithAfter with the previous page's LastID to fetch the next page.
+func (h *Service) List(ctx context.Context, opts ...ListOpt) (*ListResponse, error) {
+	resp, err := service.ListPage[Batch](ctx, &h.Client, "/batches", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing batches: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAll returns an iterator over every batch, following pagination cursors.
+func (h *Service) ListAll(ctx context.Context, opts ...ListOpt) iter.Seq2[Batch, error] {
+	return service.NewPager[Batch](&h.Client, "/batches", nil, opts...).All(ctx)
+}
+
+// DefaultPollInterval is the interval Wait polls at when it is given an
+// interval that isn't positive.
+const DefaultPollInterval = 10 * time.Second
+
+// Wait polls a batch every interval until it is in a terminal state (see
+// Batch.Done), or the context is done. If interval isn't positive,
+// DefaultPollInterval is used.
+//
+// A batch that failed, expired, or was cancelled is returned without an
+// error; check its Status.
+func (h *Service) Wait(ctx context.Context, id string, interval time.Duration) (*Batch, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		b, err := h.Retrieve(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if b.Done() {
+			return b, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error waiting for batch: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package batch_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/batch"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/jclem/openai-go/pkg/embeddings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := batch.NewWriter(&buf)
+
+	err := w.AddChatCompletion(
+		"req-1",
+		"gpt-4o-mini",
+		[]chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hello"))},
+		chat.WithMaxTokens(16),
+	)
+	require.NoError(t, err)
+
+	err = w.AddChatCompletion("req-1", "gpt-4o-mini", nil)
+	require.ErrorIs(t, err, batch.ErrDuplicateCustomID)
+
+	err = w.AddEmbeddings("req-2", "text-embedding-3-small", []string{"Hello"})
+	require.ErrorIs(t, err, batch.ErrMixedEndpoints)
+
+	assert.Equal(t, 1, w.Len())
+	assert.Equal(t, batch.EndpointChatCompletions, w.Endpoint())
+	assert.Equal(t,
+		`{"custom_id":"req-1","method":"POST","url":"/v1/chat/completions",`+
+			`"body":{"model":"gpt-4o-mini","messages":[{"role":"user","content":"Hello"}],"max_tokens":16}}`+"\n",
+		buf.String())
+}
+
+func TestHTTPClient_Submit(t *testing.T) {
+	t.Parallel()
+
+	var keys []string
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		keys = append(keys, req.URL.Path+" "+req.Header.Get("Authorization"))
+
+		r := &http.Response{}
+		r.StatusCode = http.StatusOK
+		r.Body = httptesting.NewTestBody(strings.NewReader(`{"id": "file-in", "status": "completed"}`))
+
+		return r, nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*batch.Service)(svc)
+
+	_, err := c.Submit(context.Background(), strings.NewReader("{}\n"), batch.EndpointChatCompletions,
+		batch.WithAPIKey("other-key"))
+	require.NoError(t, err)
+
+	// The upload and the batch use the same key.
+	assert.Equal(t, []string{"/v1/files Bearer other-key", "/v1/batches Bearer other-key"}, keys)
+}
+
+func TestHTTPClient_WaitZeroInterval(t *testing.T) {
+	t.Parallel()
+
+	doer := httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+		r := &http.Response{}
+		r.StatusCode = http.StatusOK
+		r.Body = httptesting.NewTestBody(strings.NewReader(`{"id": "batch_1", "status": "completed"}`))
+
+		return r, nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*batch.Service)(svc)
+
+	b, err := c.Wait(context.Background(), "batch_1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, batch.StatusCompleted, b.Status)
+}
+
+func TestHTTPClient_ChatCompletionResults(t *testing.T) {
+	t.Parallel()
+
+	contents := map[string]string{
+		"/v1/files/file-out/content": `{"id": "batch_req_1", "custom_id": "req-1", "response": {"status_code": 200, "body": {"id": "chatcmpl-1", "choices": [{"message": {"role": "assistant", "content": "Hi"}}]}}}
+{"id": "batch_req_2", "custom_id": "req-2", "response": {"status_code": 400, "body": {"error": {"message": "bad"}}}}
+`,
+		"/v1/files/file-err/content": `{"id": "batch_req_3", "custom_id": "req-3", "error": {"code": "server_error", "message": "oops"}}
+`,
+	}
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		r := &http.Response{}
+		r.StatusCode = http.StatusOK
+		r.Body = httptesting.NewTestBody(strings.NewReader(contents[req.URL.Path]))
+
+		return r, nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*batch.Service)(svc)
+
+	outID, errID := "file-out", "file-err"
+	succeeded, failed, err := c.ChatCompletionResults(context.Background(), &batch.Batch{
+		OutputFileID: &outID,
+		ErrorFileID:  &errID,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, succeeded, 1)
+	content, ok := succeeded["req-1"].GetContentAt(0)
+	require.True(t, ok)
+	assert.Equal(t, "Hi", content)
+
+	require.Len(t, failed, 2)
+	assert.Equal(t, http.StatusBadRequest, failed["req-2"].Response.StatusCode)
+	assert.Equal(t, "server_error", failed["req-3"].Error.Code)
+}
+
+func TestResult_Embeddings(t *testing.T) {
+	t.Parallel()
+
+	rr := batch.NewResultReader(strings.NewReader(
+		`{"custom_id": "req-1", "response": {"status_code": 200, "body": {"data": [{"embedding": [0.1, 0.2]}]}}}`))
+
+	result, err := rr.Next()
+	require.NoError(t, err)
+
+	resp, err := result.Embeddings()
+	require.NoError(t, err)
+	assert.Equal(t, &embeddings.Response{Data: []embeddings.Embedding{{Embedding: []float64{0.1, 0.2}}}}, resp)
+}
@@ -0,0 +1,197 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/jclem/openai-go/pkg/embeddings"
+	"github.com/jclem/openai-go/pkg/files"
+)
+
+// A Result is a single line of a batch output or error file.
+type Result struct {
+	ID       string          `json:"id"`
+	CustomID string          `json:"custom_id"`
+	Response *ResultResponse `json:"response,omitempty"`
+	Error    *ResultError    `json:"error,omitempty"`
+}
+
+// Failed reports whether the request failed, either with an error or a
+// non-2xx response.
+func (r *Result) Failed() bool {
+	if r.Error != nil || r.Response == nil {
+		return true
+	}
+
+	return r.Response.StatusCode < 200 || r.Response.StatusCode > 299 //revive:disable-line:add-constant
+}
+
+// ChatCompletion decodes the response body as a chat completion.
+func (r *Result) ChatCompletion() (*chat.CompletionResponse, error) {
+	var resp chat.CompletionResponse
+	if err := r.decode(&resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Embeddings decodes the response body as an embeddings response.
+func (r *Result) Embeddings() (*embeddings.Response, error) {
+	var resp embeddings.Response
+	if err := r.decode(&resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ErrNoResponse is returned when decoding a result without a response.
+var ErrNoResponse = errors.New("batch result has no response")
+
+func (r *Result) decode(v any) error {
+	if r.Response == nil {
+		return fmt.Errorf("%w: %s", ErrNoResponse, r.CustomID)
+	}
+
+	if err := json.Unmarshal(r.Response.Body, v); err != nil {
+		return fmt.Errorf("error decoding batch result %s: %w", r.CustomID, err)
+	}
+
+	return nil
+}
+
+// A ResultResponse is the HTTP response to a single batch request.
+type ResultResponse struct {
+	StatusCode int             `json:"status_code"`
+	RequestID  string          `json:"request_id"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// A ResultError is the error for a single batch request that could not be
+// completed.
+type ResultError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// A ResultReader reads Results from a JSONL batch output or error file.
+type ResultReader struct {
+	dec *json.Decoder
+}
+
+// NewResultReader creates a new ResultReader that reads from r.
+func NewResultReader(r io.Reader) *ResultReader {
+	return &ResultReader{dec: json.NewDecoder(r)}
+}
+
+// Next returns the next result. It returns io.EOF when there are no more
+// results.
+func (r *ResultReader) Next() (*Result, error) {
+	var result Result
+	if err := r.dec.Decode(&result); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+
+		return nil, fmt.Errorf("error reading batch result: %w", err)
+	}
+
+	return &result, nil
+}
+
+// ReadResults streams a batch output or error file, calling fn with each
+// result as it is read.
+func (h *Service) ReadResults(ctx context.Context, fileID string, fn func(*Result) error) error {
+	pr, pw := io.Pipe()
+
+	go func() {
+		pw.CloseWithError((*files.Service)(h).Content(ctx, fileID, pw)) //nolint: errcheck // Always returns nil.
+	}()
+
+	defer pr.Close() //nolint: errcheck // Unblocks the writer if we stop early.
+
+	rr := NewResultReader(pr)
+
+	for {
+		result, err := rr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := fn(result); err != nil {
+			return err
+		}
+	}
+}
+
+// ChatCompletionResults reads the output and error files of a finished chat
+// completion batch.
+//
+// Successful completions and failed results are returned separately, both
+// keyed by custom ID.
+func (h *Service) ChatCompletionResults(
+	ctx context.Context,
+	b *Batch,
+) (map[string]*chat.CompletionResponse, map[string]*Result, error) {
+	return collectResults(ctx, h, b, (*Result).ChatCompletion)
+}
+
+// EmbeddingsResults reads the output and error files of a finished embeddings
+// batch.
+//
+// Successful responses and failed results are returned separately, both keyed
+// by custom ID.
+func (h *Service) EmbeddingsResults(
+	ctx context.Context,
+	b *Batch,
+) (map[string]*embeddings.Response, map[string]*Result, error) {
+	return collectResults(ctx, h, b, (*Result).Embeddings)
+}
+
+func collectResults[T any](
+	ctx context.Context,
+	h *Service,
+	b *Batch,
+	decode func(*Result) (*T, error),
+) (map[string]*T, map[string]*Result, error) {
+	succeeded := map[string]*T{}
+	failed := map[string]*Result{}
+
+	collect := func(result *Result) error {
+		if result.Failed() {
+			failed[result.CustomID] = result
+
+			return nil
+		}
+
+		v, err := decode(result)
+		if err != nil {
+			return err
+		}
+
+		succeeded[result.CustomID] = v
+
+		return nil
+	}
+
+	for _, fileID := range []*string{b.OutputFileID, b.ErrorFileID} {
+		if fileID == nil || *fileID == "" {
+			continue
+		}
+
+		if err := h.ReadResults(ctx, *fileID, collect); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return succeeded, failed, nil
+}
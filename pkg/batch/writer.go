@@ -0,0 +1,107 @@
+package batch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/jclem/openai-go/pkg/embeddings"
+)
+
+// ErrMixedEndpoints is returned when requests for different endpoints are
+// added to the same batch.
+var ErrMixedEndpoints = errors.New("all requests in a batch must use the same endpoint")
+
+// ErrDuplicateCustomID is returned when a custom ID is used more than once in
+// the same batch.
+var ErrDuplicateCustomID = errors.New("custom ID is already used in this batch")
+
+type inputLine struct {
+	CustomID string          `json:"custom_id"`
+	Method   string          `json:"method"`
+	URL      string          `json:"url"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// A Writer writes requests to a JSONL batch input file.
+type Writer struct {
+	enc      *json.Encoder
+	endpoint string
+	ids      map[string]struct{}
+}
+
+// NewWriter creates a new Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	return &Writer{enc: enc, ids: map[string]struct{}{}}
+}
+
+// AddChatCompletion adds a chat completion request to the batch.
+//
+// The options are the same as those for chat.Service.CreateCompletion.
+func (w *Writer) AddChatCompletion(
+	customID string,
+	model string,
+	messages []chat.Message,
+	opts ...chat.CreateCompletionOpt,
+) error {
+	body, err := chat.MarshalCompletionRequest(model, messages, opts...)
+	if err != nil {
+		return fmt.Errorf("error adding chat completion to batch: %w", err)
+	}
+
+	return w.add(customID, EndpointChatCompletions, body)
+}
+
+// AddEmbeddings adds an embeddings request to the batch.
+//
+// The options are the same as those for embeddings.Service.Create.
+func (w *Writer) AddEmbeddings(
+	customID string,
+	model string,
+	inputs []string,
+	opts ...embeddings.CreateOpt,
+) error {
+	body, err := embeddings.MarshalRequest(model, inputs, opts...)
+	if err != nil {
+		return fmt.Errorf("error adding embeddings to batch: %w", err)
+	}
+
+	return w.add(customID, EndpointEmbeddings, body)
+}
+
+func (w *Writer) add(customID, endpoint string, body json.RawMessage) error {
+	if w.endpoint != "" && w.endpoint != endpoint {
+		return fmt.Errorf("%w: %s and %s", ErrMixedEndpoints, w.endpoint, endpoint)
+	}
+
+	if _, ok := w.ids[customID]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateCustomID, customID)
+	}
+
+	line := inputLine{CustomID: customID, Method: http.MethodPost, URL: endpoint, Body: body}
+	if err := w.enc.Encode(line); err != nil {
+		return fmt.Errorf("error writing batch request: %w", err)
+	}
+
+	w.endpoint = endpoint
+	w.ids[customID] = struct{}{}
+
+	return nil
+}
+
+// Endpoint returns the endpoint of the requests in the batch, for use when
+// creating it.
+func (w *Writer) Endpoint() string {
+	return w.endpoint
+}
+
+// Len returns the number of requests written to the batch.
+func (w *Writer) Len() int {
+	return len(w.ids)
+}
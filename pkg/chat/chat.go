@@ -10,7 +10,6 @@ import (
 	"net/http"
 
 	"github.com/jclem/openai-go/internal/service"
-	"github.com/jclem/sseparser"
 )
 
 // A Message is a message in a chat prompt.
@@ -73,6 +72,7 @@ type completionRequest struct {
 	TopP             *float64             `json:"top_p,omitempty"`
 	N                *int                 `json:"n,omitempty"`
 	Stream           *bool                `json:"stream,omitempty"`
+	StreamOptions    *streamOptions       `json:"stream_options,omitempty"`
 	Stop             []string             `json:"stop,omitempty"`
 	MaxTokens        *int                 `json:"max_tokens,omitempty"`
 	PresencePenalty  *float64             `json:"presence_penalty,omitempty"`
@@ -218,9 +218,21 @@ type CompletionChoice struct {
 
 // A Usage defines usage statistics.
 type Usage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens            int                      `json:"prompt_tokens"`
+	CompletionTokens        int                      `json:"completion_tokens"`
+	TotalTokens             int                      `json:"total_tokens"`
+	PromptTokensDetails     *PromptTokensDetails     `json:"prompt_tokens_details,omitempty"`
+	CompletionTokensDetails *CompletionTokensDetails `json:"completion_tokens_details,omitempty"`
+}
+
+// PromptTokensDetails breaks down prompt token usage.
+type PromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// CompletionTokensDetails breaks down completion token usage.
+type CompletionTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
 }
 
 // CreateCompletionOpt is a functional option for configuring a completion request.
@@ -272,6 +284,18 @@ func WithN(n int) CreateCompletionOpt {
 	}
 }
 
+// WithStreamIncludeUsage requests a final stream chunk with the token usage
+// of the request (see StreamingCompletionObject.Usage).
+func WithStreamIncludeUsage() CreateCompletionOpt {
+	return func(r *completionRequest) {
+		r.StreamOptions = &streamOptions{IncludeUsage: true}
+	}
+}
+
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
 // WithStream sets the stream for the completion request.
 func WithStream(stream bool) CreateCompletionOpt {
 	return func(r *completionRequest) {
@@ -328,6 +352,25 @@ func WithAPIKey(apiKey string) CreateCompletionOpt {
 	}
 }
 
+// MarshalCompletionRequest returns the JSON body of a completion request.
+//
+// It is useful when a request is not sent directly, such as in a batch input
+// file.
+func MarshalCompletionRequest(model string, messages []Message, opts ...CreateCompletionOpt) ([]byte, error) {
+	req := completionRequest{Model: model, Messages: messages}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling completion request: %w", err)
+	}
+
+	return b, nil
+}
+
 // Service is a service wrapping an OpenAI-compatible completions API.
 type Service service.Service
 
@@ -346,6 +389,21 @@ func (h *Service) CreateCompletion(
 		opt(&req)
 	}
 
+	ctx, obs := h.Client.StartOperation(ctx, req.operation())
+
+	resp, err := h.createCompletion(ctx, req)
+	if err != nil {
+		obs.End(service.OperationResult{}, err)
+
+		return nil, err
+	}
+
+	obs.End(resp.operationResult(), nil)
+
+	return resp, nil
+}
+
+func (h *Service) createCompletion(ctx context.Context, req completionRequest) (*CompletionResponse, error) {
 	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/chat/completions", req,
 		service.WithAPIKey(req.apiKey))
 	if err != nil {
@@ -378,22 +436,52 @@ func (h *Service) CreateStreamingCompletion(
 		opt(&req)
 	}
 
+	ctx, obs := h.Client.StartOperation(ctx, req.operation())
+
 	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/chat/completions", req,
 		service.WithAPIKey(req.apiKey))
 	if err != nil {
-		return nil, fmt.Errorf("error creating HTTP request: %w", err)
+		err = fmt.Errorf("error creating HTTP request: %w", err)
+		obs.End(service.OperationResult{}, err)
+
+		return nil, err
 	}
 
 	httpResp, err := h.Client.Do(httpReq, nil) //nolint: bodyclose // False positive.
 	if err != nil {
-		return nil, fmt.Errorf("error performing HTTP request: %w", err)
+		err = fmt.Errorf("error performing HTTP request: %w", err)
+		obs.End(service.OperationResult{}, err)
+
+		return nil, err
 	}
 
-	return newStreamingCompletionResponse(httpResp.Body), nil
+	return newStreamingCompletionResponse(httpResp.Body, obs), nil
+}
+
+func (r *completionRequest) operation() service.Operation {
+	return service.Operation{
+		Name:         "chat",
+		RequestModel: r.Model,
+		Stream:       r.Stream != nil && *r.Stream,
+		Temperature:  r.Temperature,
+		TopP:         r.TopP,
+		MaxTokens:    r.MaxTokens,
+	}
 }
 
-type streamingCompletionEvent struct {
-	Data StreamingCompletionObject `sse:"data"`
+func (r *CompletionResponse) operationResult() service.OperationResult {
+	result := service.OperationResult{
+		ResponseID:    r.ID,
+		ResponseModel: r.Model,
+		InputTokens:   r.Usage.PromptTokens,
+		OutputTokens:  r.Usage.CompletionTokens,
+	}
+
+	for _, choice := range r.Choices {
+		result.FinishReasons = append(result.FinishReasons, choice.FinishReason)
+	}
+
+	return result
 }
 
 // A StreamingCompletionObject is a single chunk of a streaming chat
@@ -404,6 +492,10 @@ type StreamingCompletionObject struct {
 	Created int64                       `json:"created"`
 	Model   string                      `json:"model"`
 	Choices []StreamingCompletionChoice `json:"choices"`
+
+	// Usage is only set on the final chunk of a stream requested with
+	// WithStreamIncludeUsage.
+	Usage *Usage `json:"usage,omitempty"`
 }
 
 // GetChoiceAt returns the choice at the given index.
@@ -446,7 +538,11 @@ func (o *StreamingCompletionObject) GetFunctionCallAt(index int) (FunctionCall,
 const streamDoneString = "[DONE]"
 
 // ErrStreamDone is returned when the stream is done (marked by "[DONE]").
-var ErrStreamDone = errors.New("completion stream is done")
+var ErrStreamDone = service.ErrStreamDone
+
+// ErrStreamClosed is reported to instrumentation (see openai.WithInstrumenter)
+// when a stream is closed before it is done.
+var ErrStreamClosed = errors.New("stream closed before it was done")
 
 // UnmarshalSSEValue implements sseparser.UnmarshalerSSEValue.
 func (o *StreamingCompletionObject) UnmarshalSSEValue(v string) error {
@@ -483,43 +579,80 @@ type StreamingCompletionDelta struct {
 //
 // The caller is responsible for closing the stream (`stream.Close()`).
 type StreamingCompletionResponse struct {
-	closer  io.Closer
-	scanner *sseparser.StreamScanner
+	stream *service.Stream[StreamingCompletionObject]
+
+	obs        service.OperationObserver
+	result     service.OperationResult
+	firstToken bool
+	ended      bool
 }
 
 // Next returns the next object in the streaming response.
 //
-// When the stream is complete, it returns nil, nil.
+// When the stream is complete, it returns ErrStreamDone.
 func (s *StreamingCompletionResponse) Next() (*StreamingCompletionObject, error) {
-	var evt streamingCompletionEvent
-
-	_, err := s.scanner.UnmarshalNext(&evt)
+	obj, err := s.stream.Next()
 	if err != nil {
-		if errors.Is(err, sseparser.ErrStreamEOF) {
-			return nil, fmt.Errorf("stream ended before [DONE]: %w", err)
-		}
-
 		if errors.Is(err, ErrStreamDone) {
-			return nil, ErrStreamDone
+			s.end(nil)
+		} else {
+			s.end(err)
 		}
 
-		return nil, fmt.Errorf("error reading next object from stream: %w", err)
+		return nil, err //nolint: wrapcheck // Errors are already wrapped.
 	}
 
-	return &evt.Data, nil
+	s.observe(obj)
+
+	return obj, nil
 }
 
-// Close closes the stream.
+// Close closes the stream. Closing a stream before it is done ends its
+// operation with ErrStreamClosed.
 func (s *StreamingCompletionResponse) Close() error {
-	if err := s.closer.Close(); err != nil {
-		return fmt.Errorf("error closing stream: %w", err)
+	s.end(ErrStreamClosed)
+
+	return s.stream.Close() //nolint: wrapcheck // Errors are already wrapped.
+}
+
+// observe records a stream object for the stream's operation.
+func (s *StreamingCompletionResponse) observe(obj *StreamingCompletionObject) {
+	if obj.ID != "" {
+		s.result.ResponseID = obj.ID
 	}
 
-	return nil
+	if obj.Model != "" {
+		s.result.ResponseModel = obj.Model
+	}
+
+	if obj.Usage != nil {
+		s.result.InputTokens = obj.Usage.PromptTokens
+		s.result.OutputTokens = obj.Usage.CompletionTokens
+	}
+
+	for _, choice := range obj.Choices {
+		// The first object usually has only a role and empty content.
+		hasContent := choice.Delta.Content != nil && *choice.Delta.Content != ""
+		if !s.firstToken && (hasContent || choice.Delta.FunctionCall != nil) {
+			s.firstToken = true
+			s.obs.FirstToken()
+		}
+
+		if choice.FinishReason != nil {
+			s.result.FinishReasons = append(s.result.FinishReasons, *choice.FinishReason)
+		}
+	}
 }
 
-func newStreamingCompletionResponse(rc io.ReadCloser) *StreamingCompletionResponse {
-	scanner := sseparser.NewStreamScanner(rc)
+func (s *StreamingCompletionResponse) end(err error) {
+	if s.ended {
+		return
+	}
+
+	s.ended = true
+	s.obs.End(s.result, err)
+}
 
-	return &StreamingCompletionResponse{closer: rc, scanner: scanner}
+func newStreamingCompletionResponse(rc io.ReadCloser, obs service.OperationObserver) *StreamingCompletionResponse {
+	return &StreamingCompletionResponse{stream: service.NewStream[StreamingCompletionObject](rc), obs: obs}
 }
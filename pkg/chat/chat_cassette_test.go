@@ -1,6 +1,3 @@
-//go:build integration
-// +build integration
-
 package chat_test
 
 import (
@@ -12,16 +9,22 @@ import (
 
 	"github.com/jclem/openai-go"
 	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/openaitest"
 	"github.com/jclem/openai-go/pkg/chat"
 	"github.com/stretchr/testify/require"
 )
 
 var key = os.Getenv("OPENAI_API_KEY")
 
+// These tests replay hand-written cassettes in testdata, not recordings of the
+// live API. Set OPENAITEST_RECORD (and OPENAI_API_KEY) to record them from the
+// API instead.
+
 func TestCreateChatCompletion(t *testing.T) {
 	t.Parallel()
 
-	svc := service.New(openai.DefaultBaseURL, key, http.DefaultClient)
+	doer := openaitest.NewCassette(t, "testdata/create_chat_completion.json", http.DefaultClient)
+	svc := service.New(openai.DefaultBaseURL, key, doer)
 	c := (*chat.Service)(svc)
 
 	messages := []chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hello, world."))}
@@ -33,7 +36,8 @@ func TestCreateChatCompletion(t *testing.T) {
 func TestCreateStreamingChatCompletion(t *testing.T) {
 	t.Parallel()
 
-	svc := service.New(openai.DefaultBaseURL, key, http.DefaultClient)
+	doer := openaitest.NewCassette(t, "testdata/create_streaming_chat_completion.json", http.DefaultClient)
+	svc := service.New(openai.DefaultBaseURL, key, doer)
 	c := (*chat.Service)(svc)
 
 	messages := []chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hello, world."))}
@@ -0,0 +1,293 @@
+// Package completions provides a legacy text completions client for the
+// OpenAI API.
+//
+// New applications should use the chat package. This package exists for base
+// models and OpenAI-compatible servers that still expose "/completions".
+package completions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+type request struct {
+	apiKey string
+
+	Model            string             `json:"model"`
+	Prompt           string             `json:"prompt"`
+	Suffix           *string            `json:"suffix,omitempty"`
+	MaxTokens        *int               `json:"max_tokens,omitempty"`
+	Temperature      *float64           `json:"temperature,omitempty"`
+	TopP             *float64           `json:"top_p,omitempty"`
+	N                *int               `json:"n,omitempty"`
+	Stream           *bool              `json:"stream,omitempty"`
+	Logprobs         *int               `json:"logprobs,omitempty"`
+	Echo             *bool              `json:"echo,omitempty"`
+	Stop             []string           `json:"stop,omitempty"`
+	PresencePenalty  *float64           `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64           `json:"frequency_penalty,omitempty"`
+	BestOf           *int               `json:"best_of,omitempty"`
+	LogitBias        map[string]float64 `json:"logit_bias,omitempty"`
+	Seed             *int               `json:"seed,omitempty"`
+	User             *string            `json:"user,omitempty"`
+}
+
+// CreateOpt is a functional option for configuring a completion request.
+type CreateOpt func(*request)
+
+// WithSuffix sets the suffix that comes after the completion.
+func WithSuffix(suffix string) CreateOpt {
+	return func(r *request) {
+		r.Suffix = &suffix
+	}
+}
+
+// WithMaxTokens sets the max tokens for the completion request.
+func WithMaxTokens(maxTokens int) CreateOpt {
+	return func(r *request) {
+		r.MaxTokens = &maxTokens
+	}
+}
+
+// WithTemperature sets the temperature for the completion request.
+func WithTemperature(temperature float64) CreateOpt {
+	return func(r *request) {
+		r.Temperature = &temperature
+	}
+}
+
+// WithTopP sets the top p for the completion request.
+func WithTopP(topP float64) CreateOpt {
+	return func(r *request) {
+		r.TopP = &topP
+	}
+}
+
+// WithN sets the n for the completion request.
+func WithN(n int) CreateOpt {
+	return func(r *request) {
+		r.N = &n
+	}
+}
+
+// WithLogprobs sets the number of most likely tokens to return log
+// probabilities for, at each position.
+func WithLogprobs(logprobs int) CreateOpt {
+	return func(r *request) {
+		r.Logprobs = &logprobs
+	}
+}
+
+// WithEcho sets whether the prompt is echoed back in addition to the
+// completion.
+func WithEcho(echo bool) CreateOpt {
+	return func(r *request) {
+		r.Echo = &echo
+	}
+}
+
+// WithStop sets the stop for the completion request.
+func WithStop(stop ...string) CreateOpt {
+	return func(r *request) {
+		r.Stop = stop
+	}
+}
+
+// WithPresencePenalty sets the presence penalty for the completion request.
+func WithPresencePenalty(presencePenalty float64) CreateOpt {
+	return func(r *request) {
+		r.PresencePenalty = &presencePenalty
+	}
+}
+
+// WithFrequencyPenalty sets the frequency penalty for the completion request.
+func WithFrequencyPenalty(frequencyPenalty float64) CreateOpt {
+	return func(r *request) {
+		r.FrequencyPenalty = &frequencyPenalty
+	}
+}
+
+// WithBestOf sets the number of completions to generate server-side, of which
+// the best is returned.
+func WithBestOf(bestOf int) CreateOpt {
+	return func(r *request) {
+		r.BestOf = &bestOf
+	}
+}
+
+// WithLogitBias sets the logit bias for the completion request.
+func WithLogitBias(logitBias map[string]float64) CreateOpt {
+	return func(r *request) {
+		r.LogitBias = logitBias
+	}
+}
+
+// WithSeed sets the seed for the completion request.
+func WithSeed(seed int) CreateOpt {
+	return func(r *request) {
+		r.Seed = &seed
+	}
+}
+
+// WithUser sets the user for the completion request.
+func WithUser(user string) CreateOpt {
+	return func(r *request) {
+		r.User = &user
+	}
+}
+
+// WithAPIKey sets the API key for the completion request.
+func WithAPIKey(apiKey string) CreateOpt {
+	return func(r *request) {
+		r.apiKey = apiKey
+	}
+}
+
+// A Response is a response to a completion request, or a single chunk of a
+// streaming completion response.
+type Response struct {
+	ID      string   `json:"id"`
+	Object  string   `json:"object"`
+	Created int64    `json:"created"`
+	Model   string   `json:"model"`
+	Choices []Choice `json:"choices"`
+	Usage   *Usage   `json:"usage,omitempty"`
+}
+
+// GetChoiceAt returns the choice at the given index.
+//
+// If the index is out of bounds, it returns false (but a negative index will
+// panic).
+func (r *Response) GetChoiceAt(index int) (Choice, bool) {
+	if index >= len(r.Choices) {
+		return Choice{}, false
+	}
+
+	return r.Choices[index], true
+}
+
+// GetTextAt returns the text of the choice at the given index.
+func (r *Response) GetTextAt(index int) (string, bool) {
+	choice, ok := r.GetChoiceAt(index)
+	if !ok {
+		return "", false
+	}
+
+	return choice.Text, true
+}
+
+// A Choice is a completion choice in a completion response.
+type Choice struct {
+	Text         string    `json:"text"`
+	Index        int       `json:"index"`
+	Logprobs     *Logprobs `json:"logprobs,omitempty"`
+	FinishReason *string   `json:"finish_reason"`
+}
+
+// Logprobs holds the log probabilities of a choice's tokens.
+type Logprobs struct {
+	Tokens        []string             `json:"tokens"`
+	TokenLogprobs []float64            `json:"token_logprobs"`
+	TopLogprobs   []map[string]float64 `json:"top_logprobs"`
+	TextOffset    []int                `json:"text_offset"`
+}
+
+// A Usage defines usage statistics.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Service is a service wrapping an OpenAI-compatible legacy completions API.
+type Service service.Service
+
+// Create creates a completion for a prompt.
+func (h *Service) Create(
+	ctx context.Context,
+	model string,
+	prompt string,
+	opts ...CreateOpt,
+) (*Response, error) {
+	req := request{Model: model, Prompt: prompt}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/completions", req,
+		service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request: %w", err)
+	}
+
+	var resp Response
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing HTTP request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// CreateStreaming creates a streaming completion for a prompt.
+//
+// It returns a StreamingResponse. The caller is responsible for closing it.
+func (h *Service) CreateStreaming(
+	ctx context.Context,
+	model string,
+	prompt string,
+	opts ...CreateOpt,
+) (*StreamingResponse, error) {
+	req := request{Model: model, Prompt: prompt}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	stream := true
+	req.Stream = &stream
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/completions", req,
+		service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request: %w", err)
+	}
+
+	httpResp, err := h.Client.Do(httpReq, nil) //nolint: bodyclose // False positive.
+	if err != nil {
+		return nil, fmt.Errorf("error performing HTTP request: %w", err)
+	}
+
+	return newStreamingResponse(httpResp.Body), nil
+}
+
+// ErrStreamDone is returned when the stream is done (marked by "[DONE]").
+var ErrStreamDone = service.ErrStreamDone
+
+// A StreamingResponse is a streaming response to a completion request. It
+// reads an io.ReadCloser and emits Responses, each holding a chunk of text.
+//
+// The caller is responsible for closing the stream (`stream.Close()`).
+type StreamingResponse struct {
+	stream *service.Stream[Response]
+}
+
+// Next returns the next chunk in the streaming response.
+//
+// When the stream is complete, it returns ErrStreamDone.
+func (s *StreamingResponse) Next() (*Response, error) {
+	return s.stream.Next() //nolint: wrapcheck // Errors are already wrapped.
+}
+
+// Close closes the stream.
+func (s *StreamingResponse) Close() error {
+	return s.stream.Close() //nolint: wrapcheck // Errors are already wrapped.
+}
+
+func newStreamingResponse(rc io.ReadCloser) *StreamingResponse {
+	return &StreamingResponse{stream: service.NewStream[Response](rc)}
+}
@@ -0,0 +1,104 @@
+package completions_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/completions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_CreateCompletion(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		r := &http.Response{}
+		r.StatusCode = http.StatusOK
+		r.Body = httptesting.NewTestBody(strings.NewReader(`{
+			"object": "text_completion",
+			"choices": [{"text": " world", "index": 0, "finish_reason": "length",
+				"logprobs": {"tokens": [" world"], "token_logprobs": [-0.1]}}]
+		}`))
+
+		return r, nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*completions.Service)(svc)
+
+	resp, err := c.Create(
+		context.Background(),
+		"davinci-002",
+		"Hello,",
+		completions.WithEcho(true),
+		completions.WithBestOf(2),
+		completions.WithLogprobs(1),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"model":    "davinci-002",
+		"prompt":   "Hello,",
+		"echo":     true,
+		"best_of":  float64(2),
+		"logprobs": float64(1),
+	}, body)
+
+	text, ok := resp.GetTextAt(0)
+	require.True(t, ok)
+	assert.Equal(t, " world", text)
+	assert.Equal(t, []string{" world"}, resp.Choices[0].Logprobs.Tokens)
+}
+
+func TestHTTPClient_CreateStreamingCompletion(t *testing.T) {
+	t.Parallel()
+
+	sse := `data: {"choices": [{"text": "Hello", "index": 0, "finish_reason": null}]}
+
+data: {"choices": [{"text": " world", "index": 0, "finish_reason": "stop"}]}
+
+data: [DONE]
+
+`
+
+	r := &http.Response{}
+	r.StatusCode = http.StatusOK
+	r.Body = httptesting.NewTestBody(strings.NewReader(sse))
+	doer := httptesting.NewTestDoer(r, nil)
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", &doer)
+	c := (*completions.Service)(svc)
+
+	stream, err := c.CreateStreaming(context.Background(), "davinci-002", "Say hello")
+	require.NoError(t, err)
+
+	var text string
+
+	for {
+		chunk, err := stream.Next()
+		if err != nil {
+			require.ErrorIs(t, err, completions.ErrStreamDone)
+
+			break
+		}
+
+		chunkText, _ := chunk.GetTextAt(0)
+		text += chunkText
+	}
+
+	assert.Equal(t, "Hello world", text)
+	require.NoError(t, stream.Close())
+}
@@ -0,0 +1,416 @@
+// Package costs tracks the token usage and cost of API requests.
+//
+// A Tracker is attached to a client as middleware:
+//
+//	tracker := costs.NewTracker(pricing, costs.WithBudget(100))
+//	client := openai.NewClient(openai.WithMiddleware(tracker.Middleware))
+//
+// It records the usage reported by every response (including streams that
+// report usage), aggregated by model, user and tag.
+package costs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/batch"
+)
+
+// Tokens are the token counts of a request.
+type Tokens struct {
+	// Input is the number of input tokens, including cached tokens.
+	Input int
+
+	// CachedInput is the number of input tokens read from the cache.
+	CachedInput int
+
+	// Output is the number of output tokens, including reasoning tokens.
+	Output int
+
+	// Reasoning is the number of output tokens used for reasoning.
+	Reasoning int
+}
+
+// A Usage is aggregated usage.
+type Usage struct {
+	// Requests is the number of requests that reported usage.
+	Requests int
+
+	Tokens
+
+	// Cost is the cost of the usage, in dollars. Usage of models without a
+	// price costs nothing.
+	Cost float64
+}
+
+func (u *Usage) add(tokens Tokens, cost float64) {
+	u.Requests++
+	u.Input += tokens.Input
+	u.CachedInput += tokens.CachedInput
+	u.Output += tokens.Output
+	u.Reasoning += tokens.Reasoning
+	u.Cost += cost
+}
+
+// A Snapshot is a copy of a tracker's aggregated usage.
+type Snapshot struct {
+	Total   Usage
+	ByModel map[string]Usage
+	ByUser  map[string]Usage
+	ByTag   map[string]Usage
+
+	// Unpriced lists models that were used but have no price.
+	Unpriced []string
+}
+
+// A Record is the usage of a single request.
+type Record struct {
+	Model  string
+	User   string
+	Tags   []string
+	Tokens Tokens
+
+	// Batch is whether the request was made with the batch API, and so gets
+	// the model's batch discount.
+	Batch bool
+}
+
+// ErrBudgetExceeded is returned (wrapped in a *BudgetError) for requests made
+// after a budget is spent.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// A BudgetError is returned for a request made after a budget is spent.
+type BudgetError struct {
+	// Scope is the scope of the budget: "total", "user" or "tag".
+	Scope string
+
+	// Key is the user or tag of the budget, or empty for the total budget.
+	Key string
+
+	Limit float64
+	Spent float64
+}
+
+// Error implements the error interface.
+func (e *BudgetError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%s %s: spent $%.4f of $%.4f", e.Scope, ErrBudgetExceeded, e.Spent, e.Limit)
+	}
+
+	return fmt.Sprintf("%s %q %s: spent $%.4f of $%.4f", e.Scope, e.Key, ErrBudgetExceeded, e.Spent, e.Limit)
+}
+
+// Unwrap returns ErrBudgetExceeded.
+func (e *BudgetError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+// A TrackerOpt is a functional option for configuring a Tracker.
+type TrackerOpt func(*Tracker)
+
+// WithBudget sets a limit, in dollars, on the total cost of requests.
+func WithBudget(limit float64) TrackerOpt {
+	return func(t *Tracker) {
+		t.budget = &limit
+	}
+}
+
+// WithUserBudget sets a limit, in dollars, on the cost of a user's requests.
+func WithUserBudget(user string, limit float64) TrackerOpt {
+	return func(t *Tracker) {
+		t.userBudgets[user] = limit
+	}
+}
+
+// WithTagBudget sets a limit, in dollars, on the cost of requests with a tag.
+func WithTagBudget(tag string, limit float64) TrackerOpt {
+	return func(t *Tracker) {
+		t.tagBudgets[tag] = limit
+	}
+}
+
+// A Tracker aggregates the usage and cost of requests, and enforces budgets.
+// It is safe for concurrent use.
+//
+// Budgets are checked before each request is sent, so concurrent requests can
+// together overspend a budget by the cost of the requests in flight.
+type Tracker struct {
+	pricing     *Pricing
+	budget      *float64
+	userBudgets map[string]float64
+	tagBudgets  map[string]float64
+
+	mu       sync.Mutex
+	total    Usage
+	byModel  map[string]Usage
+	byUser   map[string]Usage
+	byTag    map[string]Usage
+	unpriced map[string]bool
+}
+
+// NewTracker creates a Tracker that prices usage with pricing.
+func NewTracker(pricing *Pricing, opts ...TrackerOpt) *Tracker {
+	t := &Tracker{
+		pricing:     pricing,
+		userBudgets: make(map[string]float64),
+		tagBudgets:  make(map[string]float64),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	t.Reset()
+
+	return t
+}
+
+// Reset discards the tracker's aggregated usage, which resets its budgets.
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.total = Usage{}
+	t.byModel = make(map[string]Usage)
+	t.byUser = make(map[string]Usage)
+	t.byTag = make(map[string]Usage)
+	t.unpriced = make(map[string]bool)
+}
+
+// Record records the usage of a request, and returns its cost.
+//
+// Requests made through a client with the tracker's middleware are recorded
+// automatically. Use Record for usage reported elsewhere, such as batch
+// results (see RecordBatchResult).
+func (t *Tracker) Record(r Record) float64 {
+	price, ok := t.pricing.Lookup(r.Model)
+	cost := price.Cost(r.Tokens, r.Batch)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !ok {
+		t.unpriced[r.Model] = true
+	}
+
+	t.total.add(r.Tokens, cost)
+	addTo(t.byModel, r.Model, r.Tokens, cost)
+
+	if r.User != "" {
+		addTo(t.byUser, r.User, r.Tokens, cost)
+	}
+
+	for _, tag := range r.Tags {
+		addTo(t.byTag, tag, r.Tokens, cost)
+	}
+
+	return cost
+}
+
+func addTo(m map[string]Usage, key string, tokens Tokens, cost float64) {
+	u := m[key]
+	u.add(tokens, cost)
+	m[key] = u
+}
+
+// RecordBatchResult records the usage of a batch result, with the model's
+// batch discount. Results without usage are ignored.
+func (t *Tracker) RecordBatchResult(result *batch.Result, user string, tags ...string) {
+	if result.Response == nil {
+		return
+	}
+
+	model, tokens, ok := ParseUsage(result.Response.Body)
+	if !ok {
+		return
+	}
+
+	t.Record(Record{Model: model, User: user, Tags: tags, Tokens: tokens, Batch: true})
+}
+
+// Snapshot returns a copy of the tracker's aggregated usage.
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return Snapshot{
+		Total:    t.total,
+		ByModel:  maps.Clone(t.byModel),
+		ByUser:   maps.Clone(t.byUser),
+		ByTag:    maps.Clone(t.byTag),
+		Unpriced: slices.Sorted(maps.Keys(t.unpriced)),
+	}
+}
+
+// checkBudgets returns a *BudgetError if a budget that applies to a request
+// by user, with tags, is spent.
+func (t *Tracker) checkBudgets(user string, tags []string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.budget != nil && t.total.Cost >= *t.budget {
+		return &BudgetError{Scope: "total", Limit: *t.budget, Spent: t.total.Cost}
+	}
+
+	if limit, ok := t.userBudgets[user]; ok && user != "" && t.byUser[user].Cost >= limit {
+		return &BudgetError{Scope: "user", Key: user, Limit: limit, Spent: t.byUser[user].Cost}
+	}
+
+	for _, tag := range tags {
+		if limit, ok := t.tagBudgets[tag]; ok && t.byTag[tag].Cost >= limit {
+			return &BudgetError{Scope: "tag", Key: tag, Limit: limit, Spent: t.byTag[tag].Cost}
+		}
+	}
+
+	return nil
+}
+
+type tagsKey struct{}
+
+// WithTags returns a copy of ctx whose requests are tagged with tags (in
+// addition to any tags ctx already has), for aggregation and tag budgets.
+func WithTags(ctx context.Context, tags ...string) context.Context {
+	return context.WithValue(ctx, tagsKey{}, append(slices.Clip(Tags(ctx)), tags...))
+}
+
+// Tags returns the tags of ctx.
+func Tags(ctx context.Context) []string {
+	tags, _ := ctx.Value(tagsKey{}).([]string)
+
+	return tags
+}
+
+// Middleware records the usage of requests, and rejects requests whose
+// budgets are spent. Add it to a client with openai.WithMiddleware.
+func (t *Tracker) Middleware(next service.Handler) service.Handler {
+	return func(req *service.Request) (*service.Response, error) {
+		var body struct {
+			User string `json:"user"`
+		}
+
+		// Bodies that aren't JSON objects (such as forms) have no user.
+		_ = req.DecodeBody(&body)
+
+		rec := Record{Model: req.Model, User: body.User, Tags: Tags(req.Context())}
+
+		if err := t.checkBudgets(rec.User, rec.Tags); err != nil {
+			return nil, err
+		}
+
+		resp, err := next(req)
+		if err != nil {
+			return resp, err
+		}
+
+		if resp.Body == nil && resp.HTTPResponse != nil {
+			if !isEventStream(resp.HTTPResponse) {
+				return resp, nil
+			}
+
+			// Streams are read by the service, so usage is recorded as
+			// the stream is read.
+			resp.HTTPResponse.Body = newStreamReader(resp.HTTPResponse.Body, func(model string, tokens Tokens) {
+				t.Record(withUsage(rec, model, tokens))
+			})
+
+			return resp, nil
+		}
+
+		b, err := json.Marshal(resp.Body)
+		if err != nil {
+			return resp, nil //nolint: nilerr // Responses that can't be encoded have no usage.
+		}
+
+		if model, tokens, ok := ParseUsage(b); ok {
+			t.Record(withUsage(rec, model, tokens))
+		}
+
+		return resp, nil
+	}
+}
+
+// isEventStream reports whether a response is a stream of server-sent events.
+func isEventStream(resp *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	return mediaType == "text/event-stream"
+}
+
+// withUsage returns rec with its tokens set, and its model set to the
+// response model if the request did not name one.
+func withUsage(rec Record, model string, tokens Tokens) Record {
+	if rec.Model == "" {
+		rec.Model = model
+	}
+
+	rec.Tokens = tokens
+
+	return rec
+}
+
+type apiUsage struct {
+	PromptTokens        int `json:"prompt_tokens"`
+	CompletionTokens    int `json:"completion_tokens"`
+	InputTokens         int `json:"input_tokens"`
+	OutputTokens        int `json:"output_tokens"`
+	PromptTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
+	InputTokensDetails struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"input_tokens_details"`
+	CompletionTokensDetails struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"completion_tokens_details"`
+	OutputTokensDetails struct {
+		ReasoningTokens int `json:"reasoning_tokens"`
+	} `json:"output_tokens_details"`
+}
+
+func (u apiUsage) tokens() Tokens {
+	return Tokens{
+		Input:       u.PromptTokens + u.InputTokens,
+		CachedInput: u.PromptTokensDetails.CachedTokens + u.InputTokensDetails.CachedTokens,
+		Output:      u.CompletionTokens + u.OutputTokens,
+		Reasoning:   u.CompletionTokensDetails.ReasoningTokens + u.OutputTokensDetails.ReasoningTokens,
+	}
+}
+
+type usageBody struct {
+	Model string    `json:"model"`
+	Usage *apiUsage `json:"usage"`
+
+	// Response holds the usage of responses API stream events.
+	Response *struct {
+		Model string    `json:"model"`
+		Usage *apiUsage `json:"usage"`
+	} `json:"response"`
+}
+
+// ParseUsage parses the model and token usage of a JSON response body (or
+// stream event). It understands both the chat completions ("prompt_tokens")
+// and responses ("input_tokens") forms of usage.
+func ParseUsage(body []byte) (string, Tokens, bool) {
+	var v usageBody
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "", Tokens{}, false
+	}
+
+	if v.Usage != nil {
+		return v.Model, v.Usage.tokens(), true
+	}
+
+	if v.Response != nil && v.Response.Usage != nil {
+		return v.Response.Model, v.Response.Usage.tokens(), true
+	}
+
+	return "", Tokens{}, false
+}
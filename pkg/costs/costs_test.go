@@ -0,0 +1,258 @@
+package costs_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/pkg/batch"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/jclem/openai-go/pkg/costs"
+	"github.com/jclem/openai-go/pkg/embeddings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPricing() *costs.Pricing {
+	return costs.NewPricing(map[string]costs.Price{
+		"gpt-4o":                 {Input: 2.5, CachedInput: 1.25, Output: 10, BatchDiscount: 0.5},
+		"o3":                     {Input: 2, Output: 8, Reasoning: 16},
+		"text-embedding-3-small": {Input: 0.02},
+	})
+}
+
+func newClient(tracker *costs.Tracker, bodies ...string) (*openai.Client, *int) {
+	requests := 0
+
+	return openai.NewClient(
+		openai.WithMiddleware(tracker.Middleware),
+		openai.WithDoer(httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+			body := bodies[min(requests, len(bodies)-1)]
+			requests++
+
+			contentType := "application/json"
+			if strings.HasPrefix(body, "data:") {
+				contentType = "text/event-stream; charset=utf-8"
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {contentType}},
+				Body:       httptesting.NewTestBody(strings.NewReader(body)),
+			}, nil
+		})),
+	), &requests
+}
+
+func messages() []chat.Message {
+	return []chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hi"))}
+}
+
+func TestPrice_Cost(t *testing.T) {
+	t.Parallel()
+
+	price := costs.Price{Input: 2, CachedInput: 1, Output: 8, Reasoning: 16, BatchDiscount: 0.5}
+	tokens := costs.Tokens{Input: 1_000_000, CachedInput: 500_000, Output: 1_000_000, Reasoning: 250_000}
+
+	// 0.5M * $2 + 0.5M * $1 + 0.75M * $8 + 0.25M * $16
+	assert.InDelta(t, 11.5, price.Cost(tokens, false), 1e-9)
+	assert.InDelta(t, 5.75, price.Cost(tokens, true), 1e-9)
+}
+
+func TestPricing_Lookup(t *testing.T) {
+	t.Parallel()
+
+	pricing := newPricing()
+
+	price, ok := pricing.Lookup("gpt-4o-2024-08-06")
+	require.True(t, ok)
+	assert.InDelta(t, 2.5, price.Input, 0)
+
+	_, ok = pricing.Lookup("gpt-4")
+	assert.False(t, ok)
+
+	// Only dated snapshots fall back to their base model's price.
+	_, ok = pricing.Lookup("gpt-4o-mini")
+	assert.False(t, ok)
+
+	_, ok = pricing.Lookup("gpt-4o-mini-2024-07-18")
+	assert.False(t, ok)
+
+	pricing.Set("gpt-4", costs.Price{Input: 30})
+
+	price, ok = pricing.Lookup("gpt-4")
+	require.True(t, ok)
+	assert.InDelta(t, 30, price.Input, 0)
+}
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := costs.NewTracker(newPricing())
+	client, _ := newClient(tracker,
+		`{"model":"gpt-4o-2024-08-06","usage":{"prompt_tokens":1000,"completion_tokens":100,`+
+			`"prompt_tokens_details":{"cached_tokens":400}}}`,
+		`{"model":"text-embedding-3-small","usage":{"prompt_tokens":5000,"total_tokens":5000}}`,
+		`{"model":"gpt-5","usage":{"prompt_tokens":10,"completion_tokens":10}}`,
+		`{"model":"gpt-4o-mini","usage":{"prompt_tokens":10,"completion_tokens":10}}`)
+
+	ctx := costs.WithTags(context.Background(), "team:search")
+
+	_, err := client.Chat.CreateCompletion(ctx, "gpt-4o", messages(), chat.WithUser("alice"))
+	require.NoError(t, err)
+
+	_, err = client.Embeddings.Create(costs.WithTags(ctx, "job:index"), "text-embedding-3-small",
+		[]string{"Hi"}, embeddings.WithUser("bob"))
+	require.NoError(t, err)
+
+	_, err = client.Chat.CreateCompletion(context.Background(), "gpt-5", messages())
+	require.NoError(t, err)
+
+	_, err = client.Chat.CreateCompletion(context.Background(), "gpt-4o-mini", messages())
+	require.NoError(t, err)
+
+	snap := tracker.Snapshot()
+
+	// 600 * $2.5 + 400 * $1.25 + 100 * $10, per million.
+	chatCost := 0.003
+	embeddingsCost := 0.0001
+
+	assert.Equal(t, 4, snap.Total.Requests)
+	assert.Equal(t, 6020, snap.Total.Input)
+	assert.InDelta(t, chatCost+embeddingsCost, snap.Total.Cost, 1e-12)
+	assert.InDelta(t, chatCost, snap.ByModel["gpt-4o"].Cost, 1e-12)
+	assert.Equal(t, 400, snap.ByModel["gpt-4o"].CachedInput)
+	assert.InDelta(t, chatCost, snap.ByUser["alice"].Cost, 1e-12)
+	assert.InDelta(t, embeddingsCost, snap.ByUser["bob"].Cost, 1e-12)
+	assert.Equal(t, 2, snap.ByTag["team:search"].Requests)
+	assert.Equal(t, 1, snap.ByTag["job:index"].Requests)
+	assert.Equal(t, 1, snap.ByModel["gpt-5"].Requests)
+	assert.Equal(t, []string{"gpt-4o-mini", "gpt-5"}, snap.Unpriced)
+
+	tracker.Reset()
+	assert.Zero(t, tracker.Snapshot().Total)
+}
+
+func TestTracker_Stream(t *testing.T) {
+	t.Parallel()
+
+	tracker := costs.NewTracker(newPricing())
+	client, _ := newClient(tracker, ""+
+		"data: {\"model\":\"o3\",\"choices\":[{\"delta\":{\"content\":\"Hi\"}}],\"usage\":null}\n\n"+
+		"data: {\"model\":\"o3\",\"choices\":[],\"usage\":{\"prompt_tokens\":1000,\"completion_tokens\":500,"+
+		"\"completion_tokens_details\":{\"reasoning_tokens\":250}}}\n\n"+
+		"data: [DONE]\n\n")
+
+	stream, err := client.Chat.CreateStreamingCompletion(context.Background(), "o3", messages(),
+		chat.WithStreamIncludeUsage())
+	require.NoError(t, err)
+
+	for {
+		if _, err := stream.Next(); err != nil {
+			require.ErrorIs(t, err, chat.ErrStreamDone)
+
+			break
+		}
+	}
+
+	require.NoError(t, stream.Close())
+
+	// 1000 * $2 + 250 * $8 + 250 * $16, per million.
+	snap := tracker.Snapshot()
+	assert.Equal(t, 1, snap.Total.Requests)
+	assert.Equal(t, 250, snap.Total.Reasoning)
+	assert.InDelta(t, 0.008, snap.ByModel["o3"].Cost, 1e-12)
+}
+
+func TestTracker_NonStreamBody(t *testing.T) {
+	t.Parallel()
+
+	tracker := costs.NewTracker(newPricing())
+	client := openai.NewClient(
+		openai.WithMiddleware(tracker.Middleware),
+		openai.WithDoer(httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"audio/mpeg"}},
+				Body:       httptesting.NewTestBody(strings.NewReader("ID3")),
+			}, nil
+		})),
+	)
+
+	// Bodies read by the caller that aren't event streams are left as is.
+	rc, err := client.Audio.Speech(context.Background(), "tts-1", "alloy", "Hi")
+	require.NoError(t, err)
+	assert.IsType(t, httptesting.TestBody{}, rc)
+	require.NoError(t, rc.Close())
+}
+
+func TestTracker_Budget(t *testing.T) {
+	t.Parallel()
+
+	tracker := costs.NewTracker(newPricing(),
+		costs.WithUserBudget("alice", 0.001),
+		costs.WithTagBudget("team:search", 1))
+	client, requests := newClient(tracker,
+		`{"model":"gpt-4o","usage":{"prompt_tokens":1000,"completion_tokens":100}}`)
+
+	ctx := costs.WithTags(context.Background(), "team:search")
+
+	_, err := client.Chat.CreateCompletion(ctx, "gpt-4o", messages(), chat.WithUser("alice"))
+	require.NoError(t, err)
+
+	_, err = client.Chat.CreateCompletion(ctx, "gpt-4o", messages(), chat.WithUser("alice"))
+	require.ErrorIs(t, err, costs.ErrBudgetExceeded)
+
+	var budgetErr *costs.BudgetError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, "user", budgetErr.Scope)
+	assert.Equal(t, "alice", budgetErr.Key)
+	assert.InDelta(t, 0.0035, budgetErr.Spent, 1e-12)
+
+	// Other users are not limited.
+	_, err = client.Chat.CreateCompletion(ctx, "gpt-4o", messages(), chat.WithUser("bob"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, *requests)
+
+	tracker = costs.NewTracker(newPricing(), costs.WithBudget(0))
+	client, _ = newClient(tracker, `{}`)
+
+	_, err = client.Chat.CreateCompletion(ctx, "gpt-4o", messages())
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, "total", budgetErr.Scope)
+}
+
+func TestTracker_RecordBatchResult(t *testing.T) {
+	t.Parallel()
+
+	tracker := costs.NewTracker(newPricing())
+
+	var result batch.Result
+	require.NoError(t, json.NewDecoder(strings.NewReader(`{"custom_id":"1","response":{"status_code":200,`+
+		`"body":{"model":"gpt-4o","usage":{"prompt_tokens":1000000,"completion_tokens":0}}}}`)).Decode(&result))
+
+	tracker.RecordBatchResult(&result, "alice", "job:nightly")
+
+	snap := tracker.Snapshot()
+	assert.InDelta(t, 1.25, snap.Total.Cost, 1e-9)
+	assert.InDelta(t, 1.25, snap.ByTag["job:nightly"].Cost, 1e-9)
+}
+
+func TestParseUsage(t *testing.T) {
+	t.Parallel()
+
+	model, tokens, ok := costs.ParseUsage([]byte(`{"type":"response.completed","response":{"model":"o3",` +
+		`"usage":{"input_tokens":10,"input_tokens_details":{"cached_tokens":4},"output_tokens":20,` +
+		`"output_tokens_details":{"reasoning_tokens":5}}}}`))
+	require.True(t, ok)
+	assert.Equal(t, "o3", model)
+	assert.Equal(t, costs.Tokens{Input: 10, CachedInput: 4, Output: 20, Reasoning: 5}, tokens)
+
+	_, _, ok = costs.ParseUsage([]byte(`{"id":"file-1"}`))
+	assert.False(t, ok)
+}
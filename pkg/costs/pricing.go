@@ -0,0 +1,111 @@
+package costs
+
+import (
+	"regexp"
+	"sync"
+)
+
+// A Price is the price of a model's tokens, in dollars per million tokens.
+type Price struct {
+	// Input is the price of uncached input tokens.
+	Input float64
+
+	// CachedInput is the price of cached input tokens. If it is zero, cached
+	// tokens are priced as Input.
+	CachedInput float64
+
+	// Output is the price of output tokens.
+	Output float64
+
+	// Reasoning is the price of reasoning tokens, which are a subset of
+	// output tokens. If it is zero, reasoning tokens are priced as Output.
+	Reasoning float64
+
+	// BatchDiscount is the fraction taken off the price of batch requests
+	// (for example, 0.5 for half price).
+	BatchDiscount float64
+}
+
+// perToken is the number of tokens prices are given per.
+const perToken = 1_000_000
+
+// Cost returns the cost of token usage, in dollars.
+func (p Price) Cost(tokens Tokens, batch bool) float64 {
+	cachedPrice := p.CachedInput
+	if cachedPrice == 0 {
+		cachedPrice = p.Input
+	}
+
+	reasoningPrice := p.Reasoning
+	if reasoningPrice == 0 {
+		reasoningPrice = p.Output
+	}
+
+	cached := min(tokens.CachedInput, tokens.Input)
+	reasoning := min(tokens.Reasoning, tokens.Output)
+
+	cost := (float64(tokens.Input-cached)*p.Input +
+		float64(cached)*cachedPrice +
+		float64(tokens.Output-reasoning)*p.Output +
+		float64(reasoning)*reasoningPrice) / perToken
+
+	if batch {
+		cost *= 1 - p.BatchDiscount
+	}
+
+	return cost
+}
+
+// A Pricing is a table of model prices. It is safe for concurrent use, and
+// may be updated while in use.
+type Pricing struct {
+	mu     sync.RWMutex
+	prices map[string]Price
+}
+
+// NewPricing creates a Pricing with the given model prices.
+//
+// This package has no built-in prices, since they change; set the prices of
+// the models you use.
+func NewPricing(prices map[string]Price) *Pricing {
+	p := &Pricing{prices: make(map[string]Price, len(prices))}
+
+	for model, price := range prices {
+		p.prices[model] = price
+	}
+
+	return p
+}
+
+// Set sets the price of a model.
+func (p *Pricing) Set(model string, price Price) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prices[model] = price
+}
+
+// Lookup returns the price of a model.
+//
+// If a dated snapshot of a model (such as "gpt-4o-2024-08-06") has no price,
+// the price of the model it is a snapshot of ("gpt-4o") is used. Other
+// variants, such as "gpt-4o-mini", must have their own price.
+func (p *Pricing) Lookup(model string) (Price, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if price, ok := p.prices[model]; ok {
+		return price, true
+	}
+
+	if loc := snapshotSuffix.FindStringIndex(model); loc != nil {
+		if price, ok := p.prices[model[:loc[0]]]; ok {
+			return price, true
+		}
+	}
+
+	return Price{}, false
+}
+
+// snapshotSuffix matches the date suffix of a model snapshot.
+var snapshotSuffix = regexp.MustCompile(`-\d{4}-\d{2}-\d{2}$`)
@@ -0,0 +1,84 @@
+package costs
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// A streamReader passes a server-sent event stream through, recording the last
+// usage reported by its events when the stream ends or is closed.
+type streamReader struct {
+	rc     io.ReadCloser
+	record func(model string, tokens Tokens)
+
+	line   []byte
+	model  string
+	tokens *Tokens
+	once   sync.Once
+}
+
+func newStreamReader(rc io.ReadCloser, record func(model string, tokens Tokens)) *streamReader {
+	return &streamReader{rc: rc, record: record}
+}
+
+// Read implements io.Reader.
+func (r *streamReader) Read(p []byte) (int, error) {
+	n, err := r.rc.Read(p)
+	r.scan(p[:n])
+
+	if err == io.EOF {
+		r.finish()
+	}
+
+	return n, err //nolint: wrapcheck // Errors are the stream's.
+}
+
+// Close implements io.Closer.
+func (r *streamReader) Close() error {
+	r.finish()
+
+	return r.rc.Close() //nolint: wrapcheck // Errors are the stream's.
+}
+
+func (r *streamReader) scan(b []byte) {
+	for len(b) > 0 {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			r.line = append(r.line, b...)
+
+			return
+		}
+
+		r.line = append(r.line, b[:i]...)
+		r.parseLine(bytes.TrimSuffix(r.line, []byte("\r")))
+		r.line = r.line[:0]
+		b = b[i+1:]
+	}
+}
+
+func (r *streamReader) parseLine(line []byte) {
+	data, ok := bytes.CutPrefix(line, []byte("data:"))
+	if !ok {
+		return
+	}
+
+	model, tokens, ok := ParseUsage(bytes.TrimSpace(data))
+	if !ok {
+		return
+	}
+
+	if model != "" {
+		r.model = model
+	}
+
+	r.tokens = &tokens
+}
+
+func (r *streamReader) finish() {
+	r.once.Do(func() {
+		if r.tokens != nil {
+			r.record(r.model, *r.tokens)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package embeddings
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -48,6 +49,25 @@ type Usage struct {
 	TotalTokens  int `json:"total_tokens"`
 }
 
+// MarshalRequest returns the JSON body of an embeddings request.
+//
+// It is useful when a request is not sent directly, such as in a batch input
+// file.
+func MarshalRequest(model string, inputs []string, opts ...CreateOpt) ([]byte, error) {
+	req := request{Model: model, Input: inputs}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling embeddings request: %w", err)
+	}
+
+	return b, nil
+}
+
 // Service is a service wrapping an OpenAI-compatible embeddings API.
 type Service service.Service
 
@@ -64,6 +84,21 @@ func (h *Service) Create(
 		opt(&req)
 	}
 
+	ctx, obs := h.Client.StartOperation(ctx, service.Operation{Name: "embeddings", RequestModel: model})
+
+	resp, err := h.create(ctx, req)
+	if err != nil {
+		obs.End(service.OperationResult{}, err)
+
+		return nil, err
+	}
+
+	obs.End(service.OperationResult{ResponseModel: resp.Model, InputTokens: resp.Usage.PromptTokens}, nil)
+
+	return resp, nil
+}
+
+func (h *Service) create(ctx context.Context, req request) (*Response, error) {
 	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/embeddings", req,
 		service.WithAPIKey(req.apiKey))
 	if err != nil {
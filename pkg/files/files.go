@@ -0,0 +1,259 @@
+// Package files provides a files client for the OpenAI API.
+package files
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// File purposes.
+const (
+	PurposeAssistants = "assistants"
+	PurposeBatch      = "batch"
+	PurposeFineTune   = "fine-tune"
+	PurposeVision     = "vision"
+	PurposeUserData   = "user_data"
+	PurposeEvals      = "evals"
+)
+
+// File statuses.
+const (
+	StatusUploaded  = "uploaded"
+	StatusProcessed = "processed"
+	StatusError     = "error"
+)
+
+// A File is a file uploaded to the API.
+type File struct {
+	ID            string  `json:"id"`
+	Object        string  `json:"object"`
+	Bytes         int64   `json:"bytes"`
+	CreatedAt     int64   `json:"created_at"`
+	ExpiresAt     *int64  `json:"expires_at,omitempty"`
+	Filename      string  `json:"filename"`
+	Purpose       string  `json:"purpose"`
+	Status        string  `json:"status"`
+	StatusDetails *string `json:"status_details,omitempty"`
+}
+
+// A ListResponse is a page of files.
+type ListResponse = service.Page[File]
+
+// A DeleteResponse is a response to a request to delete a file.
+type DeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
+type uploadRequest struct {
+	apiKey string
+
+	expiresAfterAnchor  string
+	expiresAfterSeconds int
+}
+
+// UploadOpt is a functional option for configuring a file upload.
+type UploadOpt func(*uploadRequest)
+
+// WithExpiresAfter sets the file's expiration policy, relative to the given
+// anchor (e.g. "created_at").
+func WithExpiresAfter(anchor string, after time.Duration) UploadOpt {
+	return func(r *uploadRequest) {
+		r.expiresAfterAnchor = anchor
+		r.expiresAfterSeconds = int(after.Seconds())
+	}
+}
+
+// WithUploadAPIKey sets the API key for the upload request.
+func WithUploadAPIKey(apiKey string) UploadOpt {
+	return func(r *uploadRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// ListOpt is a functional option for configuring a file list request.
+type ListOpt = service.ListOpt
+
+// WithPurpose only lists files with the given purpose.
+func WithPurpose(purpose string) ListOpt {
+	return service.WithListParam("purpose", purpose)
+}
+
+// WithLimit sets the maximum number of files in a page.
+func WithLimit(limit int) ListOpt {
+	return service.WithLimit(limit)
+}
+
+// WithOrder sets the sort order by creation time ("asc" or "desc").
+func WithOrder(order string) ListOpt {
+	return service.WithOrder(order)
+}
+
+// WithAfter sets the cursor to list files after, which is the LastID of the
+// previous page.
+func WithAfter(after string) ListOpt {
+	return service.WithAfter(after)
+}
+
+// Service is a service wrapping an OpenAI-compatible files API.
+type Service service.Service
+
+// Upload uploads a file.
+//
+// The file is streamed to the API as it is read from r.
+func (h *Service) Upload(
+	ctx context.Context,
+	r io.Reader,
+	filename string,
+	purpose string,
+	opts ...UploadOpt,
+) (*File, error) {
+	req := uploadRequest{}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	form := &service.Form{}
+	form.AddField("purpose", purpose)
+
+	if req.expiresAfterAnchor != "" {
+		form.AddField("expires_after[anchor]", req.expiresAfterAnchor)
+		form.AddField("expires_after[seconds]", strconv.Itoa(req.expiresAfterSeconds))
+	}
+
+	form.AddFile("file", filename, r)
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/files", form,
+		service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating file upload request: %w", err)
+	}
+
+	var resp File
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing file upload request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// List lists a page of files.
+//
+// Use WithAfter with the previous page's LastID to fetch the next page.
+func (h *Service) List(ctx context.Context, opts ...ListOpt) (*ListResponse, error) {
+	resp, err := service.ListPage[File](ctx, &h.Client, "/files", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing files: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAll returns an iterator over every file, following pagination cursors.
+func (h *Service) ListAll(ctx context.Context, opts ...ListOpt) iter.Seq2[File, error] {
+	return service.NewPager[File](&h.Client, "/files", nil, opts...).All(ctx)
+}
+
+// Retrieve retrieves a file by its ID.
+func (h *Service) Retrieve(ctx context.Context, id string) (*File, error) {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodGet, "/files/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating file request: %w", err)
+	}
+
+	var resp File
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing file request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// Content copies the content of a file to w as it is received.
+func (h *Service) Content(ctx context.Context, id string, w io.Writer) error {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodGet,
+		"/files/"+url.PathEscape(id)+"/content", nil)
+	if err != nil {
+		return fmt.Errorf("error creating file content request: %w", err)
+	}
+
+	if _, err := h.Client.Do(httpReq, w); err != nil { //nolint: bodyclose // False positive.
+		return fmt.Errorf("error performing file content request: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a file.
+func (h *Service) Delete(ctx context.Context, id string) (*DeleteResponse, error) {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodDelete, "/files/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating file deletion request: %w", err)
+	}
+
+	var resp DeleteResponse
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing file deletion request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// ErrProcessingFailed is returned by WaitForProcessed when a file's status is
+// StatusError.
+var ErrProcessingFailed = errors.New("file processing failed")
+
+// DefaultPollInterval is the interval WaitForProcessed polls at when it is
+// given an interval that isn't positive.
+const DefaultPollInterval = time.Second
+
+// WaitForProcessed polls a file every interval until its status is
+// StatusProcessed or StatusError, or the context is done. If interval isn't
+// positive, DefaultPollInterval is used.
+//
+// If the status is StatusError, the file is returned along with an error
+// wrapping ErrProcessingFailed.
+func (h *Service) WaitForProcessed(ctx context.Context, id string, interval time.Duration) (*File, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		file, err := h.Retrieve(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch file.Status {
+		case StatusProcessed:
+			return file, nil
+		case StatusError:
+			details := ""
+			if file.StatusDetails != nil {
+				details = *file.StatusDetails
+			}
+
+			return file, fmt.Errorf("%w: %s", ErrProcessingFailed, details)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error waiting for file to be processed: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package files_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResponse(body string) *http.Response {
+	r := &http.Response{}
+	r.StatusCode = http.StatusOK
+	r.Body = httptesting.NewTestBody(strings.NewReader(body))
+
+	return r
+}
+
+func TestHTTPClient_UploadFile(t *testing.T) {
+	t.Parallel()
+
+	fields := map[string]string{}
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, err
+		}
+
+		mr := multipart.NewReader(req.Body, params["boundary"])
+
+		for {
+			part, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			b, err := io.ReadAll(part)
+			if err != nil {
+				return nil, err
+			}
+
+			fields[part.FormName()] = string(b)
+		}
+
+		return newResponse(`{"id": "file-abc", "filename": "train.jsonl", "purpose": "fine-tune", "status": "uploaded"}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*files.Service)(svc)
+
+	file, err := c.Upload(
+		context.Background(),
+		strings.NewReader(`{"messages": []}`),
+		"train.jsonl",
+		files.PurposeFineTune,
+		files.WithExpiresAfter("created_at", time.Hour),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "file-abc", file.ID)
+	assert.Equal(t, map[string]string{
+		"purpose":                "fine-tune",
+		"expires_after[anchor]":  "created_at",
+		"expires_after[seconds]": "3600",
+		"file":                   `{"messages": []}`,
+	}, fields)
+}
+
+func TestHTTPClient_ListFiles(t *testing.T) {
+	t.Parallel()
+
+	var query string
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		query = req.URL.RawQuery
+
+		return newResponse(`{"object": "list", "data": [{"id": "file-abc"}], "has_more": true, "last_id": "file-abc"}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*files.Service)(svc)
+
+	resp, err := c.List(context.Background(), files.WithPurpose(files.PurposeBatch), files.WithAfter("file-123"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "after=file-123&purpose=batch", query)
+	assert.True(t, resp.HasMore)
+	assert.Equal(t, "file-abc", resp.LastID)
+}
+
+func TestHTTPClient_FileContent(t *testing.T) {
+	t.Parallel()
+
+	doer := httptesting.NewTestDoer(newResponse("line 1\nline 2\n"), nil)
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", &doer)
+	c := (*files.Service)(svc)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.Content(context.Background(), "file-abc", &buf))
+	assert.Equal(t, "line 1\nline 2\n", buf.String())
+}
+
+func TestHTTPClient_WaitForProcessed(t *testing.T) {
+	t.Parallel()
+
+	statuses := []string{files.StatusUploaded, files.StatusUploaded, files.StatusError}
+
+	doer := httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+		status := statuses[0]
+		statuses = statuses[1:]
+
+		return newResponse(`{"id": "file-abc", "status": "` + status + `", "status_details": "bad line"}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*files.Service)(svc)
+
+	file, err := c.WaitForProcessed(context.Background(), "file-abc", time.Millisecond)
+	require.ErrorIs(t, err, files.ErrProcessingFailed)
+	assert.Equal(t, files.StatusError, file.Status)
+	assert.Empty(t, statuses)
+}
+
+func TestHTTPClient_WaitForProcessedZeroInterval(t *testing.T) {
+	t.Parallel()
+
+	doer := httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+		return newResponse(`{"id": "file-abc", "status": "processed"}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*files.Service)(svc)
+
+	file, err := c.WaitForProcessed(context.Background(), "file-abc", 0)
+	require.NoError(t, err)
+	assert.Equal(t, files.StatusProcessed, file.Status)
+}
+
+func TestHTTPClient_ListAllFiles(t *testing.T) {
+	t.Parallel()
+
+	var queries []string
+
+	pages := []string{
+		`{"data": [{"id": "file-1"}, {"id": "file-2"}], "first_id": "file-1", "last_id": "file-2", "has_more": true}`,
+		`{"data": [{"id": "file-3"}], "first_id": "file-3", "last_id": "file-3", "has_more": false}`,
+	}
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		queries = append(queries, req.URL.RawQuery)
+		page := pages[0]
+		pages = pages[1:]
+
+		return newResponse(page), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*files.Service)(svc)
+
+	var ids []string
+
+	for file, err := range c.ListAll(context.Background(), files.WithLimit(2)) {
+		require.NoError(t, err)
+
+		ids = append(ids, file.ID)
+	}
+
+	assert.Equal(t, []string{"file-1", "file-2", "file-3"}, ids)
+	assert.Equal(t, []string{"limit=2", "after=file-2&limit=2"}, queries)
+}
+
+func TestHTTPClient_ListAllFilesCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	doer := httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+		return newResponse(`{"data": [{"id": "file-1"}], "last_id": "file-1", "has_more": true}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*files.Service)(svc)
+
+	var (
+		ids  []string
+		last error
+	)
+
+	for file, err := range c.ListAll(ctx) {
+		if err != nil {
+			last = err
+
+			break
+		}
+
+		ids = append(ids, file.ID)
+		cancel()
+	}
+
+	assert.Equal(t, []string{"file-1"}, ids)
+	require.ErrorIs(t, last, context.Canceled)
+}
@@ -0,0 +1,117 @@
+package finetuning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTailDone is returned by EventTailer.Next once the job has finished and
+// all of its events have been returned.
+var ErrTailDone = errors.New("fine-tuning job is done")
+
+// An EventTailer follows the events of a fine-tuning job in chronological
+// order until the job finishes.
+type EventTailer struct {
+	svc      *Service
+	jobID    string
+	interval time.Duration
+	lastID   string
+	pending  []Event
+	job      *Job
+	polled   bool
+}
+
+// DefaultPollInterval is the interval an EventTailer polls at when it is given
+// an interval that isn't positive.
+const DefaultPollInterval = 10 * time.Second
+
+// TailEvents returns an EventTailer that polls a job's events every interval.
+// If interval isn't positive, DefaultPollInterval is used.
+//
+// Events that were emitted before TailEvents was called are included.
+func (h *Service) TailEvents(jobID string, interval time.Duration) *EventTailer {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	return &EventTailer{svc: h, jobID: jobID, interval: interval}
+}
+
+// Job returns the job as of the most recent poll, or nil before the first
+// poll.
+func (t *EventTailer) Job() *Job {
+	return t.job
+}
+
+// Next returns the next event, waiting for one if necessary.
+//
+// Once the job is done and all of its events have been returned, it returns
+// ErrTailDone. The final state of the job is then available from Job.
+func (t *EventTailer) Next(ctx context.Context) (*Event, error) {
+	for {
+		if len(t.pending) > 0 {
+			evt := t.pending[0]
+			t.pending = t.pending[1:]
+
+			return &evt, nil
+		}
+
+		if t.job != nil && t.job.Done() {
+			return nil, ErrTailDone
+		}
+
+		if t.polled {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("error waiting for fine-tuning events: %w", ctx.Err())
+			case <-time.After(t.interval):
+			}
+		}
+
+		if err := t.poll(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// poll fetches the job, then any events newer than the last one returned.
+//
+// The job is fetched first so that events emitted before it finished are
+// never missed.
+func (t *EventTailer) poll(ctx context.Context) error {
+	job, err := t.svc.Retrieve(ctx, t.jobID)
+	if err != nil {
+		return err
+	}
+
+	// Events are listed newest first, so page back until the last event that
+	// was already returned.
+	var events []Event
+
+	for evt, err := range t.svc.ListAllEvents(ctx, t.jobID) {
+		if err != nil {
+			return err
+		}
+
+		if evt.ID == t.lastID {
+			break
+		}
+
+		events = append(events, evt)
+	}
+
+	for i := len(events) - 1; i >= 0; i-- {
+		t.pending = append(t.pending, events[i])
+	}
+
+	if len(events) > 0 {
+		t.lastID = events[0].ID
+	}
+
+	t.job = job
+	t.polled = true
+
+	return nil
+}
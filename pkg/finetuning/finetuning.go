@@ -0,0 +1,407 @@
+// Package finetuning provides a fine-tuning client for the OpenAI API.
+package finetuning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// Fine-tuning methods.
+const (
+	MethodSupervised    = "supervised"
+	MethodDPO           = "dpo"
+	MethodReinforcement = "reinforcement"
+)
+
+// Job statuses.
+const (
+	StatusValidatingFiles = "validating_files"
+	StatusQueued          = "queued"
+	StatusRunning         = "running"
+	StatusPaused          = "paused"
+	StatusSucceeded       = "succeeded"
+	StatusFailed          = "failed"
+	StatusCancelled       = "cancelled"
+)
+
+// A Job is a fine-tuning job.
+type Job struct {
+	ID              string            `json:"id"`
+	Object          string            `json:"object"`
+	CreatedAt       int64             `json:"created_at"`
+	Error           *JobError         `json:"error,omitempty"`
+	FineTunedModel  *string           `json:"fine_tuned_model,omitempty"`
+	FinishedAt      *int64            `json:"finished_at,omitempty"`
+	Hyperparameters *Hyperparameters  `json:"hyperparameters,omitempty"`
+	Model           string            `json:"model"`
+	OrganizationID  string            `json:"organization_id"`
+	ResultFiles     []string          `json:"result_files"`
+	Status          string            `json:"status"`
+	TrainedTokens   *int              `json:"trained_tokens,omitempty"`
+	TrainingFile    string            `json:"training_file"`
+	ValidationFile  *string           `json:"validation_file,omitempty"`
+	Seed            int               `json:"seed"`
+	EstimatedFinish *int64            `json:"estimated_finish,omitempty"`
+	Method          *Method           `json:"method,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+}
+
+// Done reports whether the job is in a terminal state.
+func (j *Job) Done() bool {
+	switch j.Status {
+	case StatusSucceeded, StatusFailed, StatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// A JobError describes why a fine-tuning job failed.
+type JobError struct {
+	Code    string  `json:"code"`
+	Message string  `json:"message"`
+	Param   *string `json:"param,omitempty"`
+}
+
+// A Method is the method used to fine-tune a model. Only the field matching
+// Type is set.
+type Method struct {
+	Type          string               `json:"type"`
+	Supervised    *SupervisedMethod    `json:"supervised,omitempty"`
+	DPO           *DPOMethod           `json:"dpo,omitempty"`
+	Reinforcement *ReinforcementMethod `json:"reinforcement,omitempty"`
+}
+
+// A SupervisedMethod configures supervised fine-tuning.
+type SupervisedMethod struct {
+	Hyperparameters Hyperparameters `json:"hyperparameters"`
+}
+
+// A DPOMethod configures direct preference optimization fine-tuning.
+type DPOMethod struct {
+	Hyperparameters Hyperparameters `json:"hyperparameters"`
+}
+
+// A ReinforcementMethod configures reinforcement fine-tuning.
+//
+// The grader is encoded as-is; see the API reference for the grader types.
+type ReinforcementMethod struct {
+	Grader          any             `json:"grader"`
+	Hyperparameters Hyperparameters `json:"hyperparameters"`
+}
+
+// Hyperparameters configure a fine-tuning method.
+//
+// Nil fields are omitted, which lets the API choose a value ("auto"). Fields
+// the API reports as "auto" are decoded as nil. Not every field applies to
+// every method.
+type Hyperparameters struct {
+	BatchSize              *int     `json:"batch_size,omitempty"`
+	LearningRateMultiplier *float64 `json:"learning_rate_multiplier,omitempty"`
+	NEpochs                *int     `json:"n_epochs,omitempty"`
+
+	// Beta is only used for DPO.
+	Beta *float64 `json:"beta,omitempty"`
+
+	// These are only used for reinforcement fine-tuning.
+	ReasoningEffort   *string  `json:"reasoning_effort,omitempty"`
+	ComputeMultiplier *float64 `json:"compute_multiplier,omitempty"`
+	EvalInterval      *int     `json:"eval_interval,omitempty"`
+	EvalSamples       *int     `json:"eval_samples,omitempty"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (h *Hyperparameters) UnmarshalJSON(b []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return fmt.Errorf("error unmarshaling hyperparameters: %w", err)
+	}
+
+	for name, value := range fields {
+		if string(value) == `"auto"` {
+			delete(fields, name)
+		}
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("error unmarshaling hyperparameters: %w", err)
+	}
+
+	type plain Hyperparameters
+	if err := json.Unmarshal(b, (*plain)(h)); err != nil {
+		return fmt.Errorf("error unmarshaling hyperparameters: %w", err)
+	}
+
+	return nil
+}
+
+// A ListResponse is a page of fine-tuning jobs.
+type ListResponse = service.Page[Job]
+
+// An Event is a fine-tuning job event.
+type Event struct {
+	ID        string          `json:"id"`
+	Object    string          `json:"object"`
+	CreatedAt int64           `json:"created_at"`
+	Level     string          `json:"level"`
+	Message   string          `json:"message"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// An EventListResponse is a page of fine-tuning job events, newest first.
+type EventListResponse = service.Page[Event]
+
+// A Checkpoint is a model checkpoint saved during a fine-tuning job.
+type Checkpoint struct {
+	ID                       string            `json:"id"`
+	Object                   string            `json:"object"`
+	CreatedAt                int64             `json:"created_at"`
+	FineTunedModelCheckpoint string            `json:"fine_tuned_model_checkpoint"`
+	StepNumber               int               `json:"step_number"`
+	Metrics                  CheckpointMetrics `json:"metrics"`
+	FineTuningJobID          string            `json:"fine_tuning_job_id"`
+}
+
+// CheckpointMetrics are the training metrics at a checkpoint.
+type CheckpointMetrics struct {
+	Step                       float64 `json:"step"`
+	TrainLoss                  float64 `json:"train_loss"`
+	TrainMeanTokenAccuracy     float64 `json:"train_mean_token_accuracy"`
+	ValidLoss                  float64 `json:"valid_loss"`
+	ValidMeanTokenAccuracy     float64 `json:"valid_mean_token_accuracy"`
+	FullValidLoss              float64 `json:"full_valid_loss"`
+	FullValidMeanTokenAccuracy float64 `json:"full_valid_mean_token_accuracy"`
+}
+
+// A CheckpointListResponse is a page of fine-tuning job checkpoints.
+type CheckpointListResponse = service.Page[Checkpoint]
+
+type createRequest struct {
+	apiKey string
+
+	Model          string            `json:"model"`
+	TrainingFile   string            `json:"training_file"`
+	ValidationFile *string           `json:"validation_file,omitempty"`
+	Suffix         *string           `json:"suffix,omitempty"`
+	Seed           *int              `json:"seed,omitempty"`
+	Method         *Method           `json:"method,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+// CreateOpt is a functional option for configuring a fine-tuning job creation
+// request.
+type CreateOpt func(*createRequest)
+
+// WithValidationFile sets the ID of an uploaded validation file.
+func WithValidationFile(fileID string) CreateOpt {
+	return func(r *createRequest) {
+		r.ValidationFile = &fileID
+	}
+}
+
+// WithSuffix sets a suffix of up to 64 characters for the fine-tuned model
+// name.
+func WithSuffix(suffix string) CreateOpt {
+	return func(r *createRequest) {
+		r.Suffix = &suffix
+	}
+}
+
+// WithSeed sets the seed for the job, for reproducibility.
+func WithSeed(seed int) CreateOpt {
+	return func(r *createRequest) {
+		r.Seed = &seed
+	}
+}
+
+// WithMetadata sets the metadata for the job.
+func WithMetadata(metadata map[string]string) CreateOpt {
+	return func(r *createRequest) {
+		r.Metadata = metadata
+	}
+}
+
+// WithSupervised uses supervised fine-tuning with the given hyperparameters.
+func WithSupervised(hyperparameters Hyperparameters) CreateOpt {
+	return func(r *createRequest) {
+		r.Method = &Method{
+			Type:       MethodSupervised,
+			Supervised: &SupervisedMethod{Hyperparameters: hyperparameters},
+		}
+	}
+}
+
+// WithDPO uses direct preference optimization with the given
+// hyperparameters.
+func WithDPO(hyperparameters Hyperparameters) CreateOpt {
+	return func(r *createRequest) {
+		r.Method = &Method{
+			Type: MethodDPO,
+			DPO:  &DPOMethod{Hyperparameters: hyperparameters},
+		}
+	}
+}
+
+// WithReinforcement uses reinforcement fine-tuning with the given grader and
+// hyperparameters.
+func WithReinforcement(grader any, hyperparameters Hyperparameters) CreateOpt {
+	return func(r *createRequest) {
+		r.Method = &Method{
+			Type:          MethodReinforcement,
+			Reinforcement: &ReinforcementMethod{Grader: grader, Hyperparameters: hyperparameters},
+		}
+	}
+}
+
+// WithAPIKey sets the API key for the fine-tuning job creation request.
+func WithAPIKey(apiKey string) CreateOpt {
+	return func(r *createRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// ListOpt is a functional option for configuring a list request for jobs,
+// events, or checkpoints.
+type ListOpt = service.ListOpt
+
+// WithLimit sets the maximum number of items in a page.
+func WithLimit(limit int) ListOpt {
+	return service.WithLimit(limit)
+}
+
+// WithAfter sets the cursor to list items after, which is the ID of the last
+// item in the previous page.
+func WithAfter(after string) ListOpt {
+	return service.WithAfter(after)
+}
+
+// Service is a service wrapping an OpenAI-compatible fine-tuning API.
+type Service service.Service
+
+// Create creates a fine-tuning job from an uploaded training file.
+func (h *Service) Create(
+	ctx context.Context,
+	model string,
+	trainingFileID string,
+	opts ...CreateOpt,
+) (*Job, error) {
+	req := createRequest{Model: model, TrainingFile: trainingFileID}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/fine_tuning/jobs", req,
+		service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating fine-tuning request: %w", err)
+	}
+
+	var resp Job
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing fine-tuning request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// List lists a page of fine-tuning jobs.
+func (h *Service) List(ctx context.Context, opts ...ListOpt) (*ListResponse, error) {
+	resp, err := service.ListPage[Job](ctx, &h.Client, "/fine_tuning/jobs", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing fine-tuning jobs: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAll returns an iterator over every fine-tuning job, following
+// pagination cursors.
+func (h *Service) ListAll(ctx context.Context, opts ...ListOpt) iter.Seq2[Job, error] {
+	return service.NewPager(&h.Client, "/fine_tuning/jobs", jobID, opts...).All(ctx)
+}
+
+// Retrieve retrieves a fine-tuning job by its ID.
+func (h *Service) Retrieve(ctx context.Context, id string) (*Job, error) {
+	return h.job(ctx, http.MethodGet, id, "")
+}
+
+// Cancel cancels a fine-tuning job.
+func (h *Service) Cancel(ctx context.Context, id string) (*Job, error) {
+	return h.job(ctx, http.MethodPost, id, "/cancel")
+}
+
+// Pause pauses a running fine-tuning job.
+func (h *Service) Pause(ctx context.Context, id string) (*Job, error) {
+	return h.job(ctx, http.MethodPost, id, "/pause")
+}
+
+// Resume resumes a paused fine-tuning job.
+func (h *Service) Resume(ctx context.Context, id string) (*Job, error) {
+	return h.job(ctx, http.MethodPost, id, "/resume")
+}
+
+// ListEvents lists a page of events for a fine-tuning job, newest first.
+func (h *Service) ListEvents(ctx context.Context, id string, opts ...ListOpt) (*EventListResponse, error) {
+	resp, err := service.ListPage[Event](ctx, &h.Client, "/fine_tuning/jobs/"+url.PathEscape(id)+"/events", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing fine-tuning events: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAllEvents returns an iterator over every event for a fine-tuning job,
+// newest first, following pagination cursors.
+func (h *Service) ListAllEvents(ctx context.Context, id string, opts ...ListOpt) iter.Seq2[Event, error] {
+	return service.NewPager(&h.Client, "/fine_tuning/jobs/"+url.PathEscape(id)+"/events", eventID, opts...).All(ctx)
+}
+
+// ListCheckpoints lists a page of checkpoints for a fine-tuning job.
+func (h *Service) ListCheckpoints(
+	ctx context.Context,
+	id string,
+	opts ...ListOpt,
+) (*CheckpointListResponse, error) {
+	resp, err := service.ListPage[Checkpoint](ctx, &h.Client,
+		"/fine_tuning/jobs/"+url.PathEscape(id)+"/checkpoints", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing fine-tuning checkpoints: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAllCheckpoints returns an iterator over every checkpoint for a
+// fine-tuning job, following pagination cursors.
+func (h *Service) ListAllCheckpoints(ctx context.Context, id string, opts ...ListOpt) iter.Seq2[Checkpoint, error] {
+	return service.NewPager[Checkpoint](&h.Client, "/fine_tuning/jobs/"+url.PathEscape(id)+"/checkpoints",
+		nil, opts...).All(ctx)
+}
+
+func (h *Service) job(ctx context.Context, method, id, action string) (*Job, error) {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, method,
+		"/fine_tuning/jobs/"+url.PathEscape(id)+action, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating fine-tuning request: %w", err)
+	}
+
+	var resp Job
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing fine-tuning request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+func jobID(j Job) string { return j.ID }
+
+func eventID(e Event) string { return e.ID }
@@ -0,0 +1,178 @@
+package finetuning_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/finetuning"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResponse(body string) *http.Response {
+	r := &http.Response{}
+	r.StatusCode = http.StatusOK
+	r.Body = httptesting.NewTestBody(strings.NewReader(body))
+
+	return r
+}
+
+func TestHTTPClient_CreateJob(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		return newResponse(`{
+			"id": "ftjob-abc",
+			"status": "queued",
+			"method": {"type": "dpo", "dpo": {"hyperparameters": {"n_epochs": "auto", "beta": 0.1}}}
+		}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*finetuning.Service)(svc)
+
+	beta := 0.1
+	job, err := c.Create(
+		context.Background(),
+		"gpt-4o-mini",
+		"file-abc",
+		finetuning.WithDPO(finetuning.Hyperparameters{Beta: &beta}),
+		finetuning.WithSuffix("support"),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"model":         "gpt-4o-mini",
+		"training_file": "file-abc",
+		"suffix":        "support",
+		"method": map[string]any{
+			"type": "dpo",
+			"dpo":  map[string]any{"hyperparameters": map[string]any{"beta": 0.1}},
+		},
+	}, body)
+	assert.Nil(t, job.Method.DPO.Hyperparameters.NEpochs)
+	assert.Equal(t, &beta, job.Method.DPO.Hyperparameters.Beta)
+}
+
+func TestEventTailer(t *testing.T) {
+	t.Parallel()
+
+	// Each poll fetches the job, then the events (newest first).
+	responses := []string{
+		`{"id": "ftjob-abc", "status": "running"}`,
+		`{"data": [{"id": "ev-2", "message": "step 1"}, {"id": "ev-1", "message": "created"}], "has_more": false}`,
+		`{"id": "ftjob-abc", "status": "succeeded"}`,
+		`{"data": [{"id": "ev-3", "message": "done"}, {"id": "ev-2", "message": "step 1"}], "has_more": true}`,
+	}
+
+	doer := httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+		body := responses[0]
+		responses = responses[1:]
+
+		return newResponse(body), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*finetuning.Service)(svc)
+
+	tailer := c.TailEvents("ftjob-abc", time.Millisecond)
+
+	var messages []string
+
+	for {
+		evt, err := tailer.Next(context.Background())
+		if errors.Is(err, finetuning.ErrTailDone) {
+			break
+		}
+
+		require.NoError(t, err)
+
+		messages = append(messages, evt.Message)
+	}
+
+	assert.Equal(t, []string{"created", "step 1", "done"}, messages)
+	assert.Equal(t, finetuning.StatusSucceeded, tailer.Job().Status)
+}
+
+func TestEventTailerZeroInterval(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+
+	doer := httptesting.DoerFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+
+		if strings.HasSuffix(r.URL.Path, "/events") {
+			return newResponse(`{"data": [], "has_more": false}`), nil
+		}
+
+		return newResponse(`{"id": "ftjob-abc", "status": "running"}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*finetuning.Service)(svc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The tailer waits DefaultPollInterval after the first poll, rather than
+	// polling again immediately.
+	_, err := c.TailEvents("ftjob-abc", 0).Next(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, requests)
+}
+
+func TestValidateTrainingFile(t *testing.T) {
+	t.Parallel()
+
+	valid := `{"messages": [{"role": "system", "content": "Be brief."}, {"role": "user", "content": "Hi"}, {"role": "assistant", "content": "Hello"}]}`
+	lines := []string{
+		`{"messages": [{"role": "user", "content": "Weather?"}, {"role": "assistant", "content": null, "function_call": {"name": "get_weather", "arguments": {"city": "Paris"}}}]}`,
+		`{"messages": [{"role": "robot", "content": "Hi"}]}`,
+		`not json`,
+	}
+
+	for i := 0; i < 8; i++ {
+		lines = append(lines, valid)
+	}
+
+	report, err := finetuning.ValidateTrainingFile(strings.NewReader(strings.Join(lines, "\n")))
+	require.NoError(t, err)
+
+	assert.False(t, report.Valid())
+	assert.Equal(t, 11, report.Examples)
+
+	messages := make([]string, 0, len(report.Errors))
+	for _, e := range report.Errors {
+		messages = append(messages, e.Error())
+	}
+
+	assert.Equal(t, []string{
+		"line 1: message 1 has function call arguments that are not a JSON-encoded string",
+		`line 2: message 0 has invalid role "robot"`,
+		"line 2: example has no assistant message",
+		"line 3: invalid JSON: invalid character 'o' in literal null (expecting 'u')",
+	}, messages)
+
+	report, err = finetuning.ValidateTrainingFile(strings.NewReader(valid), finetuning.WithMaxTokens(10))
+	require.NoError(t, err)
+
+	assert.Equal(t, []finetuning.ValidationError{
+		{Line: 1, Message: "example has about 18 tokens, more than the limit of 10"},
+		{Message: "training file has 1 examples, at least 10 are required"},
+	}, report.Errors)
+}
@@ -0,0 +1,244 @@
+package finetuning
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jclem/openai-go/pkg/chat"
+)
+
+const (
+	// MinExamples is the minimum number of examples in a training file.
+	MinExamples = 10
+
+	// DefaultMaxTokens is the default maximum number of tokens in a single
+	// training example.
+	DefaultMaxTokens = 65536
+
+	// messageOverheadTokens approximates the tokens used by a message's role
+	// and delimiters.
+	messageOverheadTokens = 4
+)
+
+var validRoles = map[string]bool{
+	"system":    true,
+	"user":      true,
+	"assistant": true,
+	"function":  true,
+}
+
+type trainingExample struct {
+	Messages  []chat.Message            `json:"messages"`
+	Functions []chat.FunctionDefinition `json:"functions,omitempty"`
+}
+
+// A ValidationError is a problem with a training file.
+//
+// Line is the 1-based line of the offending example, or 0 for problems with
+// the file as a whole.
+type ValidationError struct {
+	Line    int
+	Message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// A ValidationReport summarizes a training file.
+type ValidationReport struct {
+	// Examples is the number of examples in the file.
+	Examples int
+
+	// Tokens is the estimated total number of tokens in the file.
+	Tokens int
+
+	// Errors are the problems found in the file.
+	Errors []ValidationError
+}
+
+// Valid reports whether no problems were found.
+func (r *ValidationReport) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+type validateRequest struct {
+	countTokens func(string) int
+	maxTokens   int
+}
+
+// ValidateOpt is a functional option for configuring training file
+// validation.
+type ValidateOpt func(*validateRequest)
+
+// WithTokenCounter sets the function used to count tokens in text.
+//
+// By default, tokens are estimated as one per four bytes. Pass a real
+// tokenizer for exact counts.
+func WithTokenCounter(countTokens func(string) int) ValidateOpt {
+	return func(r *validateRequest) {
+		r.countTokens = countTokens
+	}
+}
+
+// WithMaxTokens sets the maximum number of tokens in a single example. The
+// default is DefaultMaxTokens.
+func WithMaxTokens(maxTokens int) ValidateOpt {
+	return func(r *validateRequest) {
+		r.maxTokens = maxTokens
+	}
+}
+
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4 //revive:disable-line:add-constant
+}
+
+// ValidateTrainingFile checks a JSONL file of chat conversations in the
+// supervised fine-tuning format before it is uploaded.
+//
+// It checks that each example has valid roles and content, that function
+// calls are well-formed, and that examples fit within the token limit.
+// Problems are collected in the report; an error is only returned if r cannot
+// be read.
+func ValidateTrainingFile(r io.Reader, opts ...ValidateOpt) (*ValidationReport, error) {
+	req := validateRequest{countTokens: estimateTokens, maxTokens: DefaultMaxTokens}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	report := ValidationReport{}
+	br := bufio.NewReader(r)
+
+	for line := 1; ; line++ {
+		b, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error reading training file: %w", err)
+		}
+
+		if b = bytes.TrimSpace(b); len(b) > 0 {
+			report.Examples++
+
+			tokens, problems := validateExample(b, &req)
+			report.Tokens += tokens
+
+			for _, problem := range problems {
+				report.Errors = append(report.Errors, ValidationError{Line: line, Message: problem})
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+	}
+
+	if report.Examples < MinExamples {
+		report.Errors = append(report.Errors, ValidationError{
+			Message: fmt.Sprintf("training file has %d examples, at least %d are required", report.Examples, MinExamples),
+		})
+	}
+
+	return &report, nil
+}
+
+func validateExample(b []byte, req *validateRequest) (int, []string) {
+	var example trainingExample
+	if err := json.Unmarshal(b, &example); err != nil {
+		return 0, []string{fmt.Sprintf("invalid JSON: %s", err)}
+	}
+
+	if len(example.Messages) == 0 {
+		return 0, []string{"example has no messages"}
+	}
+
+	var (
+		problems     []string
+		tokens       int
+		hasAssistant bool
+	)
+
+	for i, msg := range example.Messages {
+		tokens += messageOverheadTokens
+
+		if !validRoles[msg.Role] {
+			problems = append(problems, fmt.Sprintf("message %d has invalid role %q", i, msg.Role))
+		}
+
+		if msg.Role == "assistant" {
+			hasAssistant = true
+		}
+
+		if msg.Content != nil {
+			tokens += req.countTokens(*msg.Content)
+		}
+
+		if msg.Name != nil {
+			tokens += req.countTokens(*msg.Name)
+		} else if msg.Role == "function" {
+			problems = append(problems, fmt.Sprintf("message %d is a function message without a name", i))
+		}
+
+		if msg.FunctionCall == nil {
+			if msg.Content == nil {
+				problems = append(problems, fmt.Sprintf("message %d has no content", i))
+			}
+
+			continue
+		}
+
+		if msg.Role != "assistant" {
+			problems = append(problems, fmt.Sprintf("message %d has a function call but is not an assistant message", i))
+		}
+
+		args, problem := validateFunctionCall(msg.FunctionCall)
+		if problem != "" {
+			problems = append(problems, fmt.Sprintf("message %d %s", i, problem))
+		}
+
+		tokens += req.countTokens(msg.FunctionCall.Name) + req.countTokens(args)
+	}
+
+	for _, fn := range example.Functions {
+		b, _ := json.Marshal(fn) //nolint: errchkjson // Decoded from JSON, so it encodes.
+		tokens += req.countTokens(string(b))
+	}
+
+	if !hasAssistant {
+		problems = append(problems, "example has no assistant message")
+	}
+
+	if tokens > req.maxTokens {
+		problems = append(problems, fmt.Sprintf("example has about %d tokens, more than the limit of %d", tokens, req.maxTokens))
+	}
+
+	return tokens, problems
+}
+
+// validateFunctionCall checks that a function call has a name, and that its
+// arguments are a JSON-encoded object. It returns the decoded arguments.
+func validateFunctionCall(call *chat.FunctionCall) (string, string) {
+	if call.Name == "" {
+		return "", "has a function call without a name"
+	}
+
+	var args string
+	if err := json.Unmarshal(call.Arguments, &args); err != nil {
+		return "", "has function call arguments that are not a JSON-encoded string"
+	}
+
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(args), &obj); err != nil {
+		return args, "has function call arguments that are not a JSON object"
+	}
+
+	return args, ""
+}
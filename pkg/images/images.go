@@ -0,0 +1,506 @@
+// Package images provides an images client for the OpenAI API.
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/sseparser"
+)
+
+// A File is an image file to upload.
+type File struct {
+	Name   string
+	Reader io.Reader
+}
+
+// NewFile creates a new File. The name's extension is used to infer the
+// file's content type, so it should match the image format (e.g. "image.png").
+func NewFile(name string, r io.Reader) File {
+	return File{Name: name, Reader: r}
+}
+
+type request struct {
+	apiKey string
+	mask   *File
+
+	Model             string  `json:"model,omitempty"`
+	Prompt            string  `json:"prompt,omitempty"`
+	N                 *int    `json:"n,omitempty"`
+	Size              *string `json:"size,omitempty"`
+	Quality           *string `json:"quality,omitempty"`
+	Style             *string `json:"style,omitempty"`
+	Background        *string `json:"background,omitempty"`
+	OutputFormat      *string `json:"output_format,omitempty"`
+	OutputCompression *int    `json:"output_compression,omitempty"`
+	Moderation        *string `json:"moderation,omitempty"`
+	InputFidelity     *string `json:"input_fidelity,omitempty"`
+	ResponseFormat    *string `json:"response_format,omitempty"`
+	Stream            *bool   `json:"stream,omitempty"`
+	PartialImages     *int    `json:"partial_images,omitempty"`
+	User              *string `json:"user,omitempty"`
+}
+
+func (r *request) addFormFields(form *service.Form) {
+	addField := func(name string, value *string) {
+		if value != nil {
+			form.AddField(name, *value)
+		}
+	}
+
+	addInt := func(name string, value *int) {
+		if value != nil {
+			form.AddField(name, strconv.Itoa(*value))
+		}
+	}
+
+	if r.Model != "" {
+		form.AddField("model", r.Model)
+	}
+
+	if r.Prompt != "" {
+		form.AddField("prompt", r.Prompt)
+	}
+
+	addInt("n", r.N)
+	addField("size", r.Size)
+	addField("quality", r.Quality)
+	addField("style", r.Style)
+	addField("background", r.Background)
+	addField("output_format", r.OutputFormat)
+	addInt("output_compression", r.OutputCompression)
+	addField("moderation", r.Moderation)
+	addField("input_fidelity", r.InputFidelity)
+	addField("response_format", r.ResponseFormat)
+	addInt("partial_images", r.PartialImages)
+	addField("user", r.User)
+
+	if r.Stream != nil {
+		form.AddField("stream", strconv.FormatBool(*r.Stream))
+	}
+}
+
+// CreateOpt is a functional option for configuring an image request.
+//
+// Not every option is supported by every endpoint or model. Unsupported
+// options are sent as-is, and the API will reject them.
+type CreateOpt func(*request)
+
+// WithN sets the number of images to create.
+func WithN(n int) CreateOpt {
+	return func(r *request) {
+		r.N = &n
+	}
+}
+
+// WithSize sets the size of the created images (e.g. "1024x1024" or "auto").
+func WithSize(size string) CreateOpt {
+	return func(r *request) {
+		r.Size = &size
+	}
+}
+
+// WithQuality sets the quality of the created images (e.g. "high" or "hd").
+func WithQuality(quality string) CreateOpt {
+	return func(r *request) {
+		r.Quality = &quality
+	}
+}
+
+// WithStyle sets the style of the created images ("vivid" or "natural").
+func WithStyle(style string) CreateOpt {
+	return func(r *request) {
+		r.Style = &style
+	}
+}
+
+// WithBackground sets the background of the created images ("transparent",
+// "opaque", or "auto").
+func WithBackground(background string) CreateOpt {
+	return func(r *request) {
+		r.Background = &background
+	}
+}
+
+// WithOutputFormat sets the output format of the created images ("png",
+// "jpeg", or "webp").
+func WithOutputFormat(format string) CreateOpt {
+	return func(r *request) {
+		r.OutputFormat = &format
+	}
+}
+
+// WithOutputCompression sets the compression level (0-100) of created jpeg
+// and webp images.
+func WithOutputCompression(compression int) CreateOpt {
+	return func(r *request) {
+		r.OutputCompression = &compression
+	}
+}
+
+// WithModeration sets the content moderation level ("low" or "auto").
+func WithModeration(moderation string) CreateOpt {
+	return func(r *request) {
+		r.Moderation = &moderation
+	}
+}
+
+// WithInputFidelity sets how closely edits match the input images ("high" or
+// "low").
+func WithInputFidelity(fidelity string) CreateOpt {
+	return func(r *request) {
+		r.InputFidelity = &fidelity
+	}
+}
+
+// WithResponseFormat sets the response format ("url" or "b64_json").
+func WithResponseFormat(format string) CreateOpt {
+	return func(r *request) {
+		r.ResponseFormat = &format
+	}
+}
+
+// WithPartialImages sets the number of partial images to stream.
+func WithPartialImages(n int) CreateOpt {
+	return func(r *request) {
+		r.PartialImages = &n
+	}
+}
+
+// WithMask sets the mask for an edit request. Fully transparent areas of the
+// mask indicate where the image should be edited.
+func WithMask(mask File) CreateOpt {
+	return func(r *request) {
+		r.mask = &mask
+	}
+}
+
+// WithUser sets the user for the image request.
+func WithUser(user string) CreateOpt {
+	return func(r *request) {
+		r.User = &user
+	}
+}
+
+// WithAPIKey sets the API key for the image request.
+func WithAPIKey(apiKey string) CreateOpt {
+	return func(r *request) {
+		r.apiKey = apiKey
+	}
+}
+
+// A Response is a response from the images API.
+type Response struct {
+	Created      int64   `json:"created"`
+	Data         []Image `json:"data"`
+	Background   string  `json:"background,omitempty"`
+	OutputFormat string  `json:"output_format,omitempty"`
+	Quality      string  `json:"quality,omitempty"`
+	Size         string  `json:"size,omitempty"`
+	Usage        *Usage  `json:"usage,omitempty"`
+}
+
+// An Image is a single created image.
+//
+// Depending on the model and response format, either URL or B64JSON is set.
+type Image struct {
+	URL           string `json:"url,omitempty"`
+	B64JSON       string `json:"b64_json,omitempty"`
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+}
+
+// ErrNoImageData is returned when an image has no base64 data to decode.
+var ErrNoImageData = errors.New("image has no base64 data")
+
+// Bytes decodes the image's base64 data.
+//
+// It returns ErrNoImageData if the image was returned as a URL.
+func (i *Image) Bytes() ([]byte, error) {
+	if i.B64JSON == "" {
+		return nil, ErrNoImageData
+	}
+
+	b, err := base64.StdEncoding.DecodeString(i.B64JSON)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image data: %w", err)
+	}
+
+	return b, nil
+}
+
+// A Usage defines token usage statistics for image models.
+type Usage struct {
+	InputTokens        int                `json:"input_tokens"`
+	OutputTokens       int                `json:"output_tokens"`
+	TotalTokens        int                `json:"total_tokens"`
+	InputTokensDetails InputTokensDetails `json:"input_tokens_details"`
+}
+
+// InputTokensDetails breaks down image model input tokens.
+type InputTokensDetails struct {
+	TextTokens  int `json:"text_tokens"`
+	ImageTokens int `json:"image_tokens"`
+}
+
+// Service is a service wrapping an OpenAI-compatible images API.
+type Service service.Service
+
+// Generate creates images from a prompt.
+func (h *Service) Generate(
+	ctx context.Context,
+	model string,
+	prompt string,
+	opts ...CreateOpt,
+) (*Response, error) {
+	req := request{Model: model, Prompt: prompt}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	return h.do(ctx, "/images/generations", req, req.apiKey)
+}
+
+// GenerateStreaming creates images from a prompt, streaming partial images as
+// they are rendered.
+//
+// The caller is responsible for closing the stream.
+func (h *Service) GenerateStreaming(
+	ctx context.Context,
+	model string,
+	prompt string,
+	opts ...CreateOpt,
+) (*StreamingResponse, error) {
+	req := request{Model: model, Prompt: prompt}
+
+	opts = append(opts, withStream())
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	return h.doStreaming(ctx, "/images/generations", req, req.apiKey)
+}
+
+// Edit edits or extends one or more images given a prompt.
+func (h *Service) Edit(
+	ctx context.Context,
+	model string,
+	prompt string,
+	images []File,
+	opts ...CreateOpt,
+) (*Response, error) {
+	req := request{Model: model, Prompt: prompt}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	return h.do(ctx, "/images/edits", newEditForm(&req, images), req.apiKey)
+}
+
+// EditStreaming edits or extends one or more images given a prompt,
+// streaming partial images as they are rendered.
+//
+// The caller is responsible for closing the stream.
+func (h *Service) EditStreaming(
+	ctx context.Context,
+	model string,
+	prompt string,
+	images []File,
+	opts ...CreateOpt,
+) (*StreamingResponse, error) {
+	req := request{Model: model, Prompt: prompt}
+
+	opts = append(opts, withStream())
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	return h.doStreaming(ctx, "/images/edits", newEditForm(&req, images), req.apiKey)
+}
+
+// CreateVariation creates variations of an image.
+func (h *Service) CreateVariation(
+	ctx context.Context,
+	model string,
+	image File,
+	opts ...CreateOpt,
+) (*Response, error) {
+	req := request{Model: model}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	form := &service.Form{}
+	form.AddFile("image", image.Name, image.Reader)
+	req.addFormFields(form)
+
+	return h.do(ctx, "/images/variations", form, req.apiKey)
+}
+
+func withStream() CreateOpt {
+	return func(r *request) {
+		stream := true
+		r.Stream = &stream
+	}
+}
+
+func newEditForm(req *request, images []File) *service.Form {
+	form := &service.Form{}
+
+	name := "image"
+	if len(images) > 1 {
+		name = "image[]"
+	}
+
+	for _, image := range images {
+		form.AddFile(name, image.Name, image.Reader)
+	}
+
+	if req.mask != nil {
+		form.AddFile("mask", req.mask.Name, req.mask.Reader)
+	}
+
+	req.addFormFields(form)
+
+	return form
+}
+
+func (h *Service) do(ctx context.Context, path string, body any, apiKey string) (*Response, error) {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, path, body,
+		service.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating images request: %w", err)
+	}
+
+	var resp Response
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing images request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+func (h *Service) doStreaming(ctx context.Context, path string, body any, apiKey string) (*StreamingResponse, error) {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, path, body,
+		service.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating images request: %w", err)
+	}
+
+	httpResp, err := h.Client.Do(httpReq, nil) //nolint: bodyclose // False positive.
+	if err != nil {
+		return nil, fmt.Errorf("error performing images request: %w", err)
+	}
+
+	return newStreamingResponse(httpResp.Body), nil
+}
+
+// Streaming event types.
+const (
+	EventGenerationPartialImage = "image_generation.partial_image"
+	EventGenerationCompleted    = "image_generation.completed"
+	EventEditPartialImage       = "image_edit.partial_image"
+	EventEditCompleted          = "image_edit.completed"
+)
+
+// A StreamEvent is a single event in a streaming image response. It is either
+// a partial image or the final, completed image.
+type StreamEvent struct {
+	Type              string `json:"type"`
+	B64JSON           string `json:"b64_json"`
+	CreatedAt         int64  `json:"created_at"`
+	Size              string `json:"size"`
+	Quality           string `json:"quality"`
+	Background        string `json:"background"`
+	OutputFormat      string `json:"output_format"`
+	PartialImageIndex int    `json:"partial_image_index"`
+	Usage             *Usage `json:"usage,omitempty"`
+}
+
+// Completed reports whether the event contains the final image.
+func (e *StreamEvent) Completed() bool {
+	return e.Type == EventGenerationCompleted || e.Type == EventEditCompleted
+}
+
+// Bytes decodes the event's base64 image data.
+func (e *StreamEvent) Bytes() ([]byte, error) {
+	img := Image{B64JSON: e.B64JSON}
+
+	return img.Bytes()
+}
+
+// UnmarshalSSEValue implements sseparser.UnmarshalerSSEValue.
+func (e *StreamEvent) UnmarshalSSEValue(v string) error {
+	if err := json.Unmarshal([]byte(v), e); err != nil {
+		return fmt.Errorf("error unmarshaling image stream event: %w", err)
+	}
+
+	return nil
+}
+
+type streamingEvent struct {
+	Data StreamEvent `sse:"data"`
+}
+
+// ErrStreamDone is returned when the stream is done (after the completed
+// image has been read).
+var ErrStreamDone = errors.New("image stream is done")
+
+// A StreamingResponse is a streaming response to an image request. It reads
+// an io.ReadCloser and emits StreamEvents.
+//
+// The caller is responsible for closing the stream (`stream.Close()`).
+type StreamingResponse struct {
+	closer  io.Closer
+	scanner *sseparser.StreamScanner
+	done    bool
+}
+
+// Next returns the next event in the streaming response.
+//
+// After the completed event has been returned, it returns ErrStreamDone.
+func (s *StreamingResponse) Next() (*StreamEvent, error) {
+	if s.done {
+		return nil, ErrStreamDone
+	}
+
+	var evt streamingEvent
+
+	if _, err := s.scanner.UnmarshalNext(&evt); err != nil {
+		if errors.Is(err, sseparser.ErrStreamEOF) {
+			return nil, fmt.Errorf("stream ended before completed image: %w", err)
+		}
+
+		return nil, fmt.Errorf("error reading next event from stream: %w", err)
+	}
+
+	s.done = evt.Data.Completed()
+
+	return &evt.Data, nil
+}
+
+// Close closes the stream.
+func (s *StreamingResponse) Close() error {
+	if err := s.closer.Close(); err != nil {
+		return fmt.Errorf("error closing stream: %w", err)
+	}
+
+	return nil
+}
+
+func newStreamingResponse(rc io.ReadCloser) *StreamingResponse {
+	scanner := sseparser.NewStreamScanner(rc)
+
+	return &StreamingResponse{closer: rc, scanner: scanner}
+}
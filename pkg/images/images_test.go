@@ -0,0 +1,151 @@
+package images_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/images"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_GenerateImage(t *testing.T) {
+	t.Parallel()
+
+	r := &http.Response{}
+	r.StatusCode = http.StatusOK
+	r.Body = httptesting.NewTestBody(strings.NewReader(`{"created": 1, "data": [{"b64_json": "aW1hZ2U="}]}`))
+	doer := httptesting.NewTestDoer(r, nil)
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", &doer)
+	c := (*images.Service)(svc)
+
+	resp, err := c.Generate(
+		context.Background(),
+		"gpt-image-1",
+		"A cat",
+		images.WithSize("1024x1024"),
+		images.WithBackground("transparent"),
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+
+	b, err := resp.Data[0].Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("image"), b)
+}
+
+func TestHTTPClient_EditImage(t *testing.T) {
+	t.Parallel()
+
+	parts := map[string]string{}
+	contentTypes := map[string]string{}
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, err
+		}
+
+		mr := multipart.NewReader(req.Body, params["boundary"])
+
+		for {
+			part, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			b, err := io.ReadAll(part)
+			if err != nil {
+				return nil, err
+			}
+
+			parts[part.FormName()] = string(b)
+			contentTypes[part.FormName()] = part.Header.Get("Content-Type")
+		}
+
+		r := &http.Response{}
+		r.StatusCode = http.StatusOK
+		r.Body = httptesting.NewTestBody(strings.NewReader(`{"data": [{"url": "https://example.com/image.png"}]}`))
+
+		return r, nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*images.Service)(svc)
+
+	resp, err := c.Edit(
+		context.Background(),
+		"dall-e-2",
+		"Add a hat",
+		[]images.File{images.NewFile("cat.png", strings.NewReader("cat"))},
+		images.WithMask(images.NewFile("mask.png", strings.NewReader("mask"))),
+		images.WithN(2),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"image":  "cat",
+		"mask":   "mask",
+		"model":  "dall-e-2",
+		"prompt": "Add a hat",
+		"n":      "2",
+	}, parts)
+	assert.Equal(t, "image/png", contentTypes["image"])
+
+	_, err = resp.Data[0].Bytes()
+	require.ErrorIs(t, err, images.ErrNoImageData)
+}
+
+func TestHTTPClient_GenerateImageStreaming(t *testing.T) {
+	t.Parallel()
+
+	sse := `event: image_generation.partial_image
+data: {"type": "image_generation.partial_image", "b64_json": "cGFydA==", "partial_image_index": 0}
+
+event: image_generation.completed
+data: {"type": "image_generation.completed", "b64_json": "aW1hZ2U=", "usage": {"total_tokens": 10}}
+
+`
+
+	r := &http.Response{}
+	r.StatusCode = http.StatusOK
+	r.Body = httptesting.NewTestBody(strings.NewReader(sse))
+	doer := httptesting.NewTestDoer(r, nil)
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", &doer)
+	c := (*images.Service)(svc)
+
+	stream, err := c.GenerateStreaming(context.Background(), "gpt-image-1", "A cat", images.WithPartialImages(1))
+	require.NoError(t, err)
+
+	evt, err := stream.Next()
+	require.NoError(t, err)
+	assert.False(t, evt.Completed())
+
+	b, err := evt.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("part"), b)
+
+	evt, err = stream.Next()
+	require.NoError(t, err)
+	assert.True(t, evt.Completed())
+	assert.Equal(t, 10, evt.Usage.TotalTokens)
+
+	_, err = stream.Next()
+	require.ErrorIs(t, err, images.ErrStreamDone)
+	require.NoError(t, stream.Close())
+}
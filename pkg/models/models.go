@@ -0,0 +1,96 @@
+// Package models provides a models client for the OpenAI API.
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// A Model is a model available to the API key.
+type Model struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+// A ListResponse is a response to a request to list models.
+//
+// The models list is not paginated, so it always contains every model.
+type ListResponse = service.Page[Model]
+
+// A DeleteResponse is a response to a request to delete a model.
+type DeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
+// Service is a service wrapping an OpenAI-compatible models API.
+type Service service.Service
+
+// List lists the models available to the API key.
+func (h *Service) List(ctx context.Context) (*ListResponse, error) {
+	resp, err := service.ListPage[Model](ctx, &h.Client, "/models")
+	if err != nil {
+		return nil, fmt.Errorf("error listing models: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Retrieve retrieves a single model by its ID.
+func (h *Service) Retrieve(ctx context.Context, id string) (*Model, error) {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodGet, "/models/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating model request: %w", err)
+	}
+
+	var resp Model
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing model request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// Exists reports whether a model with the given ID is available to the API
+// key.
+//
+// A 404 response is reported as false with a nil error. Any other failure is
+// returned as an error.
+func (h *Service) Exists(ctx context.Context, id string) (bool, error) {
+	_, err := h.Retrieve(ctx, id)
+	if err == nil {
+		return true, nil
+	}
+
+	var statusErr service.UnexpectedStatusCodeError
+	if errors.As(err, &statusErr) && statusErr.Actual == http.StatusNotFound {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// Delete deletes a fine-tuned model.
+//
+// The API key must belong to the organization that owns the model.
+func (h *Service) Delete(ctx context.Context, id string) (*DeleteResponse, error) {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodDelete, "/models/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating model deletion request: %w", err)
+	}
+
+	var resp DeleteResponse
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing model deletion request: %w", err)
+	}
+
+	return &resp, nil
+}
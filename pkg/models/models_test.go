@@ -0,0 +1,62 @@
+package models_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_ListModels(t *testing.T) {
+	t.Parallel()
+
+	listresp := models.ListResponse{
+		Object: "list",
+		Data: []models.Model{{
+			ID:      "gpt-4",
+			Object:  "model",
+			Created: 1687882411,
+			OwnedBy: "openai",
+		}},
+	}
+	bodyb, err := json.Marshal(listresp)
+	require.NoError(t, err)
+
+	r := &http.Response{}
+	r.StatusCode = http.StatusOK
+	r.Body = httptesting.NewTestBody(bytes.NewReader(bodyb))
+	doer := httptesting.NewTestDoer(r, nil)
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", &doer)
+	c := (*models.Service)(svc)
+
+	resp, err := c.List(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, &listresp, resp)
+}
+
+func TestHTTPClient_ModelExists(t *testing.T) {
+	t.Parallel()
+
+	r := &http.Response{}
+	r.StatusCode = http.StatusNotFound
+	r.Body = httptesting.NewTestBody(strings.NewReader(`{"error": {"message": "not found"}}`))
+	doer := httptesting.NewTestDoer(r, nil)
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", &doer)
+	c := (*models.Service)(svc)
+
+	ok, err := c.Exists(context.Background(), "ft:gpt-3.5-turbo:acme::abc123")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
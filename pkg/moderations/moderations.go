@@ -0,0 +1,272 @@
+// Package moderations provides a moderations client for the OpenAI API.
+package moderations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// An Input is a single text or image input to classify.
+type Input struct {
+	Type     string    `json:"type"`
+	Text     *string   `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// An ImageURL references an image by URL or base64-encoded data URL.
+type ImageURL struct {
+	URL string `json:"url"`
+}
+
+// NewTextInput creates a new text input.
+func NewTextInput(text string) Input {
+	return Input{Type: "text", Text: &text}
+}
+
+// NewImageInput creates a new image input.
+//
+// The URL may be a remote URL or a base64-encoded data URL.
+func NewImageInput(url string) Input {
+	return Input{Type: "image_url", ImageURL: &ImageURL{URL: url}}
+}
+
+type request struct {
+	apiKey string
+
+	Model string  `json:"model,omitempty"`
+	Input []Input `json:"input"`
+}
+
+// CreateOpt is a functional option for configuring a moderation request.
+type CreateOpt func(*request)
+
+// WithAPIKey sets the API key for the moderation request.
+func WithAPIKey(apiKey string) CreateOpt {
+	return func(r *request) {
+		r.apiKey = apiKey
+	}
+}
+
+// A Category is a moderation category.
+type Category string
+
+// Moderation categories.
+const (
+	CategoryHarassment            Category = "harassment"
+	CategoryHarassmentThreatening Category = "harassment/threatening"
+	CategoryHate                  Category = "hate"
+	CategoryHateThreatening       Category = "hate/threatening"
+	CategoryIllicit               Category = "illicit"
+	CategoryIllicitViolent        Category = "illicit/violent"
+	CategorySelfHarm              Category = "self-harm"
+	CategorySelfHarmIntent        Category = "self-harm/intent"
+	CategorySelfHarmInstructions  Category = "self-harm/instructions"
+	CategorySexual                Category = "sexual"
+	CategorySexualMinors          Category = "sexual/minors"
+	CategoryViolence              Category = "violence"
+	CategoryViolenceGraphic       Category = "violence/graphic"
+)
+
+// Categories lists every moderation category, in the order the API documents
+// them.
+var Categories = []Category{
+	CategoryHarassment,
+	CategoryHarassmentThreatening,
+	CategoryHate,
+	CategoryHateThreatening,
+	CategoryIllicit,
+	CategoryIllicitViolent,
+	CategorySelfHarm,
+	CategorySelfHarmIntent,
+	CategorySelfHarmInstructions,
+	CategorySexual,
+	CategorySexualMinors,
+	CategoryViolence,
+	CategoryViolenceGraphic,
+}
+
+// A Response is a response from the moderations API.
+type Response struct {
+	ID      string   `json:"id"`
+	Model   string   `json:"model"`
+	Results []Result `json:"results"`
+}
+
+// Flagged reports whether any result was flagged by the API.
+func (r *Response) Flagged() bool {
+	for _, result := range r.Results {
+		if result.Flagged {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ExceedsThresholds reports whether any result has a category score at or
+// above its configured threshold.
+func (r *Response) ExceedsThresholds(thresholds Thresholds) bool {
+	for _, result := range r.Results {
+		if len(result.ExceededCategories(thresholds)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// A Result is the moderation result for a single input.
+type Result struct {
+	Flagged                   bool                      `json:"flagged"`
+	Categories                CategoryFlags             `json:"categories"`
+	CategoryScores            CategoryScores            `json:"category_scores"`
+	CategoryAppliedInputTypes CategoryAppliedInputTypes `json:"category_applied_input_types"`
+}
+
+// ExceededCategories returns the categories whose score is at or above the
+// configured threshold.
+//
+// Categories without a configured threshold are ignored.
+func (r *Result) ExceededCategories(thresholds Thresholds) []Category {
+	var exceeded []Category
+
+	for _, category := range Categories {
+		threshold, ok := thresholds[category]
+		if !ok {
+			continue
+		}
+
+		if score, ok := r.CategoryScores.Get(category); ok && score >= threshold {
+			exceeded = append(exceeded, category)
+		}
+	}
+
+	return exceeded
+}
+
+// Thresholds maps categories to the minimum score at which they are considered
+// exceeded.
+type Thresholds map[Category]float64
+
+// CategoryFlags holds whether each category was flagged.
+type CategoryFlags struct {
+	Harassment            bool `json:"harassment"`
+	HarassmentThreatening bool `json:"harassment/threatening"`
+	Hate                  bool `json:"hate"`
+	HateThreatening       bool `json:"hate/threatening"`
+	Illicit               bool `json:"illicit"`
+	IllicitViolent        bool `json:"illicit/violent"`
+	SelfHarm              bool `json:"self-harm"`
+	SelfHarmIntent        bool `json:"self-harm/intent"`
+	SelfHarmInstructions  bool `json:"self-harm/instructions"`
+	Sexual                bool `json:"sexual"`
+	SexualMinors          bool `json:"sexual/minors"`
+	Violence              bool `json:"violence"`
+	ViolenceGraphic       bool `json:"violence/graphic"`
+}
+
+// CategoryScores holds the score the model assigned to each category.
+type CategoryScores struct {
+	Harassment            float64 `json:"harassment"`
+	HarassmentThreatening float64 `json:"harassment/threatening"`
+	Hate                  float64 `json:"hate"`
+	HateThreatening       float64 `json:"hate/threatening"`
+	Illicit               float64 `json:"illicit"`
+	IllicitViolent        float64 `json:"illicit/violent"`
+	SelfHarm              float64 `json:"self-harm"`
+	SelfHarmIntent        float64 `json:"self-harm/intent"`
+	SelfHarmInstructions  float64 `json:"self-harm/instructions"`
+	Sexual                float64 `json:"sexual"`
+	SexualMinors          float64 `json:"sexual/minors"`
+	Violence              float64 `json:"violence"`
+	ViolenceGraphic       float64 `json:"violence/graphic"`
+}
+
+// Get returns the score for the given category.
+//
+// It returns false if the category is unknown.
+func (s CategoryScores) Get(category Category) (float64, bool) { //nolint: cyclop // Flat lookup.
+	switch category {
+	case CategoryHarassment:
+		return s.Harassment, true
+	case CategoryHarassmentThreatening:
+		return s.HarassmentThreatening, true
+	case CategoryHate:
+		return s.Hate, true
+	case CategoryHateThreatening:
+		return s.HateThreatening, true
+	case CategoryIllicit:
+		return s.Illicit, true
+	case CategoryIllicitViolent:
+		return s.IllicitViolent, true
+	case CategorySelfHarm:
+		return s.SelfHarm, true
+	case CategorySelfHarmIntent:
+		return s.SelfHarmIntent, true
+	case CategorySelfHarmInstructions:
+		return s.SelfHarmInstructions, true
+	case CategorySexual:
+		return s.Sexual, true
+	case CategorySexualMinors:
+		return s.SexualMinors, true
+	case CategoryViolence:
+		return s.Violence, true
+	case CategoryViolenceGraphic:
+		return s.ViolenceGraphic, true
+	default:
+		return 0, false
+	}
+}
+
+// CategoryAppliedInputTypes holds the input types ("text" or "image") that
+// each category's score applies to.
+type CategoryAppliedInputTypes struct {
+	Harassment            []string `json:"harassment"`
+	HarassmentThreatening []string `json:"harassment/threatening"`
+	Hate                  []string `json:"hate"`
+	HateThreatening       []string `json:"hate/threatening"`
+	Illicit               []string `json:"illicit"`
+	IllicitViolent        []string `json:"illicit/violent"`
+	SelfHarm              []string `json:"self-harm"`
+	SelfHarmIntent        []string `json:"self-harm/intent"`
+	SelfHarmInstructions  []string `json:"self-harm/instructions"`
+	Sexual                []string `json:"sexual"`
+	SexualMinors          []string `json:"sexual/minors"`
+	Violence              []string `json:"violence"`
+	ViolenceGraphic       []string `json:"violence/graphic"`
+}
+
+// Service is a service wrapping an OpenAI-compatible moderations API.
+type Service service.Service
+
+// Create classifies a list of text and image inputs.
+//
+// If model is empty, the API's default moderation model is used.
+func (h *Service) Create(
+	ctx context.Context,
+	model string,
+	inputs []Input,
+	opts ...CreateOpt,
+) (*Response, error) {
+	req := request{Model: model, Input: inputs}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/moderations", req,
+		service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating moderations request: %w", err)
+	}
+
+	var resp Response
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing moderations request: %w", err)
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,79 @@
+package moderations_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/moderations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_CreateModeration(t *testing.T) {
+	t.Parallel()
+
+	body := `{
+		"id": "modr-123",
+		"model": "omni-moderation-latest",
+		"results": [{
+			"flagged": true,
+			"categories": {"violence": true, "sexual/minors": false},
+			"category_scores": {"violence": 0.86, "illicit": 0.12, "sexual/minors": 0.01},
+			"category_applied_input_types": {"violence": ["text", "image"], "illicit": ["text"]}
+		}]
+	}`
+
+	r := &http.Response{}
+	r.StatusCode = http.StatusOK
+	r.Body = httptesting.NewTestBody(strings.NewReader(body))
+	doer := httptesting.NewTestDoer(r, nil)
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", &doer)
+	c := (*moderations.Service)(svc)
+
+	resp, err := c.Create(
+		context.Background(),
+		"omni-moderation-latest",
+		[]moderations.Input{
+			moderations.NewTextInput("hello"),
+			moderations.NewImageInput("https://example.com/image.png"),
+		},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, resp.Results, 1)
+	assert.True(t, resp.Flagged())
+	assert.True(t, resp.Results[0].Categories.Violence)
+	assert.InDelta(t, 0.12, resp.Results[0].CategoryScores.Illicit, 0)
+	assert.Equal(t, []string{"text", "image"}, resp.Results[0].CategoryAppliedInputTypes.Violence)
+}
+
+func TestResult_ExceededCategories(t *testing.T) {
+	t.Parallel()
+
+	result := moderations.Result{
+		CategoryScores: moderations.CategoryScores{
+			Violence:     0.5,
+			Illicit:      0.2,
+			SexualMinors: 0.01,
+		},
+	}
+
+	exceeded := result.ExceededCategories(moderations.Thresholds{
+		moderations.CategoryViolence:     0.5,
+		moderations.CategoryIllicit:      0.3,
+		moderations.CategorySexualMinors: 0.001,
+	})
+	assert.Equal(t, []moderations.Category{
+		moderations.CategorySexualMinors,
+		moderations.CategoryViolence,
+	}, exceeded)
+
+	resp := moderations.Response{Results: []moderations.Result{result}}
+	assert.False(t, resp.ExceedsThresholds(moderations.Thresholds{moderations.CategoryHate: 0.1}))
+}
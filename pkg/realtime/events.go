@@ -0,0 +1,310 @@
+package realtime
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Client event types.
+const (
+	ClientEventSessionUpdate          = "session.update"
+	ClientEventInputAudioBufferAppend = "input_audio_buffer.append"
+	ClientEventInputAudioBufferCommit = "input_audio_buffer.commit"
+	ClientEventInputAudioBufferClear  = "input_audio_buffer.clear"
+	ClientEventConversationItemCreate = "conversation.item.create"
+	ClientEventResponseCreate         = "response.create"
+	ClientEventResponseCancel         = "response.cancel"
+)
+
+// Server event types.
+const (
+	EventError                           = "error"
+	EventSessionCreated                  = "session.created"
+	EventSessionUpdated                  = "session.updated"
+	EventConversationItemCreated         = "conversation.item.created"
+	EventInputAudioBufferCommitted       = "input_audio_buffer.committed"
+	EventInputAudioBufferCleared         = "input_audio_buffer.cleared"
+	EventInputAudioBufferSpeechStarted   = "input_audio_buffer.speech_started"
+	EventInputAudioBufferSpeechStopped   = "input_audio_buffer.speech_stopped"
+	EventInputAudioTranscriptionComplete = "conversation.item.input_audio_transcription.completed"
+	EventResponseCreated                 = "response.created"
+	EventResponseDone                    = "response.done"
+	EventResponseOutputItemAdded         = "response.output_item.added"
+	EventResponseOutputItemDone          = "response.output_item.done"
+	EventResponseContentPartAdded        = "response.content_part.added"
+	EventResponseContentPartDone         = "response.content_part.done"
+	EventResponseTextDelta               = "response.text.delta"
+	EventResponseTextDone                = "response.text.done"
+	EventResponseAudioDelta              = "response.audio.delta"
+	EventResponseAudioDone               = "response.audio.done"
+	EventResponseAudioTranscriptDelta    = "response.audio_transcript.delta"
+	EventResponseAudioTranscriptDone     = "response.audio_transcript.done"
+	EventResponseFunctionCallArgsDelta   = "response.function_call_arguments.delta"
+	EventResponseFunctionCallArgsDone    = "response.function_call_arguments.done"
+	EventRateLimitsUpdated               = "rate_limits.updated"
+)
+
+// A ClientEvent is an event sent by the client to the server.
+type ClientEvent interface {
+	ClientEventType() string
+}
+
+// A SessionUpdate updates the session configuration.
+type SessionUpdate struct {
+	Session SessionConfig `json:"session"`
+}
+
+// ClientEventType implements ClientEvent.
+func (SessionUpdate) ClientEventType() string { return ClientEventSessionUpdate }
+
+// An InputAudioBufferAppend appends base64-encoded audio to the input audio
+// buffer. Use NewInputAudioBufferAppend to encode raw audio.
+type InputAudioBufferAppend struct {
+	Audio string `json:"audio"`
+}
+
+// NewInputAudioBufferAppend creates an InputAudioBufferAppend from raw audio
+// bytes.
+func NewInputAudioBufferAppend(audio []byte) InputAudioBufferAppend {
+	return InputAudioBufferAppend{Audio: base64.StdEncoding.EncodeToString(audio)}
+}
+
+// ClientEventType implements ClientEvent.
+func (InputAudioBufferAppend) ClientEventType() string { return ClientEventInputAudioBufferAppend }
+
+// An InputAudioBufferCommit commits the input audio buffer as a user message.
+type InputAudioBufferCommit struct{}
+
+// ClientEventType implements ClientEvent.
+func (InputAudioBufferCommit) ClientEventType() string { return ClientEventInputAudioBufferCommit }
+
+// An InputAudioBufferClear clears the input audio buffer.
+type InputAudioBufferClear struct{}
+
+// ClientEventType implements ClientEvent.
+func (InputAudioBufferClear) ClientEventType() string { return ClientEventInputAudioBufferClear }
+
+// A ConversationItemCreate adds an item to the conversation.
+type ConversationItemCreate struct {
+	PreviousItemID string           `json:"previous_item_id,omitempty"`
+	Item           ConversationItem `json:"item"`
+}
+
+// ClientEventType implements ClientEvent.
+func (ConversationItemCreate) ClientEventType() string { return ClientEventConversationItemCreate }
+
+// A ResponseCreate asks the server to create a response.
+type ResponseCreate struct {
+	Response *ResponseConfig `json:"response,omitempty"`
+}
+
+// ClientEventType implements ClientEvent.
+func (ResponseCreate) ClientEventType() string { return ClientEventResponseCreate }
+
+// A ResponseCancel cancels the in-progress response.
+type ResponseCancel struct {
+	ResponseID string `json:"response_id,omitempty"`
+}
+
+// ClientEventType implements ClientEvent.
+func (ResponseCancel) ClientEventType() string { return ClientEventResponseCancel }
+
+// MarshalClientEvent marshals a client event to JSON, including its type.
+func MarshalClientEvent(event ClientEvent) ([]byte, error) {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling %s event: %w", event.ClientEventType(), err)
+	}
+
+	typ, err := json.Marshal(event.ClientEventType())
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling event type: %w", err)
+	}
+
+	// Splice the type field into the event's JSON object.
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":`)
+	buf.Write(typ)
+
+	if !bytes.Equal(b, []byte("{}")) {
+		buf.WriteByte(',')
+	}
+
+	buf.Write(b[1:])
+
+	return buf.Bytes(), nil
+}
+
+// A SessionConfig configures a realtime session.
+type SessionConfig struct {
+	ID                      string                   `json:"id,omitempty"`
+	Model                   string                   `json:"model,omitempty"`
+	Modalities              []string                 `json:"modalities,omitempty"`
+	Instructions            string                   `json:"instructions,omitempty"`
+	Voice                   string                   `json:"voice,omitempty"`
+	InputAudioFormat        string                   `json:"input_audio_format,omitempty"`
+	OutputAudioFormat       string                   `json:"output_audio_format,omitempty"`
+	InputAudioTranscription *InputAudioTranscription `json:"input_audio_transcription,omitempty"`
+	TurnDetection           *TurnDetection           `json:"turn_detection,omitempty"`
+	Tools                   []Tool                   `json:"tools,omitempty"`
+	ToolChoice              string                   `json:"tool_choice,omitempty"`
+	Temperature             *float64                 `json:"temperature,omitempty"`
+}
+
+// InputAudioTranscription configures transcription of input audio.
+type InputAudioTranscription struct {
+	Model string `json:"model"`
+}
+
+// TurnDetection configures server-side voice activity detection.
+type TurnDetection struct {
+	Type              string   `json:"type"`
+	Threshold         *float64 `json:"threshold,omitempty"`
+	PrefixPaddingMs   *int     `json:"prefix_padding_ms,omitempty"`
+	SilenceDurationMs *int     `json:"silence_duration_ms,omitempty"`
+	CreateResponse    *bool    `json:"create_response,omitempty"`
+}
+
+// A Tool is a function the model may call.
+type Tool struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters,omitempty"`
+}
+
+// NewFunctionTool creates a new function tool.
+//
+// The parameters must be a JSON-serializable JSON Schema object.
+func NewFunctionTool(name string, description string, parameters any) Tool {
+	return Tool{Type: "function", Name: name, Description: description, Parameters: parameters}
+}
+
+// A ResponseConfig configures a single response, overriding the session
+// configuration.
+type ResponseConfig struct {
+	Modalities   []string           `json:"modalities,omitempty"`
+	Instructions string             `json:"instructions,omitempty"`
+	Voice        string             `json:"voice,omitempty"`
+	Tools        []Tool             `json:"tools,omitempty"`
+	ToolChoice   string             `json:"tool_choice,omitempty"`
+	Conversation string             `json:"conversation,omitempty"`
+	Input        []ConversationItem `json:"input,omitempty"`
+	Metadata     map[string]string  `json:"metadata,omitempty"`
+}
+
+// Conversation item types.
+const (
+	ItemTypeMessage            = "message"
+	ItemTypeFunctionCall       = "function_call"
+	ItemTypeFunctionCallOutput = "function_call_output"
+)
+
+// A ConversationItem is a message, function call or function call output in a
+// conversation.
+type ConversationItem struct {
+	ID        string        `json:"id,omitempty"`
+	Type      string        `json:"type"`
+	Status    string        `json:"status,omitempty"`
+	Role      string        `json:"role,omitempty"`
+	Content   []ItemContent `json:"content,omitempty"`
+	CallID    string        `json:"call_id,omitempty"`
+	Name      string        `json:"name,omitempty"`
+	Arguments string        `json:"arguments,omitempty"`
+	Output    string        `json:"output,omitempty"`
+}
+
+// NewUserText creates a user message item with text content.
+func NewUserText(text string) ConversationItem {
+	return ConversationItem{
+		Type:    ItemTypeMessage,
+		Role:    "user",
+		Content: []ItemContent{{Type: "input_text", Text: text}},
+	}
+}
+
+// NewFunctionCallOutput creates a function call output item, which returns
+// the result of a function call to the model.
+func NewFunctionCallOutput(callID string, output string) ConversationItem {
+	return ConversationItem{Type: ItemTypeFunctionCallOutput, CallID: callID, Output: output}
+}
+
+// An ItemContent is a content part of a message item.
+type ItemContent struct {
+	Type       string `json:"type"`
+	Text       string `json:"text,omitempty"`
+	Audio      string `json:"audio,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+}
+
+// A ServerEvent is an event sent by the server to the client.
+//
+// Which fields are set depends on Type.
+type ServerEvent struct {
+	Type           string            `json:"type"`
+	EventID        string            `json:"event_id,omitempty"`
+	Session        *SessionConfig    `json:"session,omitempty"`
+	Item           *ConversationItem `json:"item,omitempty"`
+	PreviousItemID string            `json:"previous_item_id,omitempty"`
+	Response       *Response         `json:"response,omitempty"`
+	ResponseID     string            `json:"response_id,omitempty"`
+	ItemID         string            `json:"item_id,omitempty"`
+	OutputIndex    int               `json:"output_index,omitempty"`
+	ContentIndex   int               `json:"content_index,omitempty"`
+	Delta          string            `json:"delta,omitempty"`
+	Text           string            `json:"text,omitempty"`
+	Transcript     string            `json:"transcript,omitempty"`
+	CallID         string            `json:"call_id,omitempty"`
+	Name           string            `json:"name,omitempty"`
+	Arguments      string            `json:"arguments,omitempty"`
+	AudioStartMs   int               `json:"audio_start_ms,omitempty"`
+	AudioEndMs     int               `json:"audio_end_ms,omitempty"`
+	Error          *Error            `json:"error,omitempty"`
+
+	// Raw holds the event's JSON, for event types this package does not
+	// model.
+	Raw json.RawMessage `json:"-"`
+}
+
+// AudioDelta decodes the base64 audio of a "response.audio.delta" event.
+func (e *ServerEvent) AudioDelta() ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(e.Delta)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding audio delta: %w", err)
+	}
+
+	return b, nil
+}
+
+// A Response is a model response in a realtime session.
+type Response struct {
+	ID     string             `json:"id"`
+	Object string             `json:"object,omitempty"`
+	Status string             `json:"status"`
+	Output []ConversationItem `json:"output,omitempty"`
+	Usage  *Usage             `json:"usage,omitempty"`
+}
+
+// A Usage defines usage statistics.
+type Usage struct {
+	TotalTokens  int `json:"total_tokens"`
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// An Error is an error sent by the server. Most errors are recoverable, and
+// the session stays open.
+type Error struct {
+	Type    string `json:"type"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+	Param   string `json:"param,omitempty"`
+	EventID string `json:"event_id,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("realtime %s: %s", e.Type, e.Message)
+}
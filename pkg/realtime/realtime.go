@@ -0,0 +1,300 @@
+// Package realtime provides a WebSocket client for the OpenAI realtime API.
+//
+// A Session sends typed client events with Send (or AppendAudio, for raw
+// audio), and delivers typed server events on the channel returned by Events.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	"github.com/coder/websocket"
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// Audio format constants for PCM16 audio, the default realtime audio format.
+const (
+	// SampleRate is the sample rate of PCM16 audio, in hertz.
+	SampleRate = 24000
+
+	// DefaultAudioChunkSize is the default size of the chunks AppendAudio
+	// sends, which is 100ms of 16-bit mono PCM audio.
+	DefaultAudioChunkSize = SampleRate * 2 / 10
+)
+
+// DefaultEventBuffer is the default capacity of a session's event channel.
+const DefaultEventBuffer = 64
+
+type connectRequest struct {
+	apiKey         string
+	eventBuffer    int
+	audioChunkSize int
+	header         http.Header
+}
+
+// ConnectOpt is a functional option for configuring a realtime session.
+type ConnectOpt func(*connectRequest)
+
+// WithEventBuffer sets the capacity of the session's event channel. When the
+// channel is full, the session stops reading from the connection until the
+// caller receives more events.
+func WithEventBuffer(n int) ConnectOpt {
+	return func(r *connectRequest) {
+		r.eventBuffer = n
+	}
+}
+
+// WithAudioChunkSize sets the size, in bytes, of the chunks AppendAudio sends.
+// It is rounded down to a whole number of 16-bit samples.
+func WithAudioChunkSize(size int) ConnectOpt {
+	return func(r *connectRequest) {
+		r.audioChunkSize = size
+	}
+}
+
+// WithBeta sets the "OpenAI-Beta: realtime=v1" header, which selects the beta
+// version of the realtime API for preview models.
+func WithBeta() ConnectOpt {
+	return func(r *connectRequest) {
+		r.header.Set("OpenAI-Beta", "realtime=v1")
+	}
+}
+
+// WithAPIKey sets the API key for the session.
+func WithAPIKey(apiKey string) ConnectOpt {
+	return func(r *connectRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// Service is a service wrapping an OpenAI-compatible realtime API.
+type Service service.Service
+
+// Connect opens a realtime session with the given model.
+//
+// The context is only used to open the session. The caller is responsible for
+// closing the session (`session.Close()`).
+func (h *Service) Connect(ctx context.Context, model string, opts ...ConnectOpt) (*Session, error) {
+	req := connectRequest{
+		eventBuffer:    DefaultEventBuffer,
+		audioChunkSize: DefaultAudioChunkSize,
+		header:         make(http.Header),
+	}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	// Build the handshake as a normal request, so that it uses the client's
+	// base URL and authentication.
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodGet, "/realtime", nil,
+		service.WithAPIKey(req.apiKey),
+		service.WithQuery(url.Values{"model": {model}}))
+	if err != nil {
+		return nil, fmt.Errorf("error creating realtime request: %w", err)
+	}
+
+	for key, values := range req.header {
+		httpReq.Header[key] = values
+	}
+
+	conn, resp, err := websocket.Dial(ctx, httpReq.URL.String(), &websocket.DialOptions{
+		HTTPClient: h.Client.HTTPClient(),
+		HTTPHeader: httpReq.Header,
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+			return nil, fmt.Errorf("error opening realtime session: %w", service.UnexpectedStatusCodeError{
+				Expected: http.StatusSwitchingProtocols,
+				Actual:   resp.StatusCode,
+				Response: resp,
+			})
+		}
+
+		return nil, fmt.Errorf("error opening realtime session: %w", err)
+	}
+
+	// Realtime events, especially audio deltas, can exceed the default limit.
+	conn.SetReadLimit(-1)
+
+	return newSession(conn, req), nil
+}
+
+// ErrSessionClosed is returned when sending on a closed session.
+var ErrSessionClosed = errors.New("realtime session is closed")
+
+// A Session is an open realtime session.
+type Session struct {
+	conn           *websocket.Conn
+	events         chan ServerEvent
+	audioChunkSize int
+
+	ctx    context.Context //nolint: containedctx // Scopes the session's lifetime.
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	closed atomic.Bool
+	mu     sync.Mutex
+	err    error
+}
+
+func newSession(conn *websocket.Conn, req connectRequest) *Session {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Session{
+		conn:           conn,
+		events:         make(chan ServerEvent, req.eventBuffer),
+		audioChunkSize: req.audioChunkSize &^ 1,
+		ctx:            ctx,
+		cancel:         cancel,
+		done:           make(chan struct{}),
+	}
+
+	if s.audioChunkSize < 2 { //revive:disable-line:add-constant
+		s.audioChunkSize = DefaultAudioChunkSize
+	}
+
+	go s.read()
+
+	return s
+}
+
+// Events returns the channel of server events. It is closed when the session
+// ends; call Err to find out why.
+func (s *Session) Events() <-chan ServerEvent {
+	return s.events
+}
+
+// Err returns the error that ended the session, once the events channel is
+// closed. It returns nil if the session was closed with Close or closed
+// normally by the server.
+func (s *Session) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+func (s *Session) read() {
+	defer close(s.done)
+	defer close(s.events)
+
+	for {
+		_, b, err := s.conn.Read(s.ctx)
+		if err != nil {
+			s.setErr(err)
+
+			return
+		}
+
+		var evt ServerEvent
+		if err := json.Unmarshal(b, &evt); err != nil {
+			s.setErr(fmt.Errorf("error unmarshaling server event: %w", err))
+			_ = s.conn.Close(websocket.StatusUnsupportedData, "invalid event")
+
+			return
+		}
+
+		evt.Raw = b
+
+		select {
+		case s.events <- evt:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Session) setErr(err error) {
+	if s.closed.Load() || websocket.CloseStatus(err) == websocket.StatusNormalClosure {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = fmt.Errorf("error reading server event: %w", err)
+}
+
+// Send sends a client event.
+func (s *Session) Send(ctx context.Context, event ClientEvent) error {
+	if s.closed.Load() {
+		return ErrSessionClosed
+	}
+
+	b, err := MarshalClientEvent(event)
+	if err != nil {
+		return err
+	}
+
+	if err := s.conn.Write(ctx, websocket.MessageText, b); err != nil {
+		return fmt.Errorf("error sending %s event: %w", event.ClientEventType(), err)
+	}
+
+	return nil
+}
+
+// AppendAudio appends raw audio (by default, 24kHz 16-bit mono little-endian
+// PCM) to the input audio buffer, split into base64-encoded chunks (see
+// WithAudioChunkSize).
+func (s *Session) AppendAudio(ctx context.Context, audio []byte) error {
+	for _, chunk := range ChunkAudio(audio, s.audioChunkSize) {
+		if err := s.Send(ctx, NewInputAudioBufferAppend(chunk)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ChunkAudio splits audio into chunks of at most size bytes.
+func ChunkAudio(audio []byte, size int) [][]byte {
+	if size < 1 {
+		size = DefaultAudioChunkSize
+	}
+
+	chunks := make([][]byte, 0, (len(audio)+size-1)/size)
+
+	for len(audio) > 0 {
+		n := min(size, len(audio))
+		chunks = append(chunks, audio[:n])
+		audio = audio[n:]
+	}
+
+	return chunks
+}
+
+// Close closes the session, and waits for the events channel to be closed.
+// Events that have not been received are discarded.
+func (s *Session) Close() error {
+	if s.closed.Swap(true) {
+		<-s.done
+
+		return nil
+	}
+
+	closeErr := make(chan error, 1)
+
+	go func() {
+		closeErr <- s.conn.Close(websocket.StatusNormalClosure, "")
+	}()
+
+	// Keep the reader running until the close handshake completes.
+	for range s.events { //nolint: revive // Draining the channel.
+	}
+
+	s.cancel()
+
+	if err := <-closeErr; err != nil && !errors.Is(err, net.ErrClosed) {
+		return fmt.Errorf("error closing realtime session: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package realtime_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/realtime"
+	"github.com/jclem/openai-go/pkg/realtime/realtimetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newService(srv *realtimetest.Server) *realtime.Service {
+	return (*realtime.Service)(service.New(srv.BaseURL(), "api-key", http.DefaultClient))
+}
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	type received struct {
+		model   string
+		auth    string
+		session realtime.SessionUpdate
+		audio   []byte
+		appends int
+	}
+
+	got := make(chan received, 1)
+
+	srv := realtimetest.NewServer(func(ctx context.Context, conn *realtimetest.Conn) {
+		r := received{
+			model: conn.Request.URL.Query().Get("model"),
+			auth:  conn.Request.Header.Get("Authorization"),
+		}
+
+		defer func() { got <- r }()
+
+		if err := conn.Send(ctx, realtime.ServerEvent{
+			Type:    realtime.EventSessionCreated,
+			Session: &realtime.SessionConfig{ID: "sess_1"},
+		}); err != nil {
+			return
+		}
+
+		for {
+			msg, err := conn.Read(ctx)
+			if err != nil {
+				return
+			}
+
+			switch msg.Type {
+			case realtime.ClientEventSessionUpdate:
+				if err := msg.Decode(&r.session); err != nil {
+					return
+				}
+			case realtime.ClientEventInputAudioBufferAppend:
+				var evt realtime.InputAudioBufferAppend
+				if err := msg.Decode(&evt); err != nil {
+					return
+				}
+
+				chunk, err := base64.StdEncoding.DecodeString(evt.Audio)
+				if err != nil {
+					return
+				}
+
+				r.audio = append(r.audio, chunk...)
+				r.appends++
+			case realtime.ClientEventResponseCreate:
+				_ = conn.Send(ctx, realtime.ServerEvent{
+					Type:  realtime.EventResponseAudioDelta,
+					Delta: base64.StdEncoding.EncodeToString([]byte{1, 2, 3, 4}),
+				})
+				_ = conn.Send(ctx, realtime.ServerEvent{
+					Type:     realtime.EventResponseDone,
+					Response: &realtime.Response{ID: "resp_1", Status: "completed"},
+				})
+			}
+		}
+	})
+	defer srv.Close()
+
+	session, err := newService(srv).Connect(context.Background(), "gpt-realtime",
+		realtime.WithAudioChunkSize(4000))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	evt := <-session.Events()
+	assert.Equal(t, realtime.EventSessionCreated, evt.Type)
+	assert.Equal(t, "sess_1", evt.Session.ID)
+
+	require.NoError(t, session.Send(ctx, realtime.SessionUpdate{Session: realtime.SessionConfig{
+		Instructions: "Be brief.",
+		Tools:        []realtime.Tool{realtime.NewFunctionTool("lookup", "Look things up", nil)},
+	}}))
+
+	audio := make([]byte, 10000)
+	for i := range audio {
+		audio[i] = byte(i)
+	}
+
+	require.NoError(t, session.AppendAudio(ctx, audio))
+	require.NoError(t, session.Send(ctx, realtime.ResponseCreate{}))
+
+	var out []byte
+
+	for evt := range session.Events() {
+		if evt.Type == realtime.EventResponseAudioDelta {
+			b, err := evt.AudioDelta()
+			require.NoError(t, err)
+
+			out = append(out, b...)
+		}
+
+		if evt.Type == realtime.EventResponseDone {
+			assert.Equal(t, "resp_1", evt.Response.ID)
+
+			break
+		}
+	}
+
+	assert.Equal(t, []byte{1, 2, 3, 4}, out)
+	require.NoError(t, session.Close())
+	require.NoError(t, session.Err())
+	require.ErrorIs(t, session.Send(ctx, realtime.ResponseCreate{}), realtime.ErrSessionClosed)
+
+	r := <-got
+	assert.Equal(t, "gpt-realtime", r.model)
+	assert.Equal(t, "Bearer api-key", r.auth)
+	assert.Equal(t, "Be brief.", r.session.Session.Instructions)
+	assert.Equal(t, "lookup", r.session.Session.Tools[0].Name)
+	assert.Equal(t, audio, r.audio)
+	assert.Equal(t, 3, r.appends)
+}
+
+func TestSessionServerError(t *testing.T) {
+	t.Parallel()
+
+	srv := realtimetest.NewServer(func(ctx context.Context, conn *realtimetest.Conn) {
+		_ = conn.Send(ctx, realtime.ServerEvent{
+			Type:  realtime.EventError,
+			Error: &realtime.Error{Type: "invalid_request_error", Message: "bad event"},
+		})
+		_ = conn.Abort("boom")
+	})
+	defer srv.Close()
+
+	session, err := newService(srv).Connect(context.Background(), "gpt-realtime")
+	require.NoError(t, err)
+
+	var events []realtime.ServerEvent
+	for evt := range session.Events() {
+		events = append(events, evt)
+	}
+
+	require.Len(t, events, 1)
+	require.EqualError(t, events[0].Error, "realtime invalid_request_error: bad event")
+	require.ErrorContains(t, session.Err(), "boom")
+	require.NoError(t, session.Close())
+}
+
+func TestConnectUnexpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	srv := realtimetest.NewServer(nil)
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	defer srv.Close()
+
+	_, err := newService(srv).Connect(context.Background(), "gpt-realtime")
+
+	var statusErr service.UnexpectedStatusCodeError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusUnauthorized, statusErr.Actual)
+}
+
+func TestChunkAudio(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, [][]byte{{1, 2}, {3, 4}, {5}}, realtime.ChunkAudio([]byte{1, 2, 3, 4, 5}, 2))
+	assert.Empty(t, realtime.ChunkAudio(nil, 2))
+}
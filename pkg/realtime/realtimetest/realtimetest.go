@@ -0,0 +1,117 @@
+// Package realtimetest provides an in-process fake realtime server for
+// testing code that uses the realtime package.
+package realtimetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/coder/websocket"
+)
+
+// A Handler handles a single realtime session on the server side. The
+// connection is closed when the handler returns.
+type Handler func(ctx context.Context, conn *Conn)
+
+// A Server is a fake realtime server, listening on a local loopback address.
+type Server struct {
+	*httptest.Server
+}
+
+// NewServer starts a fake realtime server that calls handler for every
+// session. The caller should call Close when finished, to shut it down.
+func NewServer(handler Handler) *Server {
+	return &Server{Server: httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		ws.SetReadLimit(-1)
+
+		conn := &Conn{ws: ws, Request: r}
+		handler(r.Context(), conn)
+
+		_ = ws.Close(websocket.StatusNormalClosure, "")
+	}))}
+}
+
+// BaseURL returns the server's base URL, for use with openai.WithBaseURL.
+func (s *Server) BaseURL() *url.URL {
+	u, err := url.Parse(s.URL + "/v1")
+	if err != nil {
+		panic(err)
+	}
+
+	return u
+}
+
+// A Conn is the server side of a realtime session.
+type Conn struct {
+	ws *websocket.Conn
+
+	// Request is the handshake request, which holds the session's headers and
+	// query parameters (such as "model").
+	Request *http.Request
+}
+
+// A ClientMessage is an event received from the client.
+type ClientMessage struct {
+	Type string
+	Raw  json.RawMessage
+}
+
+// Decode decodes the message into v.
+func (m ClientMessage) Decode(v any) error {
+	if err := json.Unmarshal(m.Raw, v); err != nil {
+		return fmt.Errorf("error decoding %s event: %w", m.Type, err)
+	}
+
+	return nil
+}
+
+// Read reads the next event from the client.
+func (c *Conn) Read(ctx context.Context) (ClientMessage, error) {
+	_, b, err := c.ws.Read(ctx)
+	if err != nil {
+		return ClientMessage{}, fmt.Errorf("error reading client event: %w", err)
+	}
+
+	var head struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal(b, &head); err != nil {
+		return ClientMessage{}, fmt.Errorf("error decoding client event: %w", err)
+	}
+
+	return ClientMessage{Type: head.Type, Raw: b}, nil
+}
+
+// Send sends an event (such as a realtime.ServerEvent) to the client.
+func (c *Conn) Send(ctx context.Context, event any) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding server event: %w", err)
+	}
+
+	if err := c.ws.Write(ctx, websocket.MessageText, b); err != nil {
+		return fmt.Errorf("error sending server event: %w", err)
+	}
+
+	return nil
+}
+
+// Abort closes the session with an internal error status and the given
+// reason, which the client reports as a session error.
+func (c *Conn) Abort(reason string) error {
+	if err := c.ws.Close(websocket.StatusInternalError, reason); err != nil {
+		return fmt.Errorf("error closing connection: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,338 @@
+package responses
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Item types.
+const (
+	ItemTypeMessage            = "message"
+	ItemTypeFunctionCall       = "function_call"
+	ItemTypeFunctionCallOutput = "function_call_output"
+	ItemTypeReasoning          = "reasoning"
+	ItemTypeWebSearchCall      = "web_search_call"
+	ItemTypeFileSearchCall     = "file_search_call"
+)
+
+// ErrEmptyItem is returned when marshaling an Item that has neither a variant
+// nor raw JSON.
+var ErrEmptyItem = errors.New("item has no value")
+
+// An Item is an input or output item of a response.
+//
+// Exactly one of the variant fields is set, matching Type. Items of a type
+// this package does not model have no variant set, but Raw always holds the
+// item's JSON, so such items still round-trip when passed back as input.
+type Item struct {
+	Type string
+
+	Message            *Message
+	FunctionCall       *FunctionCall
+	FunctionCallOutput *FunctionCallOutput
+	Reasoning          *Reasoning
+	WebSearchCall      *WebSearchCall
+	FileSearchCall     *FileSearchCall
+
+	Raw json.RawMessage
+}
+
+// NewMessage creates a new message item with text content.
+//
+// Assistant messages use "output_text" content, and all other roles use
+// "input_text" content.
+func NewMessage(role string, text string) Item {
+	contentType := ContentTypeInputText
+	if role == RoleAssistant {
+		contentType = ContentTypeOutputText
+	}
+
+	return NewMessageWithContent(role, Content{Type: contentType, Text: text})
+}
+
+// NewMessageWithContent creates a new message item with the given content
+// parts.
+func NewMessageWithContent(role string, content ...Content) Item {
+	return Item{Type: ItemTypeMessage, Message: &Message{Role: role, Content: content}}
+}
+
+// NewFunctionCallOutput creates a new function call output item, which
+// returns the result of a function call to the model.
+func NewFunctionCallOutput(callID string, output string) Item {
+	return Item{
+		Type:               ItemTypeFunctionCallOutput,
+		FunctionCallOutput: &FunctionCallOutput{CallID: callID, Output: output},
+	}
+}
+
+// ID returns the ID of the item, if it has one.
+func (i Item) ID() string {
+	switch {
+	case i.Message != nil:
+		return i.Message.ID
+	case i.FunctionCall != nil:
+		return i.FunctionCall.ID
+	case i.FunctionCallOutput != nil:
+		return i.FunctionCallOutput.ID
+	case i.Reasoning != nil:
+		return i.Reasoning.ID
+	case i.WebSearchCall != nil:
+		return i.WebSearchCall.ID
+	case i.FileSearchCall != nil:
+		return i.FileSearchCall.ID
+	}
+
+	var v struct {
+		ID string `json:"id"`
+	}
+
+	_ = json.Unmarshal(i.Raw, &v)
+
+	return v.ID
+}
+
+func (i Item) variant() any {
+	switch {
+	case i.Message != nil:
+		return i.Message
+	case i.FunctionCall != nil:
+		return i.FunctionCall
+	case i.FunctionCallOutput != nil:
+		return i.FunctionCallOutput
+	case i.Reasoning != nil:
+		return i.Reasoning
+	case i.WebSearchCall != nil:
+		return i.WebSearchCall
+	case i.FileSearchCall != nil:
+		return i.FileSearchCall
+	}
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (i Item) MarshalJSON() ([]byte, error) {
+	v := i.variant()
+	if v == nil {
+		if i.Raw == nil {
+			return nil, fmt.Errorf("%w: item of type %q", ErrEmptyItem, i.Type)
+		}
+
+		return i.Raw, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling %s item: %w", i.Type, err)
+	}
+
+	typ, err := json.Marshal(i.Type)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling item type: %w", err)
+	}
+
+	// Splice the type field into the variant's JSON object.
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":`)
+	buf.Write(typ)
+
+	if !bytes.Equal(b, []byte("{}")) {
+		buf.WriteByte(',')
+	}
+
+	buf.Write(b[1:])
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *Item) UnmarshalJSON(b []byte) error {
+	var head struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal(b, &head); err != nil {
+		return fmt.Errorf("error unmarshaling item type: %w", err)
+	}
+
+	*i = Item{Type: head.Type, Raw: append(json.RawMessage(nil), b...)}
+
+	var v any
+
+	switch head.Type {
+	case ItemTypeMessage:
+		i.Message = &Message{}
+		v = i.Message
+	case ItemTypeFunctionCall:
+		i.FunctionCall = &FunctionCall{}
+		v = i.FunctionCall
+	case ItemTypeFunctionCallOutput:
+		i.FunctionCallOutput = &FunctionCallOutput{}
+		v = i.FunctionCallOutput
+	case ItemTypeReasoning:
+		i.Reasoning = &Reasoning{}
+		v = i.Reasoning
+	case ItemTypeWebSearchCall:
+		i.WebSearchCall = &WebSearchCall{}
+		v = i.WebSearchCall
+	case ItemTypeFileSearchCall:
+		i.FileSearchCall = &FileSearchCall{}
+		v = i.FileSearchCall
+	default:
+		return nil
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("error unmarshaling %s item: %w", head.Type, err)
+	}
+
+	return nil
+}
+
+// Message roles.
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleSystem    = "system"
+	RoleDeveloper = "developer"
+)
+
+// A Message is a message item.
+type Message struct {
+	ID      string    `json:"id,omitempty"`
+	Role    string    `json:"role"`
+	Status  string    `json:"status,omitempty"`
+	Content []Content `json:"content"`
+}
+
+// Text returns the concatenated text of the message's text content parts.
+func (m *Message) Text() string {
+	var text string
+
+	for _, c := range m.Content {
+		if c.Type == ContentTypeInputText || c.Type == ContentTypeOutputText {
+			text += c.Text
+		}
+	}
+
+	return text
+}
+
+// Content types.
+const (
+	ContentTypeInputText  = "input_text"
+	ContentTypeInputImage = "input_image"
+	ContentTypeInputFile  = "input_file"
+	ContentTypeOutputText = "output_text"
+	ContentTypeRefusal    = "refusal"
+)
+
+// A Content is a single content part of a message.
+type Content struct {
+	Type        string       `json:"type"`
+	Text        string       `json:"text,omitempty"`
+	ImageURL    string       `json:"image_url,omitempty"`
+	FileID      string       `json:"file_id,omitempty"`
+	FileData    string       `json:"file_data,omitempty"`
+	Filename    string       `json:"filename,omitempty"`
+	Detail      string       `json:"detail,omitempty"`
+	Refusal     string       `json:"refusal,omitempty"`
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// NewInputText creates a new text input content part.
+func NewInputText(text string) Content {
+	return Content{Type: ContentTypeInputText, Text: text}
+}
+
+// NewInputImage creates a new image input content part.
+//
+// The URL may be a remote URL or a base64-encoded data URL.
+func NewInputImage(url string) Content {
+	return Content{Type: ContentTypeInputImage, ImageURL: url}
+}
+
+// NewInputFile creates a new file input content part referencing an uploaded
+// file.
+func NewInputFile(fileID string) Content {
+	return Content{Type: ContentTypeInputFile, FileID: fileID}
+}
+
+// An Annotation is a citation attached to output text.
+type Annotation struct {
+	Type       string `json:"type"`
+	Index      int    `json:"index,omitempty"`
+	FileID     string `json:"file_id,omitempty"`
+	Filename   string `json:"filename,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Title      string `json:"title,omitempty"`
+	StartIndex int    `json:"start_index,omitempty"`
+	EndIndex   int    `json:"end_index,omitempty"`
+}
+
+// A FunctionCall is a function call item, emitted when the model calls a
+// function tool.
+type FunctionCall struct {
+	ID        string `json:"id,omitempty"`
+	CallID    string `json:"call_id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+	Status    string `json:"status,omitempty"`
+}
+
+// A FunctionCallOutput is a function call output item, which returns the
+// result of a function call to the model.
+type FunctionCallOutput struct {
+	ID     string `json:"id,omitempty"`
+	CallID string `json:"call_id"`
+	Output string `json:"output"`
+	Status string `json:"status,omitempty"`
+}
+
+// A Reasoning is a reasoning item, describing the model's chain of thought.
+type Reasoning struct {
+	ID               string             `json:"id,omitempty"`
+	Summary          []ReasoningSummary `json:"summary"`
+	EncryptedContent *string            `json:"encrypted_content,omitempty"`
+	Status           string             `json:"status,omitempty"`
+}
+
+// A ReasoningSummary is a summary of a model's reasoning.
+type ReasoningSummary struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// A WebSearchCall is a web search tool call item.
+type WebSearchCall struct {
+	ID     string           `json:"id,omitempty"`
+	Status string           `json:"status,omitempty"`
+	Action *WebSearchAction `json:"action,omitempty"`
+}
+
+// A WebSearchAction describes the action taken by a web search call.
+type WebSearchAction struct {
+	Type    string `json:"type"`
+	Query   string `json:"query,omitempty"`
+	URL     string `json:"url,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// A FileSearchCall is a file search tool call item.
+type FileSearchCall struct {
+	ID      string             `json:"id,omitempty"`
+	Status  string             `json:"status,omitempty"`
+	Queries []string           `json:"queries"`
+	Results []FileSearchResult `json:"results,omitempty"`
+}
+
+// A FileSearchResult is a single result of a file search call.
+type FileSearchResult struct {
+	FileID     string         `json:"file_id"`
+	Filename   string         `json:"filename"`
+	Score      float64        `json:"score"`
+	Text       string         `json:"text"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
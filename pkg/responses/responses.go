@@ -0,0 +1,425 @@
+// Package responses provides a client for the OpenAI responses API.
+package responses
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// A Tool is a tool the model may call.
+type Tool struct {
+	Type           string   `json:"type"`
+	Name           string   `json:"name,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Parameters     any      `json:"parameters,omitempty"`
+	Strict         *bool    `json:"strict,omitempty"`
+	VectorStoreIDs []string `json:"vector_store_ids,omitempty"`
+	MaxNumResults  *int     `json:"max_num_results,omitempty"`
+}
+
+// NewFunctionTool creates a new function tool.
+//
+// The parameters must be a JSON-serializable JSON Schema object.
+func NewFunctionTool(name string, description string, parameters any) Tool {
+	return Tool{Type: "function", Name: name, Description: description, Parameters: parameters}
+}
+
+// NewWebSearchTool creates a new built-in web search tool.
+func NewWebSearchTool() Tool {
+	return Tool{Type: "web_search"}
+}
+
+// NewFileSearchTool creates a new built-in file search tool over the given
+// vector stores.
+func NewFileSearchTool(vectorStoreIDs ...string) Tool {
+	return Tool{Type: "file_search", VectorStoreIDs: vectorStoreIDs}
+}
+
+// A ReasoningConfig configures a reasoning model.
+type ReasoningConfig struct {
+	Effort  string `json:"effort,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+type request struct {
+	apiKey string
+
+	Model              string            `json:"model"`
+	Input              []Item            `json:"input"`
+	Instructions       *string           `json:"instructions,omitempty"`
+	PreviousResponseID *string           `json:"previous_response_id,omitempty"`
+	Tools              []Tool            `json:"tools,omitempty"`
+	ToolChoice         any               `json:"tool_choice,omitempty"`
+	ParallelToolCalls  *bool             `json:"parallel_tool_calls,omitempty"`
+	Temperature        *float64          `json:"temperature,omitempty"`
+	TopP               *float64          `json:"top_p,omitempty"`
+	MaxOutputTokens    *int              `json:"max_output_tokens,omitempty"`
+	Reasoning          *ReasoningConfig  `json:"reasoning,omitempty"`
+	Include            []string          `json:"include,omitempty"`
+	Store              *bool             `json:"store,omitempty"`
+	Background         *bool             `json:"background,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+	Stream             *bool             `json:"stream,omitempty"`
+	User               *string           `json:"user,omitempty"`
+}
+
+// CreateOpt is a functional option for configuring a response request.
+type CreateOpt func(*request)
+
+// WithInstructions sets the system (or developer) instructions for the
+// response request.
+func WithInstructions(instructions string) CreateOpt {
+	return func(r *request) {
+		r.Instructions = &instructions
+	}
+}
+
+// WithPreviousResponseID continues a conversation from a previous response,
+// whose input and output items are used as context.
+func WithPreviousResponseID(id string) CreateOpt {
+	return func(r *request) {
+		r.PreviousResponseID = &id
+	}
+}
+
+// WithTools sets the tools the model may call.
+func WithTools(tools ...Tool) CreateOpt {
+	return func(r *request) {
+		r.Tools = tools
+	}
+}
+
+// WithToolChoiceBySetting sets the tool choice to "none", "auto" or
+// "required".
+func WithToolChoiceBySetting(setting string) CreateOpt {
+	return func(r *request) {
+		r.ToolChoice = setting
+	}
+}
+
+// WithToolChoiceByFunction forces the model to call the named function.
+func WithToolChoiceByFunction(name string) CreateOpt {
+	return func(r *request) {
+		r.ToolChoice = map[string]string{"type": "function", "name": name}
+	}
+}
+
+// WithParallelToolCalls sets whether the model may call tools in parallel.
+func WithParallelToolCalls(parallel bool) CreateOpt {
+	return func(r *request) {
+		r.ParallelToolCalls = &parallel
+	}
+}
+
+// WithTemperature sets the temperature for the response request.
+func WithTemperature(temperature float64) CreateOpt {
+	return func(r *request) {
+		r.Temperature = &temperature
+	}
+}
+
+// WithTopP sets the top p for the response request.
+func WithTopP(topP float64) CreateOpt {
+	return func(r *request) {
+		r.TopP = &topP
+	}
+}
+
+// WithMaxOutputTokens sets the maximum number of output tokens, including
+// reasoning tokens.
+func WithMaxOutputTokens(maxOutputTokens int) CreateOpt {
+	return func(r *request) {
+		r.MaxOutputTokens = &maxOutputTokens
+	}
+}
+
+// WithReasoning sets the reasoning configuration for reasoning models.
+func WithReasoning(reasoning ReasoningConfig) CreateOpt {
+	return func(r *request) {
+		r.Reasoning = &reasoning
+	}
+}
+
+// WithInclude sets additional output data to include in the response, such as
+// "reasoning.encrypted_content".
+func WithInclude(include ...string) CreateOpt {
+	return func(r *request) {
+		r.Include = include
+	}
+}
+
+// WithStore sets whether the response is stored for later retrieval.
+func WithStore(store bool) CreateOpt {
+	return func(r *request) {
+		r.Store = &store
+	}
+}
+
+// WithBackground sets whether the response is generated in the background.
+// Background responses can be polled with Retrieve and canceled with Cancel.
+func WithBackground(background bool) CreateOpt {
+	return func(r *request) {
+		r.Background = &background
+	}
+}
+
+// WithMetadata sets the metadata for the response.
+func WithMetadata(metadata map[string]string) CreateOpt {
+	return func(r *request) {
+		r.Metadata = metadata
+	}
+}
+
+// WithUser sets the user for the response request.
+func WithUser(user string) CreateOpt {
+	return func(r *request) {
+		r.User = &user
+	}
+}
+
+// WithAPIKey sets the API key for the response request.
+func WithAPIKey(apiKey string) CreateOpt {
+	return func(r *request) {
+		r.apiKey = apiKey
+	}
+}
+
+// Response statuses.
+const (
+	StatusQueued     = "queued"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+	StatusFailed     = "failed"
+	StatusIncomplete = "incomplete"
+	StatusCancelled  = "cancelled"
+)
+
+// A Response is a model response.
+type Response struct {
+	ID                 string             `json:"id"`
+	Object             string             `json:"object"`
+	CreatedAt          int64              `json:"created_at"`
+	Status             string             `json:"status"`
+	Error              *Error             `json:"error"`
+	IncompleteDetails  *IncompleteDetails `json:"incomplete_details"`
+	Model              string             `json:"model"`
+	Instructions       *string            `json:"instructions"`
+	Output             []Item             `json:"output"`
+	PreviousResponseID *string            `json:"previous_response_id"`
+	Metadata           map[string]string  `json:"metadata"`
+	Usage              *Usage             `json:"usage"`
+}
+
+// OutputText returns the concatenated text of every output message.
+func (r *Response) OutputText() string {
+	var text string
+
+	for _, item := range r.Output {
+		if item.Message != nil {
+			text += item.Message.Text()
+		}
+	}
+
+	return text
+}
+
+// FunctionCalls returns the function calls in the response's output.
+func (r *Response) FunctionCalls() []FunctionCall {
+	var calls []FunctionCall
+
+	for _, item := range r.Output {
+		if item.FunctionCall != nil {
+			calls = append(calls, *item.FunctionCall)
+		}
+	}
+
+	return calls
+}
+
+// An Error is an error that caused a response to fail.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// IncompleteDetails describes why a response is incomplete.
+type IncompleteDetails struct {
+	Reason string `json:"reason"`
+}
+
+// A Usage defines usage statistics.
+type Usage struct {
+	InputTokens         int                 `json:"input_tokens"`
+	InputTokensDetails  InputTokensDetails  `json:"input_tokens_details"`
+	OutputTokens        int                 `json:"output_tokens"`
+	OutputTokensDetails OutputTokensDetails `json:"output_tokens_details"`
+	TotalTokens         int                 `json:"total_tokens"`
+}
+
+// InputTokensDetails breaks down input token usage.
+type InputTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// OutputTokensDetails breaks down output token usage.
+type OutputTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
+// A DeleteResponse is a response to a response deletion request.
+type DeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
+// An InputItemsResponse is a page of a response's input items.
+type InputItemsResponse = service.Page[Item]
+
+// ListOpt is a functional option for configuring an input item list request.
+type ListOpt = service.ListOpt
+
+// WithLimit sets the maximum number of input items in a page.
+func WithLimit(limit int) ListOpt {
+	return service.WithLimit(limit)
+}
+
+// WithOrder sets the order ("asc" or "desc") of the listed input items.
+func WithOrder(order string) ListOpt {
+	return service.WithOrder(order)
+}
+
+// WithAfter sets the cursor to list input items after, which is the LastID of
+// the previous page.
+func WithAfter(after string) ListOpt {
+	return service.WithAfter(after)
+}
+
+// Service is a service wrapping an OpenAI-compatible responses API.
+type Service service.Service
+
+// Create creates a model response for the given input items.
+func (h *Service) Create(
+	ctx context.Context,
+	model string,
+	input []Item,
+	opts ...CreateOpt,
+) (*Response, error) {
+	req := newRequest(model, input, opts)
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/responses", req,
+		service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating response request: %w", err)
+	}
+
+	var resp Response
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing response request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// CreateStreaming creates a streaming model response for the given input
+// items.
+//
+// It returns a StreamingResponse. The caller is responsible for closing it.
+func (h *Service) CreateStreaming(
+	ctx context.Context,
+	model string,
+	input []Item,
+	opts ...CreateOpt,
+) (*StreamingResponse, error) {
+	req := newRequest(model, input, opts)
+
+	stream := true
+	req.Stream = &stream
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/responses", req,
+		service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating response request: %w", err)
+	}
+
+	httpResp, err := h.Client.Do(httpReq, nil) //nolint: bodyclose // False positive.
+	if err != nil {
+		return nil, fmt.Errorf("error performing response request: %w", err)
+	}
+
+	return newStreamingResponse(httpResp.Body), nil
+}
+
+func newRequest(model string, input []Item, opts []CreateOpt) request {
+	req := request{Model: model, Input: input}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	return req
+}
+
+// Retrieve retrieves a stored response by its ID.
+func (h *Service) Retrieve(ctx context.Context, id string) (*Response, error) {
+	return h.get(ctx, http.MethodGet, "/responses/"+url.PathEscape(id))
+}
+
+// Cancel cancels a background response.
+func (h *Service) Cancel(ctx context.Context, id string) (*Response, error) {
+	return h.get(ctx, http.MethodPost, "/responses/"+url.PathEscape(id)+"/cancel")
+}
+
+func (h *Service) get(ctx context.Context, method, path string) (*Response, error) {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, method, path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating response request: %w", err)
+	}
+
+	var resp Response
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing response request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// Delete deletes a stored response by its ID.
+func (h *Service) Delete(ctx context.Context, id string) (*DeleteResponse, error) {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodDelete, "/responses/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating response deletion request: %w", err)
+	}
+
+	var resp DeleteResponse
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing response deletion request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// ListInputItems lists a page of the input items of a stored response.
+func (h *Service) ListInputItems(ctx context.Context, id string, opts ...ListOpt) (*InputItemsResponse, error) {
+	resp, err := service.ListPage[Item](ctx, &h.Client, inputItemsPath(id), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing response input items: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAllInputItems returns an iterator over every input item of a stored
+// response, following pagination cursors.
+func (h *Service) ListAllInputItems(ctx context.Context, id string, opts ...ListOpt) iter.Seq2[Item, error] {
+	return service.NewPager[Item](&h.Client, inputItemsPath(id), Item.ID, opts...).All(ctx)
+}
+
+func inputItemsPath(id string) string {
+	return "/responses/" + url.PathEscape(id) + "/input_items"
+}
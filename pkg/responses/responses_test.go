@@ -0,0 +1,194 @@
+package responses_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/responses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_CreateResponse(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		r := &http.Response{}
+		r.StatusCode = http.StatusOK
+		r.Body = httptesting.NewTestBody(strings.NewReader(`{
+			"id": "resp_1",
+			"object": "response",
+			"status": "completed",
+			"output": [
+				{"type": "reasoning", "id": "rs_1", "summary": [{"type": "summary_text", "text": "Thinking"}]},
+				{"type": "message", "id": "msg_1", "role": "assistant", "status": "completed",
+					"content": [{"type": "output_text", "text": "Hello!", "annotations": []}]},
+				{"type": "function_call", "id": "fc_1", "call_id": "call_1", "name": "get_weather", "arguments": "{}"},
+				{"type": "image_generation_call", "id": "ig_1"}
+			],
+			"usage": {"input_tokens": 5, "output_tokens": 7, "total_tokens": 12,
+				"output_tokens_details": {"reasoning_tokens": 3}}
+		}`))
+
+		return r, nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*responses.Service)(svc)
+
+	resp, err := c.Create(
+		context.Background(),
+		"gpt-4.1",
+		[]responses.Item{
+			responses.NewMessage(responses.RoleUser, "Hi"),
+			responses.NewFunctionCallOutput("call_0", `{"ok":true}`),
+		},
+		responses.WithPreviousResponseID("resp_0"),
+		responses.WithTools(responses.NewWebSearchTool()),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"model": "gpt-4.1",
+		"input": []any{
+			map[string]any{
+				"type": "message", "role": "user",
+				"content": []any{map[string]any{"type": "input_text", "text": "Hi"}},
+			},
+			map[string]any{"type": "function_call_output", "call_id": "call_0", "output": `{"ok":true}`},
+		},
+		"previous_response_id": "resp_0",
+		"tools":                []any{map[string]any{"type": "web_search"}},
+	}, body)
+
+	assert.Equal(t, "Hello!", resp.OutputText())
+	assert.Equal(t, []responses.FunctionCall{
+		{ID: "fc_1", CallID: "call_1", Name: "get_weather", Arguments: "{}"},
+	}, resp.FunctionCalls())
+	assert.Equal(t, "Thinking", resp.Output[0].Reasoning.Summary[0].Text)
+	assert.Equal(t, 3, resp.Usage.OutputTokensDetails.ReasoningTokens)
+
+	unknown := resp.Output[3]
+	assert.Equal(t, "image_generation_call", unknown.Type)
+	assert.Equal(t, "ig_1", unknown.ID())
+
+	b, err := json.Marshal(unknown)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "image_generation_call", "id": "ig_1"}`, string(b))
+}
+
+func TestHTTPClient_CreateStreamingResponse(t *testing.T) {
+	t.Parallel()
+
+	sse := `event: response.created
+data: {"type":"response.created","response":{"id":"resp_1","status":"in_progress"}}
+
+event: response.output_text.delta
+data: {"type":"response.output_text.delta","item_id":"msg_1","delta":"Hello"}
+
+: keep-alive
+
+event: response.output_text.delta
+data: {"item_id":"msg_1","delta":" world"}
+
+event: response.completed
+data: {"type":"response.completed","response":{"id":"resp_1","status":"completed"}}
+
+`
+
+	r := &http.Response{}
+	r.StatusCode = http.StatusOK
+	r.Body = httptesting.NewTestBody(strings.NewReader(sse))
+	doer := httptesting.NewTestDoer(r, nil)
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", &doer)
+	c := (*responses.Service)(svc)
+
+	stream, err := c.CreateStreaming(context.Background(), "gpt-4.1",
+		[]responses.Item{responses.NewMessage(responses.RoleUser, "Hi")})
+	require.NoError(t, err)
+
+	var (
+		text  string
+		final *responses.Response
+	)
+
+	for {
+		evt, err := stream.Next()
+		if errors.Is(err, responses.ErrStreamDone) {
+			break
+		}
+
+		require.NoError(t, err)
+
+		switch evt.Type {
+		case responses.EventOutputTextDelta:
+			text += evt.Delta
+		case responses.EventResponseCompleted:
+			final = evt.Response
+		}
+	}
+
+	assert.Equal(t, "Hello world", text)
+	require.NotNil(t, final)
+	assert.Equal(t, responses.StatusCompleted, final.Status)
+	require.NoError(t, stream.Close())
+}
+
+func TestHTTPClient_ListAllInputItems(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		paths = append(paths, req.URL.String())
+
+		body := `{"data": [{"type": "message", "id": "msg_1", "role": "user", "content": []}], "has_more": true}`
+		if req.URL.Query().Get("after") != "" {
+			body = `{"data": [{"type": "function_call_output", "id": "fco_1", "call_id": "c", "output": ""}], "has_more": false}`
+		}
+
+		r := &http.Response{}
+		r.StatusCode = http.StatusOK
+		r.Body = httptesting.NewTestBody(strings.NewReader(body))
+
+		return r, nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*responses.Service)(svc)
+
+	var ids []string
+
+	for item, err := range c.ListAllInputItems(context.Background(), "resp_1") {
+		require.NoError(t, err)
+
+		ids = append(ids, item.ID())
+	}
+
+	assert.Equal(t, []string{"msg_1", "fco_1"}, ids)
+	assert.Equal(t, []string{
+		openai.DefaultBaseURL.String() + "/responses/resp_1/input_items",
+		openai.DefaultBaseURL.String() + "/responses/resp_1/input_items?after=msg_1",
+	}, paths)
+}
+
+func TestItem_MarshalJSONEmpty(t *testing.T) {
+	t.Parallel()
+
+	_, err := json.Marshal(responses.Item{Type: responses.ItemTypeMessage})
+	require.ErrorIs(t, err, responses.ErrEmptyItem)
+}
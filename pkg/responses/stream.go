@@ -0,0 +1,128 @@
+package responses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// Streaming event types.
+const (
+	EventResponseCreated            = "response.created"
+	EventResponseInProgress         = "response.in_progress"
+	EventResponseCompleted          = "response.completed"
+	EventResponseFailed             = "response.failed"
+	EventResponseIncomplete         = "response.incomplete"
+	EventOutputItemAdded            = "response.output_item.added"
+	EventOutputItemDone             = "response.output_item.done"
+	EventContentPartAdded           = "response.content_part.added"
+	EventContentPartDone            = "response.content_part.done"
+	EventOutputTextDelta            = "response.output_text.delta"
+	EventOutputTextDone             = "response.output_text.done"
+	EventRefusalDelta               = "response.refusal.delta"
+	EventRefusalDone                = "response.refusal.done"
+	EventFunctionCallArgumentsDelta = "response.function_call_arguments.delta"
+	EventFunctionCallArgumentsDone  = "response.function_call_arguments.done"
+	EventReasoningSummaryTextDelta  = "response.reasoning_summary_text.delta"
+	EventReasoningSummaryTextDone   = "response.reasoning_summary_text.done"
+	EventWebSearchCallCompleted     = "response.web_search_call.completed"
+	EventFileSearchCallCompleted    = "response.file_search_call.completed"
+	EventError                      = "error"
+)
+
+// A StreamEvent is a single event in a streaming response.
+//
+// Which fields are set depends on Type. Response is set for the
+// "response.*" lifecycle events, Item for output item events, Part for
+// content part events, and Delta for delta events.
+type StreamEvent struct {
+	Type           string    `json:"type"`
+	SequenceNumber int       `json:"sequence_number"`
+	Response       *Response `json:"response,omitempty"`
+	OutputIndex    int       `json:"output_index"`
+	ContentIndex   int       `json:"content_index"`
+	SummaryIndex   int       `json:"summary_index"`
+	ItemID         string    `json:"item_id,omitempty"`
+	Item           *Item     `json:"item,omitempty"`
+	Part           *Content  `json:"part,omitempty"`
+	Delta          string    `json:"delta,omitempty"`
+	Text           string    `json:"text,omitempty"`
+	Arguments      string    `json:"arguments,omitempty"`
+	Code           string    `json:"code,omitempty"`
+	Message        string    `json:"message,omitempty"`
+
+	// Raw holds the event's JSON data, for event types this package does not
+	// model.
+	Raw json.RawMessage `json:"-"`
+}
+
+// Terminal reports whether the event ends the stream.
+func (e *StreamEvent) Terminal() bool {
+	switch e.Type {
+	case EventResponseCompleted, EventResponseFailed, EventResponseIncomplete, EventError:
+		return true
+	}
+
+	return false
+}
+
+// ErrStreamDone is returned when the stream is done (after a terminal event
+// such as "response.completed").
+var ErrStreamDone = errors.New("response stream is done")
+
+// A StreamingResponse is a streaming response to a response request. It reads
+// an io.ReadCloser and emits StreamEvents.
+//
+// The caller is responsible for closing the stream (`stream.Close()`).
+type StreamingResponse struct {
+	stream *service.EventStream
+	done   bool
+}
+
+// Next returns the next event in the streaming response.
+//
+// After a terminal event has been returned, it returns ErrStreamDone.
+func (s *StreamingResponse) Next() (*StreamEvent, error) {
+	if s.done {
+		return nil, ErrStreamDone
+	}
+
+	evt, err := s.stream.Next()
+	if err != nil {
+		if errors.Is(err, service.ErrStreamDone) {
+			s.done = true
+
+			return nil, ErrStreamDone
+		}
+
+		return nil, err //nolint: wrapcheck // Errors are already wrapped.
+	}
+
+	var e StreamEvent
+	if err := json.Unmarshal(evt.Data, &e); err != nil {
+		return nil, fmt.Errorf("error unmarshaling stream event: %w", err)
+	}
+
+	// Some compatible servers only name the event, and omit the type from its
+	// data.
+	if evt.Type != "" {
+		e.Type = evt.Type
+	}
+
+	e.Raw = evt.Data
+	s.done = e.Terminal()
+
+	return &e, nil
+}
+
+// Close closes the stream.
+func (s *StreamingResponse) Close() error {
+	return s.stream.Close() //nolint: wrapcheck // Errors are already wrapped.
+}
+
+func newStreamingResponse(rc io.ReadCloser) *StreamingResponse {
+	return &StreamingResponse{stream: service.NewEventStream(rc)}
+}
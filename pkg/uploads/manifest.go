@@ -0,0 +1,137 @@
+package uploads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// A Manifest records the progress of a multipart upload, so that an
+// interrupted upload can be resumed without re-sending completed parts.
+type Manifest struct {
+	UploadID string `json:"upload_id"`
+	Filename string `json:"filename"`
+	Purpose  string `json:"purpose"`
+	Bytes    int64  `json:"bytes"`
+	PartSize int64  `json:"part_size"`
+
+	// ExpiresAt is the Unix time at which the upload expires, after which
+	// it can't be resumed.
+	ExpiresAt int64 `json:"expires_at"`
+
+	Parts []ManifestPart `json:"parts"`
+}
+
+// A ManifestPart records a single part of an upload.
+//
+// ID is empty until the part has been uploaded.
+type ManifestPart struct {
+	ID  string `json:"id,omitempty"`
+	MD5 string `json:"md5"`
+}
+
+// resumeMargin is how long before it expires an upload is no longer resumed,
+// to leave time to finish it.
+const resumeMargin = 5 * time.Minute
+
+// matches reports whether the manifest describes an unexpired upload of the
+// same file, for the same purpose, with the same part layout.
+func (m *Manifest) matches(filename, purpose string, size, partSize int64, parts []ManifestPart, now time.Time) bool {
+	if m.UploadID == "" || m.Filename != filename || m.Purpose != purpose || m.Bytes != size ||
+		m.PartSize != partSize {
+		return false
+	}
+
+	if !now.Add(resumeMargin).Before(time.Unix(m.ExpiresAt, 0)) {
+		return false
+	}
+
+	return len(m.Parts) == len(parts)
+}
+
+// A ManifestStore persists a Manifest between attempts to upload a file.
+type ManifestStore interface {
+	// Load loads the manifest. It returns nil and no error if there is none.
+	Load() (*Manifest, error)
+
+	// Save saves the manifest.
+	Save(m *Manifest) error
+
+	// Delete deletes the manifest once the upload is complete.
+	Delete() error
+}
+
+// A FileManifestStore is a ManifestStore that persists a manifest as a JSON
+// file at Path.
+type FileManifestStore struct {
+	Path string
+}
+
+var _ ManifestStore = FileManifestStore{}
+
+// Load implements the ManifestStore interface.
+func (s FileManifestStore) Load() (*Manifest, error) {
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil //nolint: nilnil // No manifest is not an error.
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("error decoding manifest: %w", err)
+	}
+
+	return &m, nil
+}
+
+// Save implements the ManifestStore interface.
+//
+// The manifest is written to a temporary file and renamed into place, so a
+// crash mid-write never leaves a corrupt manifest behind.
+func (s FileManifestStore) Save(m *Manifest) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("error encoding manifest: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return fmt.Errorf("error creating manifest: %w", err)
+	}
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()           //nolint: errcheck,gosec // Already failing.
+		os.Remove(tmp.Name()) //nolint: errcheck,gosec // Already failing.
+
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name()) //nolint: errcheck,gosec // Already failing.
+
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+
+	return nil
+}
+
+// Delete implements the ManifestStore interface.
+func (s FileManifestStore) Delete() error {
+	if err := os.Remove(s.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("error deleting manifest: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,368 @@
+package uploads
+
+import (
+	"context"
+	"crypto/md5" //nolint: gosec // MD5 is the checksum the API accepts.
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/files"
+)
+
+const (
+	// MaxPartSize is the largest part the API accepts.
+	MaxPartSize int64 = 64 << 20
+
+	// DefaultPartSize is the default size of each part.
+	DefaultPartSize = MaxPartSize
+
+	// DefaultConcurrency is the default number of parts uploaded at once.
+	DefaultConcurrency = 4
+
+	// DefaultMaxRetries is the default number of times a failed part is
+	// retried.
+	DefaultMaxRetries = 3
+
+	// DefaultRetryBackoff is the default delay before the first retry of a
+	// failed part. It doubles on each subsequent retry.
+	DefaultRetryBackoff = time.Second
+)
+
+// ErrNoFile is returned when a completed upload does not include a file.
+var ErrNoFile = errors.New("completed upload has no file")
+
+// ErrInvalidPartSize is returned by UploadFile when the part size is not
+// positive or is larger than MaxPartSize.
+var ErrInvalidPartSize = errors.New("invalid part size")
+
+type uploadFileRequest struct {
+	apiKey       string
+	createOpts   []CreateOpt
+	partSize     int64
+	concurrency  int
+	maxRetries   int
+	retryBackoff time.Duration
+	store        ManifestStore
+}
+
+// UploadFileOpt is a functional option for configuring UploadFile.
+type UploadFileOpt func(*uploadFileRequest)
+
+// WithPartSize sets the size of each part, which must be positive and at most
+// MaxPartSize. The default is DefaultPartSize.
+func WithPartSize(size int64) UploadFileOpt {
+	return func(r *uploadFileRequest) {
+		r.partSize = size
+	}
+}
+
+// WithConcurrency sets the number of parts uploaded at once. The default is
+// DefaultConcurrency.
+func WithConcurrency(n int) UploadFileOpt {
+	return func(r *uploadFileRequest) {
+		r.concurrency = n
+	}
+}
+
+// WithMaxRetries sets the number of times a failed part is retried. The
+// default is DefaultMaxRetries.
+func WithMaxRetries(n int) UploadFileOpt {
+	return func(r *uploadFileRequest) {
+		r.maxRetries = n
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry of a failed part.
+// The default is DefaultRetryBackoff.
+func WithRetryBackoff(backoff time.Duration) UploadFileOpt {
+	return func(r *uploadFileRequest) {
+		r.retryBackoff = backoff
+	}
+}
+
+// WithManifestStore sets a store used to persist upload progress.
+//
+// If the store holds a manifest for the same file and purpose, the upload it
+// describes is resumed, and parts whose checksums still match are not uploaded
+// again. Uploads that have expired, or expire within a few minutes, are not
+// resumed; a new upload is created instead.
+func WithManifestStore(store ManifestStore) UploadFileOpt {
+	return func(r *uploadFileRequest) {
+		r.store = store
+	}
+}
+
+// WithCreateOpts sets options for the request that creates the upload. An API
+// key set with WithAPIKey is used for every request of the upload.
+func WithCreateOpts(opts ...CreateOpt) UploadFileOpt {
+	return func(r *uploadFileRequest) {
+		r.createOpts = opts
+	}
+}
+
+// UploadFile uploads size bytes read from r as a single file, using as many
+// parts as needed.
+//
+// Parts are uploaded concurrently, and each one is retried on network errors,
+// rate limits, and server errors. The MD5 checksum of the whole file is sent
+// with the completion request for the API to verify.
+func (h *Service) UploadFile(
+	ctx context.Context,
+	r io.ReaderAt,
+	size int64,
+	filename string,
+	purpose string,
+	mimeType string,
+	opts ...UploadFileOpt,
+) (*files.File, error) {
+	req := uploadFileRequest{
+		partSize:     DefaultPartSize,
+		concurrency:  DefaultConcurrency,
+		maxRetries:   DefaultMaxRetries,
+		retryBackoff: DefaultRetryBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	if req.partSize <= 0 || req.partSize > MaxPartSize {
+		return nil, fmt.Errorf("%w: %d bytes (must be between 1 and %d)", ErrInvalidPartSize, req.partSize, MaxPartSize)
+	}
+
+	var create createRequest
+	for _, opt := range req.createOpts {
+		opt(&create)
+	}
+
+	req.apiKey = create.apiKey
+
+	parts, sum, err := checksumParts(r, size, req.partSize)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := h.loadOrCreateManifest(ctx, &req, filename, purpose, size, mimeType, parts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.uploadParts(ctx, r, size, &req, manifest); err != nil {
+		return nil, err
+	}
+
+	partIDs := make([]string, len(manifest.Parts))
+	for i, part := range manifest.Parts {
+		partIDs[i] = part.ID
+	}
+
+	upload, err := h.Complete(ctx, manifest.UploadID, partIDs, WithMD5(sum), WithCompleteAPIKey(req.apiKey))
+	if err != nil {
+		return nil, err
+	}
+
+	if req.store != nil {
+		if err := req.store.Delete(); err != nil {
+			return nil, err //nolint: wrapcheck // Store errors are returned as-is.
+		}
+	}
+
+	if upload.File == nil {
+		return nil, ErrNoFile
+	}
+
+	return upload.File, nil
+}
+
+// checksumParts reads r once, returning the MD5 checksum of each part and of
+// the whole file.
+func checksumParts(r io.ReaderAt, size, partSize int64) ([]ManifestPart, string, error) {
+	whole := md5.New() //nolint: gosec // MD5 is the checksum the API accepts.
+	parts := make([]ManifestPart, 0, (size+partSize-1)/partSize)
+
+	for offset := int64(0); offset < size; offset += partSize {
+		part := md5.New() //nolint: gosec // MD5 is the checksum the API accepts.
+
+		section := io.NewSectionReader(r, offset, min(partSize, size-offset))
+		if _, err := io.Copy(io.MultiWriter(whole, part), section); err != nil {
+			return nil, "", fmt.Errorf("error reading part at offset %d: %w", offset, err)
+		}
+
+		parts = append(parts, ManifestPart{MD5: hex.EncodeToString(part.Sum(nil))})
+	}
+
+	return parts, hex.EncodeToString(whole.Sum(nil)), nil
+}
+
+func (h *Service) loadOrCreateManifest(
+	ctx context.Context,
+	req *uploadFileRequest,
+	filename string,
+	purpose string,
+	size int64,
+	mimeType string,
+	parts []ManifestPart,
+) (*Manifest, error) {
+	if req.store != nil {
+		manifest, err := req.store.Load()
+		if err != nil {
+			return nil, err //nolint: wrapcheck // Store errors are returned as-is.
+		}
+
+		if manifest != nil && manifest.matches(filename, purpose, size, req.partSize, parts, time.Now()) {
+			// Only keep parts whose content is unchanged since they were uploaded.
+			for i, part := range manifest.Parts {
+				if part.MD5 == parts[i].MD5 {
+					parts[i].ID = part.ID
+				}
+			}
+
+			manifest.Parts = parts
+
+			return manifest, nil
+		}
+	}
+
+	upload, err := h.Create(ctx, filename, purpose, size, mimeType, req.createOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		UploadID:  upload.ID,
+		Filename:  filename,
+		Purpose:   purpose,
+		Bytes:     size,
+		PartSize:  req.partSize,
+		ExpiresAt: upload.ExpiresAt,
+		Parts:     parts,
+	}
+
+	if req.store != nil {
+		if err := req.store.Save(manifest); err != nil {
+			return nil, err //nolint: wrapcheck // Store errors are returned as-is.
+		}
+	}
+
+	return manifest, nil
+}
+
+func (h *Service) uploadParts(
+	ctx context.Context,
+	r io.ReaderAt,
+	size int64,
+	req *uploadFileRequest,
+	manifest *Manifest,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+
+	jobs := make(chan int)
+
+	for n := 0; n < max(req.concurrency, 1); n++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				offset := int64(i) * req.partSize
+				id, err := h.addPartWithRetry(ctx, manifest.UploadID, r, offset, min(req.partSize, size-offset), req)
+
+				mu.Lock()
+
+				if err == nil {
+					manifest.Parts[i].ID = id
+
+					if req.store != nil {
+						err = req.store.Save(manifest)
+					}
+				}
+
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("error uploading part %d: %w", i, err)
+					cancel()
+				}
+
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for i, part := range manifest.Parts {
+		if part.ID != "" {
+			continue
+		}
+
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("error uploading parts: %w", err)
+	}
+
+	return nil
+}
+
+func (h *Service) addPartWithRetry(
+	ctx context.Context,
+	uploadID string,
+	r io.ReaderAt,
+	offset int64,
+	length int64,
+	req *uploadFileRequest,
+) (string, error) {
+	for attempt := 0; ; attempt++ {
+		part, err := h.AddPart(ctx, uploadID, io.NewSectionReader(r, offset, length), WithPartAPIKey(req.apiKey))
+		if err == nil {
+			return part.ID, nil
+		}
+
+		if attempt >= req.maxRetries || !isRetryable(ctx, err) {
+			return "", err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("error waiting to retry part: %w", ctx.Err())
+		case <-time.After(req.retryBackoff << attempt):
+		}
+	}
+}
+
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var statusErr service.UnexpectedStatusCodeError
+	if errors.As(err, &statusErr) {
+		return statusErr.Actual == http.StatusTooManyRequests || statusErr.Actual >= http.StatusInternalServerError
+	}
+
+	return true
+}
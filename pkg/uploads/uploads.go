@@ -0,0 +1,224 @@
+// Package uploads provides a client for the OpenAI uploads API, which is used
+// to upload files larger than a single request allows.
+package uploads
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/files"
+)
+
+// Upload statuses.
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+// An Upload is an intermediate object that parts can be added to.
+type Upload struct {
+	ID        string      `json:"id"`
+	Object    string      `json:"object"`
+	Bytes     int64       `json:"bytes"`
+	CreatedAt int64       `json:"created_at"`
+	ExpiresAt int64       `json:"expires_at"`
+	Filename  string      `json:"filename"`
+	Purpose   string      `json:"purpose"`
+	Status    string      `json:"status"`
+	File      *files.File `json:"file,omitempty"`
+}
+
+// A Part is a chunk of bytes added to an Upload.
+type Part struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	CreatedAt int64  `json:"created_at"`
+	UploadID  string `json:"upload_id"`
+}
+
+type expiresAfter struct {
+	Anchor  string `json:"anchor"`
+	Seconds int    `json:"seconds"`
+}
+
+type createRequest struct {
+	apiKey string
+
+	Filename     string        `json:"filename"`
+	Purpose      string        `json:"purpose"`
+	Bytes        int64         `json:"bytes"`
+	MimeType     string        `json:"mime_type"`
+	ExpiresAfter *expiresAfter `json:"expires_after,omitempty"`
+}
+
+// CreateOpt is a functional option for configuring an upload creation request.
+type CreateOpt func(*createRequest)
+
+// WithExpiresAfter sets the expiration policy of the file created by the
+// upload, relative to the given anchor (e.g. "created_at").
+func WithExpiresAfter(anchor string, after time.Duration) CreateOpt {
+	return func(r *createRequest) {
+		r.ExpiresAfter = &expiresAfter{Anchor: anchor, Seconds: int(after.Seconds())}
+	}
+}
+
+// WithAPIKey sets the API key for the upload creation request.
+//
+// When passed to UploadFile with WithCreateOpts, the key is used for every
+// request of the upload.
+func WithAPIKey(apiKey string) CreateOpt {
+	return func(r *createRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+type addPartRequest struct {
+	apiKey string
+}
+
+// AddPartOpt is a functional option for configuring an upload part request.
+type AddPartOpt func(*addPartRequest)
+
+// WithPartAPIKey sets the API key for the upload part request.
+func WithPartAPIKey(apiKey string) AddPartOpt {
+	return func(r *addPartRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+type completeRequest struct {
+	apiKey string
+
+	PartIDs []string `json:"part_ids"`
+	MD5     *string  `json:"md5,omitempty"`
+}
+
+// CompleteOpt is a functional option for configuring an upload completion
+// request.
+type CompleteOpt func(*completeRequest)
+
+// WithMD5 sets the hex-encoded MD5 checksum of the whole file, which the API
+// verifies against the uploaded bytes.
+func WithMD5(md5 string) CompleteOpt {
+	return func(r *completeRequest) {
+		r.MD5 = &md5
+	}
+}
+
+// WithCompleteAPIKey sets the API key for the upload completion request.
+func WithCompleteAPIKey(apiKey string) CompleteOpt {
+	return func(r *completeRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// Service is a service wrapping an OpenAI-compatible uploads API.
+type Service service.Service
+
+// Create creates an upload that parts can be added to.
+//
+// The size is the total number of bytes that will be uploaded.
+func (h *Service) Create(
+	ctx context.Context,
+	filename string,
+	purpose string,
+	size int64,
+	mimeType string,
+	opts ...CreateOpt,
+) (*Upload, error) {
+	req := createRequest{Filename: filename, Purpose: purpose, Bytes: size, MimeType: mimeType}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost, "/uploads", req,
+		service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating upload request: %w", err)
+	}
+
+	var resp Upload
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing upload request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// AddPart adds a part to an upload. Parts may be added in parallel, and their
+// order is determined when the upload is completed.
+func (h *Service) AddPart(ctx context.Context, uploadID string, r io.Reader, opts ...AddPartOpt) (*Part, error) {
+	var req addPartRequest
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	form := &service.Form{}
+	form.AddFile("data", "part", r)
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost,
+		"/uploads/"+url.PathEscape(uploadID)+"/parts", form, service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating upload part request: %w", err)
+	}
+
+	var resp Part
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing upload part request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// Complete completes an upload, creating a File from its parts in the order
+// of partIDs.
+func (h *Service) Complete(
+	ctx context.Context,
+	uploadID string,
+	partIDs []string,
+	opts ...CompleteOpt,
+) (*Upload, error) {
+	req := completeRequest{PartIDs: partIDs}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost,
+		"/uploads/"+url.PathEscape(uploadID)+"/complete", req, service.WithAPIKey(req.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating upload completion request: %w", err)
+	}
+
+	var resp Upload
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing upload completion request: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// Cancel cancels an upload. No parts may be added after it is cancelled.
+func (h *Service) Cancel(ctx context.Context, uploadID string) (*Upload, error) {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, http.MethodPost,
+		"/uploads/"+url.PathEscape(uploadID)+"/cancel", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating upload cancellation request: %w", err)
+	}
+
+	var resp Upload
+	if _, err := h.Client.Do(httpReq, &resp); err != nil { //nolint: bodyclose // False positive.
+		return nil, fmt.Errorf("error performing upload cancellation request: %w", err)
+	}
+
+	return &resp, nil
+}
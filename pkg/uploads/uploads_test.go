@@ -0,0 +1,247 @@
+package uploads_test
+
+import (
+	"context"
+	"crypto/md5" //nolint: gosec // MD5 is the checksum the API accepts.
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/uploads"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUploadsAPI struct {
+	mu       sync.Mutex
+	keys     []string
+	created  int
+	parts    map[string]string
+	failures map[string]int
+	complete struct {
+		PartIDs []string `json:"part_ids"`
+		MD5     string   `json:"md5"`
+	}
+}
+
+func newResponse(status int, body string) *http.Response {
+	r := &http.Response{}
+	r.StatusCode = status
+	r.Body = httptesting.NewTestBody(strings.NewReader(body))
+
+	return r
+}
+
+func (f *fakeUploadsAPI) Do(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.keys = append(f.keys, req.Header.Get("Authorization"))
+
+	switch {
+	case req.URL.Path == "/v1/uploads":
+		f.created++
+
+		return newResponse(http.StatusOK, fmt.Sprintf(`{"id": "upload_abc", "status": "pending", "expires_at": %d}`,
+			time.Now().Add(time.Hour).Unix())), nil
+
+	case strings.HasSuffix(req.URL.Path, "/parts"):
+		_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, err
+		}
+
+		part, err := multipart.NewReader(req.Body, params["boundary"]).NextPart()
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+
+		data := string(b)
+		if f.failures[data] > 0 {
+			f.failures[data]--
+
+			return newResponse(http.StatusServiceUnavailable, `{}`), nil
+		}
+
+		id := "part_" + data
+		f.parts[id] = data
+
+		return newResponse(http.StatusOK, fmt.Sprintf(`{"id": %q}`, id)), nil
+
+	case strings.HasSuffix(req.URL.Path, "/complete"):
+		if err := json.NewDecoder(req.Body).Decode(&f.complete); err != nil {
+			return nil, err
+		}
+
+		return newResponse(http.StatusOK, `{"id": "upload_abc", "status": "completed", "file": {"id": "file-abc"}}`), nil
+	}
+
+	return newResponse(http.StatusNotFound, `{}`), nil
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s)) //nolint: gosec // MD5 is the checksum the API accepts.
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestHTTPClient_UploadFile(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeUploadsAPI{parts: map[string]string{}, failures: map[string]int{"def": 2}}
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", api)
+	c := (*uploads.Service)(svc)
+
+	content := "abcdefghij"
+	file, err := c.UploadFile(
+		context.Background(),
+		strings.NewReader(content),
+		int64(len(content)),
+		"data.jsonl",
+		"batch",
+		"application/jsonl",
+		uploads.WithPartSize(3),
+		uploads.WithConcurrency(2),
+		uploads.WithRetryBackoff(0),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "file-abc", file.ID)
+	assert.Equal(t, 1, api.created)
+	assert.Equal(t, []string{"part_abc", "part_def", "part_ghi", "part_j"}, api.complete.PartIDs)
+	assert.Equal(t, md5Hex(content), api.complete.MD5)
+	assert.Empty(t, api.failures["def"])
+}
+
+func TestHTTPClient_UploadFileResume(t *testing.T) {
+	t.Parallel()
+
+	store := uploads.FileManifestStore{Path: filepath.Join(t.TempDir(), "manifest.json")}
+	require.NoError(t, store.Save(&uploads.Manifest{
+		UploadID:  "upload_abc",
+		Filename:  "data.jsonl",
+		Purpose:   "batch",
+		Bytes:     6,
+		PartSize:  3,
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		Parts: []uploads.ManifestPart{
+			{ID: "part_abc", MD5: md5Hex("abc")},
+			{ID: "part_old", MD5: md5Hex("old")},
+		},
+	}))
+
+	api := &fakeUploadsAPI{parts: map[string]string{}}
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", api)
+	c := (*uploads.Service)(svc)
+
+	content := "abcdef"
+	_, err := c.UploadFile(
+		context.Background(),
+		strings.NewReader(content),
+		int64(len(content)),
+		"data.jsonl",
+		"batch",
+		"application/jsonl",
+		uploads.WithPartSize(3),
+		uploads.WithManifestStore(store),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, api.created)
+	assert.Equal(t, map[string]string{"part_def": "def"}, api.parts)
+	assert.Equal(t, []string{"part_abc", "part_def"}, api.complete.PartIDs)
+
+	m, err := store.Load()
+	require.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestHTTPClient_UploadFileResumeExpired(t *testing.T) {
+	t.Parallel()
+
+	manifests := map[string]*uploads.Manifest{
+		"expired": {
+			UploadID: "upload_old", Filename: "data.jsonl", Purpose: "batch", Bytes: 3, PartSize: 3,
+			ExpiresAt: time.Now().Add(-time.Hour).Unix(),
+			Parts:     []uploads.ManifestPart{{ID: "part_abc", MD5: md5Hex("abc")}},
+		},
+		"other purpose": {
+			UploadID: "upload_old", Filename: "data.jsonl", Purpose: "fine-tune", Bytes: 3, PartSize: 3,
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+			Parts:     []uploads.ManifestPart{{ID: "part_abc", MD5: md5Hex("abc")}},
+		},
+	}
+
+	for name, manifest := range manifests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			store := uploads.FileManifestStore{Path: filepath.Join(t.TempDir(), "manifest.json")}
+			require.NoError(t, store.Save(manifest))
+
+			api := &fakeUploadsAPI{parts: map[string]string{}}
+
+			svc := service.New(openai.DefaultBaseURL, "api-key", api)
+			c := (*uploads.Service)(svc)
+
+			_, err := c.UploadFile(context.Background(), strings.NewReader("abc"), 3, "data.jsonl", "batch",
+				"application/jsonl", uploads.WithPartSize(3), uploads.WithManifestStore(store))
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, api.created)
+			assert.Equal(t, map[string]string{"part_abc": "abc"}, api.parts)
+		})
+	}
+}
+
+func TestHTTPClient_UploadFileAPIKey(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeUploadsAPI{parts: map[string]string{}}
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", api)
+	c := (*uploads.Service)(svc)
+
+	_, err := c.UploadFile(context.Background(), strings.NewReader("abcdef"), 6, "data.jsonl", "batch",
+		"application/jsonl", uploads.WithPartSize(3), uploads.WithCreateOpts(uploads.WithAPIKey("other-key")))
+	require.NoError(t, err)
+
+	// The create, both parts and the completion all use the key.
+	assert.Equal(t, []string{"Bearer other-key", "Bearer other-key", "Bearer other-key", "Bearer other-key"}, api.keys)
+}
+
+func TestHTTPClient_UploadFileInvalidPartSize(t *testing.T) {
+	t.Parallel()
+
+	api := &fakeUploadsAPI{parts: map[string]string{}}
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", api)
+	c := (*uploads.Service)(svc)
+
+	for _, size := range []int64{0, -1, uploads.MaxPartSize + 1} {
+		_, err := c.UploadFile(context.Background(), strings.NewReader("abc"), 3, "data.jsonl", "batch",
+			"application/jsonl", uploads.WithPartSize(size))
+		require.ErrorIs(t, err, uploads.ErrInvalidPartSize)
+	}
+
+	assert.Empty(t, api.keys)
+}
@@ -0,0 +1,323 @@
+package vectorstores
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/files"
+)
+
+// DefaultUploadConcurrency is the default number of files UploadFileBatch
+// uploads at once.
+const DefaultUploadConcurrency = 4
+
+// A FileBatch is a batch of files being attached to a vector store.
+type FileBatch struct {
+	ID            string     `json:"id"`
+	Object        string     `json:"object"`
+	CreatedAt     int64      `json:"created_at"`
+	VectorStoreID string     `json:"vector_store_id"`
+	Status        string     `json:"status"`
+	FileCounts    FileCounts `json:"file_counts"`
+}
+
+// Done reports whether the file batch is in a terminal state.
+func (b *FileBatch) Done() bool {
+	return b.Status != FileStatusInProgress
+}
+
+type fileBatchRequest struct {
+	apiKey      string
+	concurrency int
+
+	FileIDs          []string          `json:"file_ids"`
+	Attributes       Attributes        `json:"attributes,omitempty"`
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"`
+}
+
+// FileBatchOpt is a functional option for configuring a file batch request.
+type FileBatchOpt func(*fileBatchRequest)
+
+// WithBatchAttributes sets the attributes of every file in the batch.
+func WithBatchAttributes(attributes Attributes) FileBatchOpt {
+	return func(r *fileBatchRequest) {
+		r.Attributes = attributes
+	}
+}
+
+// WithBatchChunkingStrategy sets the chunking strategy of every file in the
+// batch.
+func WithBatchChunkingStrategy(strategy ChunkingStrategy) FileBatchOpt {
+	return func(r *fileBatchRequest) {
+		r.ChunkingStrategy = &strategy
+	}
+}
+
+// WithUploadConcurrency sets the number of files UploadFileBatch uploads at
+// once.
+func WithUploadConcurrency(concurrency int) FileBatchOpt {
+	return func(r *fileBatchRequest) {
+		r.concurrency = concurrency
+	}
+}
+
+// WithBatchAPIKey sets the API key for the file batch request, and for any
+// file uploads.
+func WithBatchAPIKey(apiKey string) FileBatchOpt {
+	return func(r *fileBatchRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// CreateFileBatch attaches uploaded files to a vector store as a batch.
+func (h *Service) CreateFileBatch(
+	ctx context.Context,
+	vectorStoreID string,
+	fileIDs []string,
+	opts ...FileBatchOpt,
+) (*FileBatch, error) {
+	req := newFileBatchRequest(fileIDs, opts)
+
+	var resp FileBatch
+	if err := h.do(ctx, http.MethodPost, batchesPath(vectorStoreID), req, req.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func newFileBatchRequest(fileIDs []string, opts []FileBatchOpt) fileBatchRequest {
+	req := fileBatchRequest{FileIDs: fileIDs, concurrency: DefaultUploadConcurrency}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	return req
+}
+
+// RetrieveFileBatch retrieves a file batch by its ID. Of the options, only
+// WithBatchAPIKey applies.
+func (h *Service) RetrieveFileBatch(
+	ctx context.Context,
+	vectorStoreID string,
+	batchID string,
+	opts ...FileBatchOpt,
+) (*FileBatch, error) {
+	req := newFileBatchRequest(nil, opts)
+
+	var resp FileBatch
+	if err := h.do(ctx, http.MethodGet, batchPath(vectorStoreID, batchID), nil, req.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// CancelFileBatch cancels an in-progress file batch. Of the options, only
+// WithBatchAPIKey applies.
+func (h *Service) CancelFileBatch(
+	ctx context.Context,
+	vectorStoreID string,
+	batchID string,
+	opts ...FileBatchOpt,
+) (*FileBatch, error) {
+	req := newFileBatchRequest(nil, opts)
+	path := batchPath(vectorStoreID, batchID) + "/cancel"
+
+	var resp FileBatch
+	if err := h.do(ctx, http.MethodPost, path, nil, req.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListFileBatchFiles lists a page of the files in a file batch.
+func (h *Service) ListFileBatchFiles(
+	ctx context.Context,
+	vectorStoreID string,
+	batchID string,
+	opts ...ListOpt,
+) (*FileListResponse, error) {
+	resp, err := service.ListPage[File](ctx, &h.Client, batchPath(vectorStoreID, batchID)+"/files", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing file batch files: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAllFileBatchFiles returns an iterator over every file in a file batch,
+// following pagination cursors.
+func (h *Service) ListAllFileBatchFiles(
+	ctx context.Context,
+	vectorStoreID string,
+	batchID string,
+	opts ...ListOpt,
+) iter.Seq2[File, error] {
+	return service.NewPager[File](&h.Client, batchPath(vectorStoreID, batchID)+"/files", nil, opts...).All(ctx)
+}
+
+// DefaultPollInterval is the interval WaitForFileBatch polls at when it is
+// given an interval that isn't positive.
+const DefaultPollInterval = time.Second
+
+// WaitForFileBatch polls a file batch every interval until it is in a
+// terminal state (see FileBatch.Done), or the context is done. If interval
+// isn't positive, DefaultPollInterval is used. Of the options, only
+// WithBatchAPIKey applies.
+//
+// A file batch that failed or was cancelled is returned without an error;
+// check its Status and FileCounts.
+func (h *Service) WaitForFileBatch(
+	ctx context.Context,
+	vectorStoreID string,
+	batchID string,
+	interval time.Duration,
+	opts ...FileBatchOpt,
+) (*FileBatch, error) {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		b, err := h.RetrieveFileBatch(ctx, vectorStoreID, batchID, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		if b.Done() {
+			return b, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error waiting for file batch: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// UploadFileBatch uploads local files through the files API, attaches them to
+// a vector store as a file batch, and polls the batch every interval until it
+// is done.
+//
+// Files are uploaded concurrently (see WithUploadConcurrency). If any upload
+// fails, the remaining uploads are canceled and the error is returned; files
+// that were already uploaded are not deleted.
+func (h *Service) UploadFileBatch(
+	ctx context.Context,
+	vectorStoreID string,
+	paths []string,
+	interval time.Duration,
+	opts ...FileBatchOpt,
+) (*FileBatch, error) {
+	req := newFileBatchRequest(nil, opts)
+
+	fileIDs, err := h.uploadFiles(ctx, paths, req.concurrency, req.apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	batch, err := h.CreateFileBatch(ctx, vectorStoreID, fileIDs, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.WaitForFileBatch(ctx, vectorStoreID, batch.ID, interval, opts...)
+}
+
+func (h *Service) uploadFiles(ctx context.Context, paths []string, concurrency int, apiKey string) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		fileIDs  = make([]string, len(paths))
+		sem      = make(chan struct{}, concurrency)
+	)
+
+	for i, path := range paths {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			file, err := h.uploadFile(ctx, path, apiKey)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+
+				return
+			}
+
+			fileIDs[i] = file.ID
+		}()
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("error uploading files: %w", err)
+	}
+
+	return fileIDs, nil
+}
+
+func (h *Service) uploadFile(ctx context.Context, path string, apiKey string) (*files.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer f.Close() //nolint: errcheck // Read-only file.
+
+	file, err := (*files.Service)(h).Upload(ctx, f, filepath.Base(path), files.PurposeAssistants,
+		files.WithUploadAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error uploading %s: %w", path, err)
+	}
+
+	return file, nil
+}
+
+func batchesPath(vectorStoreID string) string {
+	return storePath(vectorStoreID) + "/file_batches"
+}
+
+func batchPath(vectorStoreID, batchID string) string {
+	return batchesPath(vectorStoreID) + "/" + url.PathEscape(batchID)
+}
@@ -0,0 +1,161 @@
+package vectorstores
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// File statuses.
+const (
+	FileStatusInProgress = "in_progress"
+	FileStatusCompleted  = "completed"
+	FileStatusCancelled  = "cancelled"
+	FileStatusFailed     = "failed"
+)
+
+// A File is a file attached to a vector store.
+type File struct {
+	ID               string            `json:"id"`
+	Object           string            `json:"object"`
+	CreatedAt        int64             `json:"created_at"`
+	VectorStoreID    string            `json:"vector_store_id"`
+	Status           string            `json:"status"`
+	UsageBytes       int64             `json:"usage_bytes"`
+	LastError        *FileError        `json:"last_error"`
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"`
+	Attributes       Attributes        `json:"attributes,omitempty"`
+}
+
+// A FileError is the error that caused a file to fail indexing.
+type FileError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Attributes are key-value pairs attached to a file, which can be used to
+// filter searches. Values must be strings, numbers or booleans.
+type Attributes map[string]any
+
+type fileRequest struct {
+	apiKey string
+
+	FileID           string            `json:"file_id,omitempty"`
+	Attributes       Attributes        `json:"attributes,omitempty"`
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"`
+}
+
+// FileOpt is a functional option for configuring a vector store file
+// request.
+type FileOpt func(*fileRequest)
+
+// WithAttributes sets the attributes of the file.
+func WithAttributes(attributes Attributes) FileOpt {
+	return func(r *fileRequest) {
+		r.Attributes = attributes
+	}
+}
+
+// WithFileChunkingStrategy sets the chunking strategy of the file.
+func WithFileChunkingStrategy(strategy ChunkingStrategy) FileOpt {
+	return func(r *fileRequest) {
+		r.ChunkingStrategy = &strategy
+	}
+}
+
+// WithFileAPIKey sets the API key for the vector store file request.
+func WithFileAPIKey(apiKey string) FileOpt {
+	return func(r *fileRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// A FileListResponse is a page of vector store files.
+type FileListResponse = service.Page[File]
+
+// WithStatusFilter only lists files with the given status.
+func WithStatusFilter(status string) ListOpt {
+	return service.WithListParam("filter", status)
+}
+
+// AddFile attaches an uploaded file to a vector store.
+func (h *Service) AddFile(ctx context.Context, vectorStoreID, fileID string, opts ...FileOpt) (*File, error) {
+	req := fileRequest{FileID: fileID}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	var resp File
+	if err := h.do(ctx, http.MethodPost, filesPath(vectorStoreID), req, req.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// RetrieveFile retrieves a vector store file by its ID.
+func (h *Service) RetrieveFile(ctx context.Context, vectorStoreID, fileID string) (*File, error) {
+	var resp File
+	if err := h.do(ctx, http.MethodGet, filePath(vectorStoreID, fileID), nil, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// UpdateFileAttributes replaces the attributes of a vector store file.
+func (h *Service) UpdateFileAttributes(
+	ctx context.Context,
+	vectorStoreID string,
+	fileID string,
+	attributes Attributes,
+) (*File, error) {
+	req := fileRequest{Attributes: attributes}
+
+	var resp File
+	if err := h.do(ctx, http.MethodPost, filePath(vectorStoreID, fileID), req, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// RemoveFile detaches a file from a vector store. The file itself is not
+// deleted.
+func (h *Service) RemoveFile(ctx context.Context, vectorStoreID, fileID string) (*DeleteResponse, error) {
+	var resp DeleteResponse
+	if err := h.do(ctx, http.MethodDelete, filePath(vectorStoreID, fileID), nil, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListFiles lists a page of the files of a vector store.
+func (h *Service) ListFiles(ctx context.Context, vectorStoreID string, opts ...ListOpt) (*FileListResponse, error) {
+	resp, err := service.ListPage[File](ctx, &h.Client, filesPath(vectorStoreID), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing vector store files: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAllFiles returns an iterator over every file of a vector store,
+// following pagination cursors.
+func (h *Service) ListAllFiles(ctx context.Context, vectorStoreID string, opts ...ListOpt) iter.Seq2[File, error] {
+	return service.NewPager[File](&h.Client, filesPath(vectorStoreID), nil, opts...).All(ctx)
+}
+
+func filesPath(vectorStoreID string) string {
+	return storePath(vectorStoreID) + "/files"
+}
+
+func filePath(vectorStoreID, fileID string) string {
+	return filesPath(vectorStoreID) + "/" + url.PathEscape(fileID)
+}
@@ -0,0 +1,206 @@
+package vectorstores
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Filter types.
+const (
+	FilterEq  = "eq"
+	FilterNe  = "ne"
+	FilterGt  = "gt"
+	FilterGte = "gte"
+	FilterLt  = "lt"
+	FilterLte = "lte"
+	FilterAnd = "and"
+	FilterOr  = "or"
+)
+
+// A Filter filters search results by file attributes.
+//
+// It is either a comparison of the attribute Key to Value, or a compound of
+// Filters. Use the constructors (Eq, And, and so on) to create filters.
+type Filter struct {
+	Type    string   `json:"type"`
+	Key     string   `json:"key,omitempty"`
+	Value   any      `json:"value,omitempty"`
+	Filters []Filter `json:"filters,omitempty"`
+}
+
+// Eq matches files whose attribute key equals value.
+func Eq(key string, value any) Filter {
+	return Filter{Type: FilterEq, Key: key, Value: value}
+}
+
+// Ne matches files whose attribute key does not equal value.
+func Ne(key string, value any) Filter {
+	return Filter{Type: FilterNe, Key: key, Value: value}
+}
+
+// Gt matches files whose attribute key is greater than value.
+func Gt(key string, value any) Filter {
+	return Filter{Type: FilterGt, Key: key, Value: value}
+}
+
+// Gte matches files whose attribute key is greater than or equal to value.
+func Gte(key string, value any) Filter {
+	return Filter{Type: FilterGte, Key: key, Value: value}
+}
+
+// Lt matches files whose attribute key is less than value.
+func Lt(key string, value any) Filter {
+	return Filter{Type: FilterLt, Key: key, Value: value}
+}
+
+// Lte matches files whose attribute key is less than or equal to value.
+func Lte(key string, value any) Filter {
+	return Filter{Type: FilterLte, Key: key, Value: value}
+}
+
+// And matches files that match every filter.
+func And(filters ...Filter) Filter {
+	return Filter{Type: FilterAnd, Filters: filters}
+}
+
+// Or matches files that match any filter.
+func Or(filters ...Filter) Filter {
+	return Filter{Type: FilterOr, Filters: filters}
+}
+
+// RankingOptions configure how search results are ranked.
+type RankingOptions struct {
+	Ranker         string   `json:"ranker,omitempty"`
+	ScoreThreshold *float64 `json:"score_threshold,omitempty"`
+}
+
+type searchRequest struct {
+	apiKey string
+
+	Query          string          `json:"query"`
+	Filters        *Filter         `json:"filters,omitempty"`
+	MaxNumResults  *int            `json:"max_num_results,omitempty"`
+	RankingOptions *RankingOptions `json:"ranking_options,omitempty"`
+	RewriteQuery   *bool           `json:"rewrite_query,omitempty"`
+}
+
+// SearchOpt is a functional option for configuring a search request.
+type SearchOpt func(*searchRequest)
+
+// WithFilter filters search results by file attributes.
+func WithFilter(filter Filter) SearchOpt {
+	return func(r *searchRequest) {
+		r.Filters = &filter
+	}
+}
+
+// WithMaxNumResults sets the maximum number of search results (1 to 50).
+func WithMaxNumResults(maxNumResults int) SearchOpt {
+	return func(r *searchRequest) {
+		r.MaxNumResults = &maxNumResults
+	}
+}
+
+// WithRankingOptions sets the ranking options of the search.
+func WithRankingOptions(options RankingOptions) SearchOpt {
+	return func(r *searchRequest) {
+		r.RankingOptions = &options
+	}
+}
+
+// WithRewriteQuery sets whether the query is rewritten for vector search.
+func WithRewriteQuery(rewrite bool) SearchOpt {
+	return func(r *searchRequest) {
+		r.RewriteQuery = &rewrite
+	}
+}
+
+// WithSearchAPIKey sets the API key for the search request.
+func WithSearchAPIKey(apiKey string) SearchOpt {
+	return func(r *searchRequest) {
+		r.apiKey = apiKey
+	}
+}
+
+// A SearchResponse is a page of vector store search results.
+type SearchResponse struct {
+	Object      string         `json:"object"`
+	SearchQuery SearchQuery    `json:"search_query"`
+	Data        []SearchResult `json:"data"`
+	HasMore     bool           `json:"has_more"`
+	NextPage    *string        `json:"next_page"`
+}
+
+// A SearchQuery is the query (or rewritten queries) a search was run with.
+type SearchQuery []string
+
+// UnmarshalJSON implements json.Unmarshaler, accepting a single string or a
+// list of strings.
+func (q *SearchQuery) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*q = SearchQuery{s}
+
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return fmt.Errorf("error unmarshaling search query: %w", err)
+	}
+
+	*q = list
+
+	return nil
+}
+
+// A SearchResult is a chunk of a file that matched a search.
+type SearchResult struct {
+	FileID     string          `json:"file_id"`
+	Filename   string          `json:"filename"`
+	Score      float64         `json:"score"`
+	Attributes Attributes      `json:"attributes,omitempty"`
+	Content    []SearchContent `json:"content"`
+}
+
+// Text returns the concatenated text content of the result.
+func (r *SearchResult) Text() string {
+	var text string
+
+	for _, c := range r.Content {
+		if c.Type == "text" {
+			text += c.Text
+		}
+	}
+
+	return text
+}
+
+// A SearchContent is a content part of a search result.
+type SearchContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Search searches a vector store for chunks relevant to a query.
+func (h *Service) Search(
+	ctx context.Context,
+	vectorStoreID string,
+	query string,
+	opts ...SearchOpt,
+) (*SearchResponse, error) {
+	req := searchRequest{Query: query}
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	var resp SearchResponse
+	if err := h.do(ctx, http.MethodPost, storePath(vectorStoreID)+"/search", req, req.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
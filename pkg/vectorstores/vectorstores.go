@@ -0,0 +1,254 @@
+// Package vectorstores provides a vector stores client for the OpenAI API.
+//
+// Vector stores index files for the file search tool of the responses and
+// assistants APIs, and can be searched directly.
+package vectorstores
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// Vector store statuses.
+const (
+	StatusExpired    = "expired"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+)
+
+// A VectorStore is a collection of indexed files.
+type VectorStore struct {
+	ID           string            `json:"id"`
+	Object       string            `json:"object"`
+	CreatedAt    int64             `json:"created_at"`
+	Name         string            `json:"name"`
+	UsageBytes   int64             `json:"usage_bytes"`
+	FileCounts   FileCounts        `json:"file_counts"`
+	Status       string            `json:"status"`
+	ExpiresAfter *ExpiresAfter     `json:"expires_after,omitempty"`
+	ExpiresAt    *int64            `json:"expires_at,omitempty"`
+	LastActiveAt *int64            `json:"last_active_at,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+}
+
+// FileCounts counts the files of a vector store or file batch by status.
+type FileCounts struct {
+	InProgress int `json:"in_progress"`
+	Completed  int `json:"completed"`
+	Failed     int `json:"failed"`
+	Cancelled  int `json:"cancelled"`
+	Total      int `json:"total"`
+}
+
+// ExpiresAfter is the expiration policy of a vector store.
+type ExpiresAfter struct {
+	Anchor string `json:"anchor"`
+	Days   int    `json:"days"`
+}
+
+// A ChunkingStrategy configures how files are split into chunks.
+type ChunkingStrategy struct {
+	Type   string          `json:"type"`
+	Static *StaticChunking `json:"static,omitempty"`
+}
+
+// StaticChunking configures fixed-size chunks.
+type StaticChunking struct {
+	MaxChunkSizeTokens int `json:"max_chunk_size_tokens"`
+	ChunkOverlapTokens int `json:"chunk_overlap_tokens"`
+}
+
+// AutoChunking returns the default chunking strategy.
+func AutoChunking() ChunkingStrategy {
+	return ChunkingStrategy{Type: "auto"}
+}
+
+// NewStaticChunking returns a chunking strategy with chunks of at most
+// maxChunkSize tokens, overlapping by overlap tokens.
+func NewStaticChunking(maxChunkSize, overlap int) ChunkingStrategy {
+	return ChunkingStrategy{
+		Type:   "static",
+		Static: &StaticChunking{MaxChunkSizeTokens: maxChunkSize, ChunkOverlapTokens: overlap},
+	}
+}
+
+type request struct {
+	apiKey string
+
+	Name             *string           `json:"name,omitempty"`
+	FileIDs          []string          `json:"file_ids,omitempty"`
+	ChunkingStrategy *ChunkingStrategy `json:"chunking_strategy,omitempty"`
+	ExpiresAfter     *ExpiresAfter     `json:"expires_after,omitempty"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+}
+
+// CreateOpt is a functional option for configuring a vector store creation or
+// update request.
+type CreateOpt func(*request)
+
+// WithName sets the name of the vector store.
+func WithName(name string) CreateOpt {
+	return func(r *request) {
+		r.Name = &name
+	}
+}
+
+// WithFileIDs sets the files to add to the vector store when it is created.
+func WithFileIDs(fileIDs ...string) CreateOpt {
+	return func(r *request) {
+		r.FileIDs = fileIDs
+	}
+}
+
+// WithChunkingStrategy sets the chunking strategy of the files added when the
+// vector store is created.
+func WithChunkingStrategy(strategy ChunkingStrategy) CreateOpt {
+	return func(r *request) {
+		r.ChunkingStrategy = &strategy
+	}
+}
+
+// WithExpiresAfter expires the vector store the given number of days after it
+// was last active.
+func WithExpiresAfter(days int) CreateOpt {
+	return func(r *request) {
+		r.ExpiresAfter = &ExpiresAfter{Anchor: "last_active_at", Days: days}
+	}
+}
+
+// WithMetadata sets the metadata of the vector store.
+func WithMetadata(metadata map[string]string) CreateOpt {
+	return func(r *request) {
+		r.Metadata = metadata
+	}
+}
+
+// WithAPIKey sets the API key for the vector store request.
+func WithAPIKey(apiKey string) CreateOpt {
+	return func(r *request) {
+		r.apiKey = apiKey
+	}
+}
+
+// A DeleteResponse is a response to a deletion request.
+type DeleteResponse struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Deleted bool   `json:"deleted"`
+}
+
+// A ListResponse is a page of vector stores.
+type ListResponse = service.Page[VectorStore]
+
+// ListOpt is a functional option for configuring a list request.
+type ListOpt = service.ListOpt
+
+// WithLimit sets the maximum number of objects in a page.
+func WithLimit(limit int) ListOpt {
+	return service.WithLimit(limit)
+}
+
+// WithOrder sets the order ("asc" or "desc") of the listed objects.
+func WithOrder(order string) ListOpt {
+	return service.WithOrder(order)
+}
+
+// WithAfter sets the cursor to list objects after, which is the LastID of the
+// previous page.
+func WithAfter(after string) ListOpt {
+	return service.WithAfter(after)
+}
+
+// Service is a service wrapping an OpenAI-compatible vector stores API.
+type Service service.Service
+
+// do performs a request, decoding the response into v.
+func (h *Service) do(ctx context.Context, method, path string, body any, apiKey string, v any) error {
+	httpReq, err := h.Client.NewRequestWithContext(ctx, method, path, body, service.WithAPIKey(apiKey))
+	if err != nil {
+		return fmt.Errorf("error creating vector store request: %w", err)
+	}
+
+	if _, err := h.Client.Do(httpReq, v); err != nil { //nolint: bodyclose // False positive.
+		return fmt.Errorf("error performing vector store request: %w", err)
+	}
+
+	return nil
+}
+
+// Create creates a vector store.
+func (h *Service) Create(ctx context.Context, opts ...CreateOpt) (*VectorStore, error) {
+	var req request
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	var resp VectorStore
+	if err := h.do(ctx, http.MethodPost, "/vector_stores", req, req.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Retrieve retrieves a vector store by its ID.
+func (h *Service) Retrieve(ctx context.Context, id string) (*VectorStore, error) {
+	var resp VectorStore
+	if err := h.do(ctx, http.MethodGet, storePath(id), nil, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Update updates a vector store's name, expiration policy or metadata.
+func (h *Service) Update(ctx context.Context, id string, opts ...CreateOpt) (*VectorStore, error) {
+	var req request
+
+	for _, opt := range opts {
+		opt(&req)
+	}
+
+	var resp VectorStore
+	if err := h.do(ctx, http.MethodPost, storePath(id), req, req.apiKey, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Delete deletes a vector store by its ID. Its files are not deleted.
+func (h *Service) Delete(ctx context.Context, id string) (*DeleteResponse, error) {
+	var resp DeleteResponse
+	if err := h.do(ctx, http.MethodDelete, storePath(id), nil, "", &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// List lists a page of vector stores.
+func (h *Service) List(ctx context.Context, opts ...ListOpt) (*ListResponse, error) {
+	resp, err := service.ListPage[VectorStore](ctx, &h.Client, "/vector_stores", opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error listing vector stores: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ListAll returns an iterator over every vector store, following pagination
+// cursors.
+func (h *Service) ListAll(ctx context.Context, opts ...ListOpt) iter.Seq2[VectorStore, error] {
+	return service.NewPager[VectorStore](&h.Client, "/vector_stores", nil, opts...).All(ctx)
+}
+
+func storePath(id string) string {
+	return "/vector_stores/" + url.PathEscape(id)
+}
@@ -0,0 +1,277 @@
+package vectorstores_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/internal/service"
+	"github.com/jclem/openai-go/pkg/vectorstores"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newResponse(status int, body string) *http.Response {
+	r := &http.Response{}
+	r.StatusCode = status
+	r.Body = httptesting.NewTestBody(strings.NewReader(body))
+
+	return r
+}
+
+func TestHTTPClient_CreateVectorStore(t *testing.T) {
+	t.Parallel()
+
+	var body map[string]any
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		return newResponse(http.StatusOK, `{"id": "vs_1", "status": "in_progress", "file_counts": {"total": 1}}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*vectorstores.Service)(svc)
+
+	vs, err := c.Create(
+		context.Background(),
+		vectorstores.WithName("docs"),
+		vectorstores.WithFileIDs("file_1"),
+		vectorstores.WithChunkingStrategy(vectorstores.NewStaticChunking(800, 400)),
+		vectorstores.WithExpiresAfter(7),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"name":     "docs",
+		"file_ids": []any{"file_1"},
+		"chunking_strategy": map[string]any{
+			"type":   "static",
+			"static": map[string]any{"max_chunk_size_tokens": float64(800), "chunk_overlap_tokens": float64(400)},
+		},
+		"expires_after": map[string]any{"anchor": "last_active_at", "days": float64(7)},
+	}, body)
+	assert.Equal(t, "vs_1", vs.ID)
+	assert.Equal(t, 1, vs.FileCounts.Total)
+}
+
+func TestHTTPClient_SearchVectorStore(t *testing.T) {
+	t.Parallel()
+
+	var (
+		path string
+		body map[string]any
+	)
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		path = req.URL.Path
+
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		return newResponse(http.StatusOK, `{
+			"object": "vector_store.search_results.page",
+			"search_query": "return policy",
+			"data": [{"file_id": "file_1", "filename": "policy.md", "score": 0.9,
+				"attributes": {"region": "us"},
+				"content": [{"type": "text", "text": "Returns are "}, {"type": "text", "text": "free."}]}],
+			"has_more": false
+		}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*vectorstores.Service)(svc)
+
+	resp, err := c.Search(
+		context.Background(),
+		"vs_1",
+		"return policy",
+		vectorstores.WithFilter(vectorstores.And(
+			vectorstores.Eq("region", "us"),
+			vectorstores.Or(vectorstores.Gte("year", 2024), vectorstores.Eq("archived", false)),
+		)),
+		vectorstores.WithMaxNumResults(5),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/v1/vector_stores/vs_1/search", path)
+	assert.Equal(t, map[string]any{
+		"query": "return policy",
+		"filters": map[string]any{
+			"type": "and",
+			"filters": []any{
+				map[string]any{"type": "eq", "key": "region", "value": "us"},
+				map[string]any{"type": "or", "filters": []any{
+					map[string]any{"type": "gte", "key": "year", "value": float64(2024)},
+					map[string]any{"type": "eq", "key": "archived", "value": false},
+				}},
+			},
+		},
+		"max_num_results": float64(5),
+	}, body)
+
+	assert.Equal(t, vectorstores.SearchQuery{"return policy"}, resp.SearchQuery)
+	assert.Equal(t, "Returns are free.", resp.Data[0].Text())
+	assert.Equal(t, "us", resp.Data[0].Attributes["region"])
+}
+
+func TestHTTPClient_UploadFileBatch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	paths := make([]string, 3)
+
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("doc%d.txt", i))
+		require.NoError(t, os.WriteFile(paths[i], []byte(fmt.Sprintf("document %d", i)), 0o600))
+	}
+
+	var (
+		mu         sync.Mutex
+		uploaded   = map[string]string{}
+		batchBody  map[string]any
+		uploadN    atomic.Int32
+		retrievesN atomic.Int32
+	)
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case req.URL.Path == "/v1/files":
+			if err := req.ParseMultipartForm(1 << 20); err != nil {
+				return nil, err
+			}
+
+			f, header, err := req.FormFile("file")
+			if err != nil {
+				return nil, err
+			}
+
+			content, err := io.ReadAll(f)
+			if err != nil {
+				return nil, err
+			}
+
+			id := fmt.Sprintf("file_%d", uploadN.Add(1))
+
+			mu.Lock()
+			uploaded[header.Filename] = string(content)
+			mu.Unlock()
+
+			return newResponse(http.StatusOK, fmt.Sprintf(`{"id": %q, "purpose": %q}`,
+				id, req.FormValue("purpose"))), nil
+		case req.Method == http.MethodPost && req.URL.Path == "/v1/vector_stores/vs_1/file_batches":
+			if err := json.NewDecoder(req.Body).Decode(&batchBody); err != nil {
+				return nil, err
+			}
+
+			return newResponse(http.StatusOK, `{"id": "vsfb_1", "status": "in_progress"}`), nil
+		case req.URL.Path == "/v1/vector_stores/vs_1/file_batches/vsfb_1":
+			if retrievesN.Add(1) < 2 {
+				return newResponse(http.StatusOK, `{"id": "vsfb_1", "status": "in_progress"}`), nil
+			}
+
+			return newResponse(http.StatusOK, `{"id": "vsfb_1", "status": "completed",
+				"file_counts": {"completed": 3, "total": 3}}`), nil
+		}
+
+		return newResponse(http.StatusNotFound, `{}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*vectorstores.Service)(svc)
+
+	batch, err := c.UploadFileBatch(
+		context.Background(),
+		"vs_1",
+		paths,
+		time.Millisecond,
+		vectorstores.WithBatchAttributes(vectorstores.Attributes{"source": "docs"}),
+		vectorstores.WithUploadConcurrency(2),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, vectorstores.FileStatusCompleted, batch.Status)
+	assert.Equal(t, 3, batch.FileCounts.Completed)
+	assert.Equal(t, map[string]string{
+		"doc0.txt": "document 0",
+		"doc1.txt": "document 1",
+		"doc2.txt": "document 2",
+	}, uploaded)
+	assert.ElementsMatch(t, []any{"file_1", "file_2", "file_3"}, batchBody["file_ids"])
+	assert.Equal(t, map[string]any{"source": "docs"}, batchBody["attributes"])
+}
+
+func TestHTTPClient_UploadFileBatchFailure(t *testing.T) {
+	t.Parallel()
+
+	doer := httptesting.DoerFunc(func(*http.Request) (*http.Response, error) {
+		return newResponse(http.StatusInternalServerError, `{}`), nil
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*vectorstores.Service)(svc)
+
+	path := filepath.Join(t.TempDir(), "doc.txt")
+	require.NoError(t, os.WriteFile(path, []byte("doc"), 0o600))
+
+	_, err := c.UploadFileBatch(context.Background(), "vs_1", []string{path}, time.Millisecond)
+
+	var statusErr service.UnexpectedStatusCodeError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusInternalServerError, statusErr.Actual)
+}
+
+func TestHTTPClient_UploadFileBatchAPIKey(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		auths = map[string]string{}
+	)
+
+	doer := httptesting.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		auths[req.Method+" "+req.URL.Path] = req.Header.Get("Authorization")
+		mu.Unlock()
+
+		switch req.URL.Path {
+		case "/v1/files":
+			return newResponse(http.StatusOK, `{"id": "file_1"}`), nil
+		case "/v1/vector_stores/vs_1/file_batches":
+			return newResponse(http.StatusOK, `{"id": "vsfb_1", "status": "in_progress"}`), nil
+		default:
+			return newResponse(http.StatusOK, `{"id": "vsfb_1", "status": "completed"}`), nil
+		}
+	})
+
+	svc := service.New(openai.DefaultBaseURL, "api-key", doer)
+	c := (*vectorstores.Service)(svc)
+
+	path := filepath.Join(t.TempDir(), "doc.txt")
+	require.NoError(t, os.WriteFile(path, []byte("doc"), 0o600))
+
+	// A zero interval falls back to DefaultPollInterval rather than panicking.
+	_, err := c.UploadFileBatch(context.Background(), "vs_1", []string{path}, 0,
+		vectorstores.WithBatchAPIKey("batch-key"))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"POST /v1/files": "Bearer batch-key",
+		"POST /v1/vector_stores/vs_1/file_batches":       "Bearer batch-key",
+		"GET /v1/vector_stores/vs_1/file_batches/vsfb_1": "Bearer batch-key",
+	}, auths)
+}
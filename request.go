@@ -0,0 +1,72 @@
+package openai
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/jclem/openai-go/internal/service"
+)
+
+// A RequestOption customizes the requests made for a single call, as an escape
+// hatch for headers, query parameters and body fields this library does not
+// model.
+//
+// Request options are carried by a context (see WithRequestOptions), so every
+// service method accepts them.
+type RequestOption func(*service.CallOptions)
+
+// WithRequestOptions returns a copy of ctx carrying request options. Calls
+// made with the returned context apply them to their requests, in addition to
+// any request options ctx already carries.
+//
+//	ctx = openai.WithRequestOptions(ctx,
+//		openai.WithRequestHeader("X-Gateway-Route", "eu"),
+//		openai.WithExtraBody("service_tier", "flex"),
+//	)
+//	comp, err := client.Chat.CreateCompletion(ctx, model, messages)
+func WithRequestOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	return service.ContextWithCallOptions(ctx, func(o *service.CallOptions) {
+		for _, opt := range opts {
+			opt(o)
+		}
+	})
+}
+
+// WithRequestHeader sets a header on the request, replacing any value set by
+// the client or the service.
+func WithRequestHeader(key, value string) RequestOption {
+	return func(o *service.CallOptions) {
+		if o.Header == nil {
+			o.Header = make(http.Header)
+		}
+
+		o.Header.Set(key, value)
+	}
+}
+
+// WithQueryParam adds a query parameter to the request.
+func WithQueryParam(key, value string) RequestOption {
+	return func(o *service.CallOptions) {
+		if o.Query == nil {
+			o.Query = make(url.Values)
+		}
+
+		o.Query.Add(key, value)
+	}
+}
+
+// WithExtraBody sets a field in the request body, replacing the field if the
+// body already has it. The value must be JSON-serializable.
+//
+// It is ignored for requests without a body, such as GET requests. In
+// multipart/form-data bodies, non-string values are encoded as JSON.
+func WithExtraBody(key string, value any) RequestOption {
+	return func(o *service.CallOptions) {
+		if o.ExtraBody == nil {
+			o.ExtraBody = make(map[string]any)
+		}
+
+		o.ExtraBody[key] = value
+	}
+}
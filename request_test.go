@@ -0,0 +1,203 @@
+package openai_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jclem/openai-go"
+	"github.com/jclem/openai-go/internal/httptesting"
+	"github.com/jclem/openai-go/pkg/chat"
+	"github.com/jclem/openai-go/pkg/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Headers(t *testing.T) {
+	t.Parallel()
+
+	var reqs []*http.Request
+
+	c := openai.NewClient(
+		openai.WithKey("api-key"),
+		openai.WithOrganization("org-123"),
+		openai.WithProject("proj-456"),
+		openai.WithHeader("X-Gateway-Route", "us"),
+		openai.WithDoer(recordingDoer(&reqs, `{"object":"list","data":[]}`)),
+	)
+
+	_, err := c.Models.List(context.Background())
+	require.NoError(t, err)
+
+	ctx := openai.WithRequestOptions(context.Background(), openai.WithRequestHeader("X-Gateway-Route", "eu"))
+	_, err = c.Models.List(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, reqs, 2)
+	assert.Equal(t, "org-123", reqs[0].Header.Get("OpenAI-Organization"))
+	assert.Equal(t, "proj-456", reqs[0].Header.Get("OpenAI-Project"))
+	assert.Equal(t, "us", reqs[0].Header.Get("X-Gateway-Route"))
+	assert.Equal(t, "eu", reqs[1].Header.Get("X-Gateway-Route"))
+	assert.Equal(t, "org-123", reqs[1].Header.Get("OpenAI-Organization"))
+}
+
+func TestWithRequestOptions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		req  *http.Request
+		body map[string]any
+	)
+
+	doer := func(r *http.Request) (*http.Response, error) {
+		req = r
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		return recordingDoer(&[]*http.Request{}, `{}`)(r)
+	}
+
+	c := openai.NewClient(openai.WithKey("api-key"), openai.WithDoer(httptesting.DoerFunc(doer)))
+
+	ctx := openai.WithRequestOptions(context.Background(),
+		openai.WithRequestHeader("X-Stainless-Lang", "go"),
+		openai.WithQueryParam("trace", "1"),
+		openai.WithExtraBody("service_tier", "flex"))
+
+	// Options accumulate across calls.
+	ctx = openai.WithRequestOptions(ctx,
+		openai.WithExtraBody("temperature", 0.2),
+		openai.WithExtraBody("metadata", map[string]string{"a": "b"}))
+
+	_, err := c.Chat.CreateCompletion(ctx, "gpt-4",
+		[]chat.Message{chat.NewMessage("user", chat.WithMessageContent("Hi"))},
+		chat.WithTemperature(0.9))
+	require.NoError(t, err)
+
+	assert.Equal(t, "go", req.Header.Get("X-Stainless-Lang"))
+	assert.Equal(t, "Bearer api-key", req.Header.Get("Authorization"))
+	assert.Equal(t, "1", req.URL.Query().Get("trace"))
+	assert.Equal(t, "gpt-4", body["model"])
+	assert.Equal(t, "flex", body["service_tier"])
+	assert.InDelta(t, 0.2, body["temperature"], 0)
+	assert.Equal(t, map[string]any{"a": "b"}, body["metadata"])
+	assert.NotEmpty(t, body["messages"])
+}
+
+func TestWithRequestOptions_Form(t *testing.T) {
+	t.Parallel()
+
+	var fields map[string][]string
+
+	doer := func(r *http.Request) (*http.Response, error) {
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+
+		fields = r.MultipartForm.Value
+
+		return recordingDoer(&[]*http.Request{}, `{}`)(r)
+	}
+
+	c := openai.NewClient(openai.WithKey("api-key"), openai.WithDoer(httptesting.DoerFunc(doer)))
+
+	ctx := openai.WithRequestOptions(context.Background(),
+		openai.WithExtraBody("expires_after", map[string]any{"anchor": "created_at", "seconds": 3600}),
+		openai.WithExtraBody("note", "hello"),
+		openai.WithExtraBody("purpose", "fine-tune"))
+
+	_, err := c.Files.Upload(ctx, strings.NewReader("data"), "data.jsonl", files.PurposeBatch)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"fine-tune"}, fields["purpose"])
+	assert.Equal(t, []string{"hello"}, fields["note"])
+	require.Len(t, fields["expires_after"], 1)
+	assert.JSONEq(t, `{"anchor":"created_at","seconds":3600}`, fields["expires_after"][0])
+}
+
+func TestWithRequestOptions_NoBody(t *testing.T) {
+	t.Parallel()
+
+	var req *http.Request
+
+	doer := func(r *http.Request) (*http.Response, error) {
+		req = r
+
+		return recordingDoer(&[]*http.Request{}, `{}`)(r)
+	}
+
+	c := openai.NewClient(openai.WithKey("api-key"), openai.WithDoer(httptesting.DoerFunc(doer)))
+
+	ctx := openai.WithRequestOptions(context.Background(), openai.WithExtraBody("ignored", true))
+	_, err := c.Models.Retrieve(ctx, "gpt-4")
+	require.NoError(t, err)
+
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.Empty(t, b)
+	}
+}
+
+func TestWithRequestOptions_QueryNotShared(t *testing.T) {
+	t.Parallel()
+
+	var reqs []*http.Request
+
+	c := openai.NewClient(openai.WithKey("api-key"),
+		openai.WithDoer(recordingDoer(&reqs, `{"object":"list","data":[]}`)))
+
+	base := openai.WithRequestOptions(context.Background(),
+		openai.WithQueryParam("tag", "1"),
+		openai.WithQueryParam("tag", "2"),
+		openai.WithQueryParam("tag", "3"))
+
+	// Contexts derived from the same parent don't share query values.
+	a := openai.WithRequestOptions(base, openai.WithQueryParam("tag", "a"))
+	_ = openai.WithRequestOptions(base, openai.WithQueryParam("tag", "b"))
+
+	_, err := c.Models.List(a)
+	require.NoError(t, err)
+
+	require.Len(t, reqs, 1)
+	assert.Equal(t, []string{"1", "2", "3", "a"}, reqs[0].URL.Query()["tag"])
+}
+
+func TestWithRequestOptions_MiddlewareBody(t *testing.T) {
+	t.Parallel()
+
+	var (
+		body    map[string]any
+		reqBody any
+	)
+
+	doer := func(r *http.Request) (*http.Response, error) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		return recordingDoer(&[]*http.Request{}, `{}`)(r)
+	}
+
+	c := openai.NewClient(openai.WithKey("api-key"),
+		openai.WithDoer(httptesting.DoerFunc(doer)),
+		openai.WithMiddleware(func(next openai.Handler) openai.Handler {
+			return func(req *openai.Request) (*openai.Response, error) {
+				reqBody = req.Body
+
+				return next(req)
+			}
+		}))
+
+	ctx := openai.WithRequestOptions(context.Background(), openai.WithExtraBody("service_tier", "flex"))
+
+	_, err := c.Embeddings.Create(ctx, "text-embedding-3-small", []string{"Hi"})
+	require.NoError(t, err)
+
+	// Middleware sees the typed request body, and the extra fields are merged
+	// in when it is encoded.
+	require.NotNil(t, reqBody)
+
+	_, merged := reqBody.(map[string]json.RawMessage)
+	assert.False(t, merged)
+	assert.Equal(t, "flex", body["service_tier"])
+	assert.Equal(t, "text-embedding-3-small", body["model"])
+}